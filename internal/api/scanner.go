@@ -31,27 +31,31 @@ type ScanOptions struct {
 	Timeout   float64  `json:"timeout"`    // 超时时间
 
 	// 高级设置
-	RealTimeStatus bool `json:"real_time_status"` // 实时状态显示
-	Headless       bool `json:"headless"`         // 无头模式
+	RealTimeStatus bool   `json:"real_time_status"` // 实时状态显示
+	Headless       bool   `json:"headless"`         // 无头模式
+	ScanID         string `json:"scan_id"`          // 扫描ID，为空时自动生成
 }
 
 // ScanResult 扫描结果
 type ScanResult struct {
-	URL            string            `json:"url"`             // 完整URL
-	Path           string            `json:"path"`            // 扫描路径
-	StatusCode     int               `json:"status_code"`     // HTTP状态码
-	ContentLength  int64             `json:"content_length"`  // 内容长度
-	Title          string            `json:"title"`           // 页面标题
-	Redirect       string            `json:"redirect"`        // 重定向URL
-	Headers        map[string]string `json:"headers"`         // 响应头
-	Body           string            `json:"body"`            // 响应体
-	IsDirectory    bool              `json:"is_directory"`    // 是否为目录
-	RecursionLevel int               `json:"recursion_level"` // 递归层级
-	Error          string            `json:"error,omitempty"` // 错误信息
+	URL            string              `json:"url"`                       // 完整URL
+	Path           string              `json:"path"`                      // 扫描路径
+	StatusCode     int                 `json:"status_code"`               // HTTP状态码
+	ContentLength  int64               `json:"content_length"`            // 内容长度
+	Title          string              `json:"title"`                     // 页面标题
+	Redirect       string              `json:"redirect"`                  // 重定向URL
+	Headers        map[string][]string `json:"headers"`                   // 响应头（保留重复的头，如多个Set-Cookie）
+	Body           string              `json:"body"`                      // 响应体
+	IsDirectory    bool                `json:"is_directory"`              // 是否为目录
+	RecursionLevel int                 `json:"recursion_level"`           // 递归层级
+	AllowedMethods []string            `json:"allowed_methods,omitempty"` // 允许的HTTP方法（--probe-methods）
+	Blocked        bool                `json:"blocked,omitempty"`         // 疑似被WAF拦截（--detect-waf）
+	Error          string              `json:"error,omitempty"`           // 错误信息
 }
 
 // ScanResponse 扫描响应
 type ScanResponse struct {
+	ScanID        string       `json:"scan_id"`        // 扫描ID
 	Results       []ScanResult `json:"results"`        // 扫描结果
 	TotalScanned  int          `json:"total_scanned"`  // 总扫描数
 	TotalFound    int          `json:"total_found"`    // 总发现数
@@ -115,6 +119,7 @@ func Scan(options ScanOptions) (*ScanResponse, error) {
 
 	// 构建响应
 	response := buildResponse(apiResults, results)
+	response.ScanID = scanner.ScanID()
 
 	return response, nil
 }
@@ -176,6 +181,7 @@ func createConfig(options *ScanOptions) *config.Config {
 	cfg := &config.Config{
 		General: config.GeneralConfig{
 			Threads: options.Threads,
+			ScanID:  options.ScanID,
 		},
 		Dictionary: config.DictionaryConfig{
 			Wordlists: options.Wordlists,
@@ -268,12 +274,12 @@ func convertSingleResult(result report.ScanResult) (ScanResult, error) {
 		}
 	}()
 
-	// 转换响应头
-	headers := make(map[string]string)
+	// 转换响应头，保留多值头的全部取值
+	headers := make(map[string][]string)
 	if result.Headers != nil {
 		for key, values := range result.Headers {
 			if len(values) > 0 {
-				headers[key] = values[0]
+				headers[key] = append([]string(nil), values...)
 			}
 		}
 	}
@@ -289,6 +295,8 @@ func convertSingleResult(result report.ScanResult) (ScanResult, error) {
 		Body:           result.Body,
 		IsDirectory:    result.IsDirectory,
 		RecursionLevel: result.RecursionLevel,
+		AllowedMethods: result.AllowedMethods,
+		Blocked:        result.Blocked,
 		Error:          "",
 	}
 
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dirsearch-go/internal/report"
+)
+
+func TestConvertSingleResultKeepsMultiValuedHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "xyz"})
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/login")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+
+	result, err := convertSingleResult(report.ScanResult{
+		URL:        server.URL + "/",
+		Path:       "login",
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+	})
+	if err != nil {
+		t.Fatalf("convertSingleResult failed: %v", err)
+	}
+
+	cookies := result.Headers["Set-Cookie"]
+	if len(cookies) != 2 || cookies[0] != "session=abc" || cookies[1] != "csrf=xyz" {
+		t.Errorf("Set-Cookie = %v, want both cookies preserved", cookies)
+	}
+	if got := result.Headers["Content-Type"]; len(got) != 1 || got[0] != "text/html" {
+		t.Errorf("Content-Type = %v, want [text/html]", got)
+	}
+
+	// JSON输出中同样保留全部取值
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded ScanResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded.Headers["Set-Cookie"]) != 2 {
+		t.Errorf("Decoded Set-Cookie = %v, want 2 values", decoded.Headers["Set-Cookie"])
+	}
+}
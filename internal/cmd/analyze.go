@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/dictionary"
+
+	"github.com/spf13/cobra"
+)
+
+// analyzeCmd 字典分析子命令
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze wordlists without sending any requests",
+	Long: `Load the configured wordlists and print statistics: total entries,
+duplicates, entries with extensions, longest/shortest entry and the number of
+paths that would be generated with the current extension/prefix/suffix settings.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(wordlists) == 0 && wordlistURL == "" {
+			return fmt.Errorf("no wordlist was provided, try using -w <wordlist>")
+		}
+
+		cfg := config.GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("failed to get configuration")
+		}
+		updateConfigFromFlags(cfg)
+		if err := config.Validate(cfg); err != nil {
+			return fmt.Errorf("invalid options:\n%w", err)
+		}
+
+		dict, err := dictionary.NewDictionary(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to load dictionary: %w", err)
+		}
+
+		stats, err := dict.Analyze()
+		if err != nil {
+			return fmt.Errorf("failed to analyze dictionary: %w", err)
+		}
+
+		displayWordlistStats(stats)
+		return nil
+	},
+}
+
+// init 注册字典分析子命令
+func init() {
+	addDictionaryFlags(analyzeCmd)
+	rootCmd.AddCommand(analyzeCmd)
+}
+
+// displayWordlistStats 显示字典统计信息
+func displayWordlistStats(stats *dictionary.WordlistStats) {
+	fmt.Println("\nWordlist Analysis:")
+	fmt.Println("==================")
+	fmt.Printf("Total entries:        %d\n", stats.TotalEntries)
+	fmt.Printf("Unique entries:       %d\n", stats.UniqueEntries)
+	fmt.Printf("Duplicates:           %d\n", stats.Duplicates)
+	fmt.Printf("With extensions:      %d\n", stats.WithExtensions)
+	fmt.Printf("Directories:          %d\n", stats.Directories)
+	fmt.Printf("Longest entry:        %s (%d)\n", stats.Longest, len(stats.Longest))
+	fmt.Printf("Shortest entry:       %s (%d)\n", stats.Shortest, len(stats.Shortest))
+	fmt.Printf("Generated paths:      %d\n", stats.GeneratedPaths)
+}
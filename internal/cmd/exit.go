@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+
+	"dirsearch-go/internal/scanner"
+)
+
+// 进程退出码，ExitNoAliveTargets和ExitNoFindings只在--exit-codes时使用，默认这两种情况也以0退出
+const (
+	// ExitFindings 扫描完成并发现了路径
+	ExitFindings = 0
+	// ExitError 参数错误或扫描出错
+	ExitError = 1
+	// ExitNoAliveTargets 域名存活检测后没有可扫描的目标
+	ExitNoAliveTargets = 2
+	// ExitNoFindings 扫描完成但没有发现任何路径
+	ExitNoFindings = 3
+)
+
+// exitCode 命令成功执行后的退出码，由runScanner设置
+var exitCode = ExitFindings
+
+// ExitCode 返回命令成功执行后应使用的退出码，命令返回错误时应使用ExitError
+func ExitCode() int {
+	return exitCode
+}
+
+// scanAborted 判断扫描是否因--exit-on-error中止，中止时已扫描部分的结果仍需保存
+func scanAborted(err error) bool {
+	return errors.Is(err, scanner.ErrExitOnError)
+}
+
+// noAliveTargets 判断扫描是否因域名存活检测后没有可扫描的目标而结束
+func noAliveTargets(err error) bool {
+	return errors.Is(err, scanner.ErrNoAliveTargets)
+}
+
+// scanExitCode 根据扫描错误和发现的路径数量确定退出码，distinct为false（未启用--exit-codes）时
+// 没有存活目标和没有发现路径都以0退出
+func scanExitCode(findings int, err error, distinct bool) int {
+	switch {
+	case noAliveTargets(err):
+		if distinct {
+			return ExitNoAliveTargets
+		}
+		return ExitFindings
+	case err != nil:
+		return ExitError
+	case findings > 0 || !distinct:
+		return ExitFindings
+	default:
+		return ExitNoFindings
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"dirsearch-go/internal/scanner"
+)
+
+func TestScanExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings int
+		err      error
+		distinct bool
+		expected int
+	}{
+		{name: "没有存活目标", err: scanner.ErrNoAliveTargets, distinct: true, expected: ExitNoAliveTargets},
+		{name: "包装后的没有存活目标", err: fmt.Errorf("scan failed: %w", scanner.ErrNoAliveTargets), distinct: true, expected: ExitNoAliveTargets},
+		{name: "扫描出错", err: errors.New("failed to execute scan"), distinct: true, expected: ExitError},
+		{name: "发现路径", findings: 3, distinct: true, expected: ExitFindings},
+		{name: "没有发现路径", distinct: true, expected: ExitNoFindings},
+		{name: "默认没有存活目标", err: scanner.ErrNoAliveTargets, expected: ExitFindings},
+		{name: "默认扫描出错", err: errors.New("failed to execute scan"), expected: ExitError},
+		{name: "默认没有发现路径", expected: ExitFindings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanExitCode(tt.findings, tt.err, tt.distinct); got != tt.expected {
+				t.Errorf("scanExitCode(%d, %v, %v) = %d, want %d", tt.findings, tt.err, tt.distinct, got, tt.expected)
+			}
+		})
+	}
+}
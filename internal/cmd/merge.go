@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/dictionary"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeOutput string
+
+// mergeCmd 字典合并子命令
+var mergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Merge and deduplicate wordlists into a single file",
+	Long: `Load all configured wordlists/sources, apply case transforms, remove
+duplicate entries and write the combined wordlist to the output file.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(wordlists) == 0 && wordlistURL == "" {
+			return fmt.Errorf("no wordlist was provided, try using -w <wordlist>")
+		}
+		if mergeOutput == "" {
+			return fmt.Errorf("no output file was provided, try using -o <file>")
+		}
+
+		cfg := config.GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("failed to get configuration")
+		}
+		updateConfigFromFlags(cfg)
+		if err := config.Validate(cfg); err != nil {
+			return fmt.Errorf("invalid options:\n%w", err)
+		}
+
+		dict, err := dictionary.NewDictionary(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to load dictionary: %w", err)
+		}
+
+		// 低内存模式下条目从磁盘读取，必须在写入前统计（输出文件可能就是输入字典）
+		total := dict.GetWordCount()
+		count, err := dict.SaveMerged(mergeOutput)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Merged %d entries (%d duplicates removed) into %s\n", count, total-count, mergeOutput)
+		return nil
+	},
+}
+
+// init 注册字典合并子命令
+func init() {
+	addDictionaryFlags(mergeCmd)
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "", "File to write the merged wordlist to")
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/report"
+)
+
+// reportOutput 返回保存报告的文件名：指定了-o时直接使用；启用自动保存（--output-dir）时在报告目录下
+// 按时间戳和格式生成文件名（如 out/scan_<timestamp>.json）；都未指定时返回空字符串
+func reportOutput(cfg *config.Config, output string) (string, error) {
+	if output != "" || !cfg.Output.AutosaveReport {
+		return output, nil
+	}
+
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := reporter.CreateReportDirectory(); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	return reporter.GenerateReportFilename(cfg.Output.ReportFormat), nil
+}
+
+// checkReportOutput 在扫描开始前检查报告格式与输出位置是否匹配：写入文件时格式必须有效（mysql格式需要-o mysql://）；
+// 写入MySQL时--format中的其他格式不会生成报告，给出警告
+func checkReportOutput(cfg *config.Config, reportFile string) error {
+	if reportFile == "" {
+		return nil
+	}
+	if !report.IsMySQLURL(reportFile) {
+		_, err := report.ParseReportFormats(cfg.Output.ReportFormat)
+		return err
+	}
+	if ignored := ignoredMySQLFormats(cfg.Output.ReportFormat); len(ignored) > 0 {
+		fmt.Printf("Warning: Results are written to MySQL, ignoring --format %s\n", strings.Join(ignored, ","))
+	}
+	return nil
+}
+
+// ignoredMySQLFormats 返回-o mysql://时被忽略的报告格式，未修改的默认格式plain不计入
+func ignoredMySQLFormats(spec string) []string {
+	if strings.EqualFold(strings.TrimSpace(spec), "plain") {
+		return nil
+	}
+	var ignored []string
+	for _, format := range strings.Split(spec, ",") {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format != "" && format != "mysql" {
+			ignored = append(ignored, format)
+		}
+	}
+	return ignored
+}
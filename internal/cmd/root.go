@@ -2,10 +2,20 @@ package cmd
 
 import (
 	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/connection"
+	"dirsearch-go/internal/dictionary"
+	"dirsearch-go/internal/report"
 	"dirsearch-go/internal/scanner"
+	"dirsearch-go/internal/session"
 	"dirsearch-go/internal/utils"
 	"dirsearch-go/internal/view"
 	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -18,21 +28,34 @@ var (
 	cidr        string
 	rawFile     string
 	nmapReport  string
+	targetsFile string
+	retryErrors string
+	targetTags  []string
+	tryWWW      bool
 	sessionFile string
 	configFile  string
+	noConfig    bool
 
 	// 字典设置
-	wordlists           []string
-	extensions          []string
-	forceExtensions     bool
-	overwriteExtensions bool
-	excludeExtensions   []string
-	removeExtensions    bool
-	prefixes            []string
-	suffixes            []string
-	uppercase           bool
-	lowercase           bool
-	capital             bool
+	wordlists            []string
+	extensions           []string
+	forceExtensions      bool
+	overwriteExtensions  bool
+	excludeExtensions    []string
+	removeExtensions     bool
+	wordlistExtensions   []string
+	priorityFile         string
+	lowMemory            bool
+	ignoreWordlistErrors bool
+	caseInsensitiveDedup bool
+	appendSlash          bool
+	tech                 []string
+	autoTech             bool
+	prefixes             []string
+	suffixes             []string
+	uppercase            bool
+	lowercase            bool
+	capital              bool
 
 	// Wordlist源设置
 	wordlistSource     string
@@ -46,57 +69,102 @@ var (
 	wordlistDBColumn   string
 
 	// 通用设置
-	threads           int
-	async             bool
-	recursive         bool
-	deepRecursive     bool
-	forceRecursive    bool
-	maxRecursionDepth int
-	recursionStatus   []string
-	subdirs           []string
-	excludeSubdirs    []string
-	includeStatus     []string
-	excludeStatus     []string
-	statusFilter      string
-	excludeSizes      []string
-	excludeText       []string
-	excludeRegex      []string
-	excludeRedirect   []string
-	excludeResponse   []string
-	skipOnStatus      []string
-	minResponseSize   int
-	maxResponseSize   int
-	maxTime           int
-	exitOnError       bool
+	threads              int
+	async                bool
+	recursive            bool
+	deepRecursive        bool
+	forceRecursive       bool
+	maxRecursionDepth    int
+	recursionStatus      []string
+	noRecursionStatus    []string
+	subdirs              []string
+	excludeSubdirs       []string
+	includeStatus        []string
+	excludeStatus        []string
+	statusFilter         string
+	excludeSizes         []string
+	excludeLengthSimilar []string
+	excludeText          []string
+	known404             []string
+	excludeRegex         []string
+	excludeRedirect      []string
+	excludeResponse      []string
+	skipOnStatus         []string
+	minResponseSize      int
+	maxResponseSize      int
+	minResponseTime      int
+	maxResponseTime      int
+	maxTime              int
+	exitOnError          bool
+	exitCodes            bool
+	interactive          bool
+	fingerprint          bool
+	twoPhase             bool
+	shuffle              bool
+	shuffleSeed          int64
+	checkBackups         bool
+	backupSuffixes       []string
+	dumpRequests         int
+	probeMethods         bool
+	detectWAF            bool
+	excludeBlocked       bool
+	startPaths           []string
+	dedupBy              string
+	skip404After         int
+	paramFuzz            bool
+	scanID               string
 
 	// 请求设置
 	httpMethod      string
 	data            string
 	dataFile        string
+	multipartData   bool
 	headers         []string
 	headersFile     string
 	followRedirects bool
+	stayInScope     bool
 	randomAgent     bool
 	auth            string
 	authType        string
+	authMap         string
+	authMapFile     string
 	certFile        string
 	keyFile         string
 	userAgent       string
 	cookie          string
+	preflightURL    string
+	tokenRegex      string
+	tokenHeader     string
+	cacheBust       bool
+	cacheBustParam  string
 
 	// 连接设置
-	timeout       float64
-	delay         float64
-	proxy         string
-	proxiesFile   string
-	proxyAuth     string
-	replayProxy   string
-	tor           bool
-	scheme        string
-	maxRate       int
-	retries       int
-	ip            string
-	interfaceName string
+	timeout          float64
+	delay            float64
+	proxy            string
+	proxiesFile      string
+	proxyAuth        string
+	proxyRules       string
+	replayProxy      string
+	replayHeaders    []string
+	tor              bool
+	torAddress       string
+	scheme           string
+	maxRate          int
+	maxBandwidth     string
+	retries          int
+	retryOnStatus    string
+	ip               string
+	interfaceName    string
+	hostTimeout      string
+	warmup           int
+	disableKeepAlive bool
+	http10           bool
+	maxConsecErrors  int
+	aliveStatus      string
+	chromePath       string
+	chromeFlags      []string
+	headlessWait     string
 
 	// 高级设置
 	crawl bool
@@ -105,6 +173,7 @@ var (
 	fullURL          bool
 	redirectsHistory bool
 	noColor          bool
+	colorMap         string
 	quietMode        bool
 	realTimeStatus   bool
 	headless         bool
@@ -112,9 +181,18 @@ var (
 	recursiveScan    bool
 
 	// 输出设置
-	output  string
-	format  string
-	logFile string
+	output        string
+	format        string
+	logFile       string
+	screenshotDir string
+	csvBOM        bool
+	webhook       string
+	webhookHeader []string
+	outputURLs    string
+	outputDir     string
+	flushInterval time.Duration
+	ciSummary     bool
+	maxTitleLen   int
 )
 
 // rootCmd 根命令
@@ -126,22 +204,43 @@ var rootCmd = &cobra.Command{
 It can discover hidden files and directories on web servers by brute-forcing
 common paths and extensions.`,
 
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// --no-config只使用内置默认配置和命令行参数
+		if noConfig {
+			if configFile != "" {
+				return fmt.Errorf("--no-config cannot be used together with --config")
+			}
+			return config.InitDefaults()
+		}
+
+		// 初始化配置（.env、环境变量和--config指定或自动查找的配置文件）
+		if err := config.Init(configFile); err != nil {
+			return fmt.Errorf("failed to initialize config: %w", err)
+		}
+		return nil
+	},
+
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// 验证必需参数
-		if len(urls) == 0 && urlsFile == "" && !stdin && cidr == "" && rawFile == "" && nmapReport == "" {
-			return fmt.Errorf("URL target is missing, try using -u <url>")
+		// 只重试报告中出错的条目时不需要目标和字典
+		if retryErrors != "" {
+			return runRetryErrors()
 		}
 
-		if len(wordlists) == 0 {
-			return fmt.Errorf("no wordlist was provided, try using -w <wordlist>")
+		// 验证必需参数
+		if len(urls) == 0 && urlsFile == "" && targetsFile == "" && !stdin && cidr == "" && rawFile == "" && nmapReport == "" && sessionFile == "" {
+			return fmt.Errorf("URL target is missing, try using -u <url>")
 		}
 
 		if threads < 1 {
 			return fmt.Errorf("threads number must be greater than zero")
 		}
 
+		if tor && (proxy != "" || proxiesFile != "") {
+			return fmt.Errorf("--tor cannot be used together with --proxy or --proxies-file")
+		}
+
 		// 启动扫描器
-		return runScanner()
+		return runScanner(cmd)
 	},
 }
 
@@ -155,36 +254,20 @@ func init() {
 	// 必需参数
 	rootCmd.Flags().StringArrayVarP(&urls, "url", "u", nil, "Target URL(s), can use multiple flags")
 	rootCmd.Flags().StringVarP(&urlsFile, "urls-file", "l", "", "URL list file")
+	rootCmd.Flags().StringVar(&targetsFile, "targets-file", "", "Target inventory file (.csv or .json with host, port, scheme, tags)")
+	rootCmd.Flags().StringArrayVar(&targetTags, "tag", nil, "Only scan inventory targets with this tag (can use multiple flags)")
+	rootCmd.Flags().BoolVar(&tryWWW, "try-www", false, "Scan both the apex and www. variant of each hostname target")
 	rootCmd.Flags().BoolVar(&stdin, "stdin", false, "Read URL(s) from STDIN")
 	rootCmd.Flags().StringVar(&cidr, "cidr", "", "Target CIDR")
 	rootCmd.Flags().StringVar(&rawFile, "raw", "", "Load raw HTTP request from file")
 	rootCmd.Flags().StringVar(&nmapReport, "nmap-report", "", "Load targets from nmap report")
 	rootCmd.Flags().StringVarP(&sessionFile, "session", "s", "", "Session file")
-	rootCmd.Flags().StringVar(&configFile, "config", "", "Path to configuration file")
+	rootCmd.Flags().StringVar(&retryErrors, "retry-errors", "", "Re-scan only the errored entries of a previous JSON report and merge the results")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to configuration file")
+	rootCmd.PersistentFlags().BoolVar(&noConfig, "no-config", false, "Ignore config files, .env files and environment variables; use only flags and built-in defaults")
 
 	// 字典设置
-	rootCmd.Flags().StringArrayVarP(&wordlists, "wordlists", "w", nil, "Wordlist files or directories contain wordlists")
-	rootCmd.Flags().StringArrayVarP(&extensions, "extensions", "e", nil, "Extension list separated by commas (e.g. php,asp)")
-	rootCmd.Flags().BoolVarP(&forceExtensions, "force-extensions", "f", false, "Add extensions to the end of every wordlist entry")
-	rootCmd.Flags().BoolVarP(&overwriteExtensions, "overwrite-extensions", "O", false, "Overwrite other extensions in the wordlist")
-	rootCmd.Flags().StringArrayVar(&excludeExtensions, "exclude-extensions", nil, "Exclude extension list separated by commas")
-	rootCmd.Flags().BoolVar(&removeExtensions, "remove-extensions", false, "Remove extensions in all paths")
-	rootCmd.Flags().StringArrayVar(&prefixes, "prefixes", nil, "Add custom prefixes to all wordlist entries")
-	rootCmd.Flags().StringArrayVar(&suffixes, "suffixes", nil, "Add custom suffixes to all wordlist entries")
-	rootCmd.Flags().BoolVarP(&uppercase, "uppercase", "U", false, "Uppercase wordlist")
-	rootCmd.Flags().BoolVarP(&lowercase, "lowercase", "L", false, "Lowercase wordlist")
-	rootCmd.Flags().BoolVarP(&capital, "capital", "C", false, "Capital wordlist")
-
-	// Wordlist源设置
-	rootCmd.Flags().StringVar(&wordlistSource, "wordlist-source", "file", "Wordlist source type (file, url, database)")
-	rootCmd.Flags().StringVar(&wordlistURL, "wordlist-url", "", "URL to fetch wordlist from")
-	rootCmd.Flags().StringVar(&wordlistDBHost, "wordlist-db-host", "", "Database host for wordlist")
-	rootCmd.Flags().IntVar(&wordlistDBPort, "wordlist-db-port", 3306, "Database port for wordlist")
-	rootCmd.Flags().StringVar(&wordlistDBUser, "wordlist-db-user", "", "Database user for wordlist")
-	rootCmd.Flags().StringVar(&wordlistDBPassword, "wordlist-db-password", "", "Database password for wordlist")
-	rootCmd.Flags().StringVar(&wordlistDBName, "wordlist-db-name", "", "Database name for wordlist")
-	rootCmd.Flags().StringVar(&wordlistDBTable, "wordlist-db-table", "wordlists", "Database table for wordlist")
-	rootCmd.Flags().StringVar(&wordlistDBColumn, "wordlist-db-column", "word", "Database column for wordlist")
+	addDictionaryFlags(rootCmd)
 
 	// 通用设置
 	rootCmd.Flags().IntVarP(&threads, "threads", "t", 25, "Number of threads")
@@ -194,50 +277,93 @@ func init() {
 	rootCmd.Flags().BoolVar(&forceRecursive, "force-recursive", false, "Do recursive brute-force for every found path")
 	rootCmd.Flags().IntVarP(&maxRecursionDepth, "max-recursion-depth", "R", 0, "Maximum recursion depth")
 	rootCmd.Flags().StringArrayVar(&recursionStatus, "recursion-status", nil, "Valid status codes to perform recursive scan")
-	rootCmd.Flags().StringArrayVar(&subdirs, "subdirs", nil, "Scan sub-directories of the given URL[s]")
+	rootCmd.Flags().StringArrayVar(&noRecursionStatus, "recursion-exclude-status", nil, "Status codes that never trigger a recursive scan, separated by commas (e.g. 403); matching paths are still reported")
+	rootCmd.Flags().StringArrayVar(&subdirs, "subdirs", nil, "Scan sub-directories of the given URL[s], separated by commas (e.g. admin/,api/; an empty entry scans the root)")
 	rootCmd.Flags().StringArrayVar(&excludeSubdirs, "exclude-subdirs", nil, "Exclude the following subdirectories during recursive scan")
 	rootCmd.Flags().StringArrayVarP(&includeStatus, "include-status", "i", nil, "Include status codes, separated by commas")
 	rootCmd.Flags().StringArrayVarP(&excludeStatus, "exclude-status", "x", nil, "Exclude status codes, separated by commas")
 	rootCmd.Flags().StringVar(&statusFilter, "status", "", "Filter results by status code (e.g. 200,404)")
 	rootCmd.Flags().StringArrayVar(&excludeSizes, "exclude-sizes", nil, "Exclude responses by sizes, separated by commas")
+	rootCmd.Flags().StringArrayVar(&excludeLengthSimilar, "exclude-length-similar", nil, "Exclude responses whose size is within a tolerance of a baseline (e.g. 1234±20, 1234+-20 or 1234±5%)")
 	rootCmd.Flags().StringArrayVar(&excludeText, "exclude-text", nil, "Exclude responses by text")
+	rootCmd.Flags().StringArrayVar(&known404, "known-404", nil, "Exclude responses matching a known 404 signature without a baseline request (size=<bytes> or text=<marker>)")
 	rootCmd.Flags().StringArrayVar(&excludeRegex, "exclude-regex", nil, "Exclude responses by regular expression")
 	rootCmd.Flags().StringArrayVar(&excludeRedirect, "exclude-redirect", nil, "Exclude responses if this regex matches redirect URL")
 	rootCmd.Flags().StringArrayVar(&excludeResponse, "exclude-response", nil, "Exclude responses similar to response of this page")
 	rootCmd.Flags().StringArrayVar(&skipOnStatus, "skip-on-status", nil, "Skip target whenever hit one of these status codes")
 	rootCmd.Flags().IntVar(&minResponseSize, "min-response-size", 0, "Minimum response length")
 	rootCmd.Flags().IntVar(&maxResponseSize, "max-response-size", 0, "Maximum response length")
-	rootCmd.Flags().IntVar(&maxTime, "max-time", 0, "Maximum runtime for the scan")
-	rootCmd.Flags().BoolVar(&exitOnError, "exit-on-error", false, "Exit whenever an error occurs")
+	rootCmd.Flags().IntVar(&minResponseTime, "min-response-time", 0, "Minimum response time in milliseconds")
+	rootCmd.Flags().IntVar(&maxResponseTime, "max-response-time", 0, "Maximum response time in milliseconds")
+	rootCmd.Flags().IntVar(&maxTime, "max-time", 0, "Maximum runtime for the scan in seconds; found results are still reported when it is reached")
+	rootCmd.Flags().BoolVar(&exitOnError, "exit-on-error", false, "Abort the scan and exit non-zero on the first request error (connection failure, timeout); HTTP error statuses do not count")
+	rootCmd.Flags().BoolVar(&exitCodes, "exit-codes", false, "Exit with 2 when no targets are alive and 3 when nothing is found, instead of 0")
+	rootCmd.Flags().BoolVar(&interactive, "interactive", false, "Enable keyboard controls to pause/resume and adjust threads during the scan")
+	rootCmd.Flags().BoolVar(&fingerprint, "fingerprint", false, "Fingerprint each host (server, CDN/WAF, TLS certificate) and show a summary at scan end")
+	rootCmd.Flags().BoolVar(&autoTech, "auto-tech", false, "Detect the target technology from Server/X-Powered-By headers of the root page and add its common extensions")
+	rootCmd.Flags().BoolVar(&twoPhase, "two-phase", false, "Probe every path with HEAD first, then confirm only the candidate hits with the configured method")
+	rootCmd.Flags().BoolVar(&shuffle, "shuffle", false, "Send paths in random order for each target")
+	rootCmd.Flags().Int64Var(&shuffleSeed, "shuffle-seed", 0, "Seed for --shuffle to reproduce an order (default: random, printed at scan start)")
+	rootCmd.Flags().BoolVar(&checkBackups, "check-backups", false, "Probe backup/temp variants (.bak, ~, .old, .name.swp) of every discovered file")
+	rootCmd.Flags().StringArrayVar(&backupSuffixes, "backup-suffixes", nil, "Extra suffix for --check-backups (e.g. .zip, can use multiple flags)")
+	rootCmd.Flags().BoolVar(&probeMethods, "probe-methods", false, "Send an OPTIONS request to every discovered path and record the allowed methods (Allow header)")
+	rootCmd.Flags().BoolVar(&detectWAF, "detect-waf", false, "Flag responses that look like WAF block pages (406/419/429 status, or 403/503 with WAF headers or block page markers)")
+	rootCmd.Flags().BoolVar(&excludeBlocked, "exclude-blocked", false, "Exclude suspected WAF-blocked responses from results (implies --detect-waf)")
+	rootCmd.Flags().IntVar(&skip404After, "skip-404-after", 0, "During recursion, stop scheduling a path on a host once it has returned 404 under K different directories of that host (0 disables)")
+	rootCmd.Flags().StringVar(&dedupBy, "dedup-by", "", "Drop duplicate results by key: url, path, hash (same content, redirects collapse into their target) or status-size")
+	rootCmd.Flags().StringArrayVar(&startPaths, "start-paths", nil, "Known paths scanned before the wordlist, separated by commas (e.g. /api/,/admin/); paths ending with / seed recursion")
+	rootCmd.Flags().BoolVar(&paramFuzz, "param-fuzz", false, "Fuzz query parameter names: keep each target URL fixed and send every wordlist entry as ?WORD=test, reporting only responses that differ from the no-parameter baseline")
+	rootCmd.Flags().StringVar(&scanID, "scan-id", "", "ID of this scan, written to reports (a scan_id field on each json/jsonl record), webhook payloads and log lines (default: a generated UUID)")
+	rootCmd.Flags().IntVar(&dumpRequests, "dump-requests", 0, "Print up to N raw HTTP requests the scan would send, without sending them (e.g. --dump-requests 10)")
 
 	// 请求设置
 	rootCmd.Flags().StringVarP(&httpMethod, "http-method", "m", "GET", "HTTP method (default: GET)")
 	rootCmd.Flags().StringVarP(&data, "data", "d", "", "HTTP request data")
 	rootCmd.Flags().StringVar(&dataFile, "data-file", "", "File contains HTTP request data")
+	rootCmd.Flags().BoolVar(&multipartData, "multipart", false, "Send request data as multipart/form-data (key=value&file=@path)")
 	rootCmd.Flags().StringArrayVarP(&headers, "header", "H", nil, "HTTP request header, can use multiple flags")
 	rootCmd.Flags().StringVar(&headersFile, "headers-file", "", "File contains HTTP request headers")
 	rootCmd.Flags().BoolVarP(&followRedirects, "follow-redirects", "F", false, "Follow HTTP redirects")
+	rootCmd.Flags().BoolVar(&stayInScope, "stay-in-scope", false, "With --follow-redirects, do not follow redirects to a host other than the target's (the redirect is recorded instead)")
 	rootCmd.Flags().BoolVar(&randomAgent, "random-agent", false, "Choose a random User-Agent for each request")
 	rootCmd.Flags().StringVar(&auth, "auth", "", "Authentication credential (e.g. user:password or bearer token)")
 	rootCmd.Flags().StringVar(&authType, "auth-type", "", "Authentication type (basic, digest, bearer, ntlm, jwt)")
-	rootCmd.Flags().StringVar(&certFile, "cert-file", "", "File contains client-side certificate")
+	rootCmd.Flags().StringVar(&authMap, "auth-map", "", "Per-host authentication credentials (e.g. host1=token1,host2:8443=token2), sent with --auth-type basic (user:password) or bearer (default: bearer)")
+	rootCmd.Flags().StringVar(&authMapFile, "auth-map-file", "", "File with one host=credential per line, merged with --auth-map")
+	rootCmd.Flags().StringVar(&certFile, "cert-file", "", "File contains client-side certificate (PEM, used for mutual TLS together with --key-file)")
 	rootCmd.Flags().StringVar(&keyFile, "key-file", "", "File contains client-side certificate private key")
 	rootCmd.Flags().StringVar(&userAgent, "user-agent", "", "User-Agent")
 	rootCmd.Flags().StringVar(&cookie, "cookie", "", "Cookie")
+	rootCmd.Flags().StringVar(&preflightURL, "preflight-url", "", "URL requested once before the scan to extract a token")
+	rootCmd.Flags().StringVar(&tokenRegex, "extract-token-regex", "", "Regex to extract the token from the preflight response (first capture group is used)")
+	rootCmd.Flags().StringVar(&tokenHeader, "token-header", "X-CSRF-Token", "Header used to send the extracted token on scan requests")
+	rootCmd.Flags().BoolVar(&cacheBust, "cache-bust", false, "Append a random query parameter to every request to bypass CDN caches")
+	rootCmd.Flags().StringVar(&cacheBustParam, "cache-bust-param", "cb", "Query parameter name used by --cache-bust")
 
 	// 连接设置
 	rootCmd.Flags().Float64Var(&timeout, "timeout", 7.5, "Connection timeout")
 	rootCmd.Flags().Float64Var(&delay, "delay", 0, "Delay between requests")
 	rootCmd.Flags().StringVarP(&proxy, "proxy", "p", "", "Proxy URL (HTTP/SOCKS), can use multiple flags")
-	rootCmd.Flags().StringVar(&proxiesFile, "proxies-file", "", "File contains proxy servers")
-	rootCmd.Flags().StringVar(&proxyAuth, "proxy-auth", "", "Proxy authentication credential")
+	rootCmd.Flags().StringVar(&proxiesFile, "proxies-file", "", "File contains proxy servers, one per line; requests rotate through them round-robin")
+	rootCmd.Flags().StringVar(&proxyAuth, "proxy-auth", "", "Proxy authentication credential (user:pass), ignored when the proxy URL already contains credentials")
+	rootCmd.Flags().StringVar(&proxyRules, "proxy-rules", "", "Per-host proxy routing rules, first match wins (e.g. \"*.internal=direct;*=socks5://127.0.0.1:1080\")")
 	rootCmd.Flags().StringVar(&replayProxy, "replay-proxy", "", "Proxy to replay with found paths")
+	rootCmd.Flags().StringArrayVar(&replayHeaders, "replay-header", nil, "Extra header added only to replayed requests (e.g. \"X-Tag: dirsearch\")")
 	rootCmd.Flags().BoolVar(&tor, "tor", false, "Use Tor network as proxy")
+	rootCmd.Flags().StringVar(&torAddress, "tor-address", "", "Tor SOCKS5 proxy address used by --tor (default: 127.0.0.1:9050)")
 	rootCmd.Flags().StringVar(&scheme, "scheme", "", "Scheme for raw request or if there is no scheme in the URL")
 	rootCmd.Flags().IntVar(&maxRate, "max-rate", 0, "Max requests per second")
+	rootCmd.Flags().StringVar(&maxBandwidth, "max-bandwidth", "", "Max total bandwidth for reading responses across all threads, in bytes per second (e.g. 512kb, 1mb)")
 	rootCmd.Flags().IntVar(&retries, "retries", 1, "Number of retries for failed requests")
-	rootCmd.Flags().StringVar(&ip, "ip", "", "Server IP address")
-	rootCmd.Flags().StringVar(&interfaceName, "interface", "", "Network interface to use")
+	rootCmd.Flags().StringVar(&retryOnStatus, "retry-on-status", "", "Status codes retried up to --retries times after a backoff, honoring Retry-After (e.g. 429,503; default: 429 and 5xx)")
+	rootCmd.Flags().StringVar(&ip, "ip", "", "Local source IP address to send requests from")
+	rootCmd.Flags().StringVar(&interfaceName, "interface", "", "Network interface to send requests from (e.g. eth1, tun0); --ip takes precedence")
+	rootCmd.Flags().StringVar(&hostTimeout, "host-timeout", "", "Per-host timeout overrides (e.g. slow.example.com=30,fast.example.com=3)")
+	rootCmd.Flags().IntVar(&warmup, "warmup", 0, "Send N throwaway requests to / on each host before the scan to prime connections and DNS (e.g. --warmup 3)")
+	rootCmd.Flags().BoolVar(&disableKeepAlive, "disable-keepalive", false, "Use a fresh connection for every request instead of reusing keep-alive connections")
+	rootCmd.Flags().BoolVar(&http10, "http10", false, "Send requests as HTTP/1.0 with Connection: close, for legacy servers (not supported with proxies)")
+	rootCmd.Flags().IntVar(&maxConsecErrors, "max-consecutive-errors", 0, "Skip the rest of a host's scan after N consecutive request errors (0 disables)")
+	rootCmd.Flags().StringVar(&aliveStatus, "alive-status", "", "Status codes at / that mark a host alive (e.g. 200-399,401,403; default: any HTTP response)")
 
 	// 高级设置
 	rootCmd.Flags().BoolVar(&crawl, "crawl", false, "Crawl for new paths in responses")
@@ -246,23 +372,153 @@ func init() {
 	rootCmd.Flags().BoolVar(&fullURL, "full-url", false, "Full URLs in the output")
 	rootCmd.Flags().BoolVar(&redirectsHistory, "redirects-history", false, "Show redirects history")
 	rootCmd.Flags().BoolVar(&noColor, "no-color", false, "No colored output")
+	rootCmd.Flags().StringVar(&colorMap, "color-map", "", "Override status code colors, separated by commas (e.g. 403=yellow,401=cyan)")
 	rootCmd.Flags().BoolVarP(&quietMode, "quiet-mode", "q", false, "Quiet mode")
 	rootCmd.Flags().BoolVar(&realTimeStatus, "real-time-status", false, "Enable real-time status display")
 	rootCmd.Flags().BoolVar(&headless, "headless", false, "Use headless browser for scanning")
+	rootCmd.Flags().StringVar(&chromePath, "chrome-path", "", "Path to the Chrome/Chromium executable for headless mode")
+	rootCmd.Flags().StringArrayVar(&chromeFlags, "chrome-flag", nil, "Extra Chrome flag for headless mode (name or name=value, name=false removes it)")
+	rootCmd.Flags().StringVar(&headlessWait, "headless-wait-for", "", "Wait condition in headless mode: a CSS selector or \"networkidle\"")
 	rootCmd.Flags().BoolVar(&showAllStatus, "show-all-status", false, "Show all status codes (default: only 200 and 403)")
 	rootCmd.Flags().BoolVar(&recursiveScan, "recursive-scan", false, "Enable recursive scanning for directories (200/403)")
 
 	// 输出设置
 	rootCmd.Flags().StringVarP(&output, "output", "o", "", "Output file or MySQL/PostgreSQL URL")
-	rootCmd.Flags().StringVar(&format, "format", "plain", "Report format (Available: simple, plain, json, xml, md, csv, html, sqlite, mysql, postgresql)")
+	rootCmd.Flags().StringVar(&format, "format", "plain", "Report format, comma-separated for several reports from one scan (Available: simple, grepable, plain, json, jsonl, xml, md, csv, html, sqlite, mysql, postgresql)")
 	rootCmd.Flags().StringVar(&logFile, "log", "", "Log file")
+	rootCmd.Flags().StringVar(&screenshotDir, "screenshots", "", "Directory to save full-page screenshots of found pages (headless mode)")
+	rootCmd.Flags().BoolVar(&csvBOM, "csv-bom", false, "Prepend a UTF-8 BOM to CSV reports so Excel detects the encoding")
+	rootCmd.Flags().StringVar(&webhook, "webhook", "", "POST each found result as JSON to this URL while scanning")
+	rootCmd.Flags().StringVar(&outputDir, "output-dir", "", "Save reports into this directory with generated names (scan_<timestamp>.<ext> for each --format) when -o is not given")
+	rootCmd.Flags().StringVar(&outputURLs, "output-urls", "", "Write the full URLs of found paths to this file, one per line (for piping into other tools)")
+	rootCmd.Flags().DurationVar(&flushInterval, "flush-interval", 0, "How often the streaming jsonl report is flushed to disk during the scan (default 1s)")
+	rootCmd.Flags().IntVar(&maxTitleLen, "max-title-length", 0, "Truncate titles longer than N characters (with an ellipsis) in the terminal and text/csv reports; JSON reports keep the full title (0 = no limit)")
+	rootCmd.Flags().BoolVar(&ciSummary, "ci-summary", false, "Print a single parseable line (RESULT found=N scanned=N errors=N duration=Ns) to stderr when the scan finishes")
+	rootCmd.Flags().StringArrayVar(&webhookHeader, "webhook-header", nil, "Header sent with webhook requests (e.g. \"Authorization: Bearer token\", can use multiple flags)")
 
 	// 版本信息
 	rootCmd.Flags().Bool("version", false, "Show program's version number and exit")
 }
 
+// addDictionaryFlags 注册字典和wordlist源相关的标志（扫描与字典子命令共用）
+func addDictionaryFlags(cmd *cobra.Command) {
+	// 字典设置
+	cmd.Flags().StringArrayVarP(&wordlists, "wordlists", "w", nil, "Wordlist files or directories contain wordlists")
+	cmd.Flags().StringArrayVarP(&extensions, "extensions", "e", nil, "Extension list separated by commas (e.g. php,asp)")
+	cmd.Flags().BoolVarP(&forceExtensions, "force-extensions", "f", false, "Add extensions to the end of every wordlist entry")
+	cmd.Flags().BoolVarP(&overwriteExtensions, "overwrite-extensions", "O", false, "Overwrite other extensions in the wordlist")
+	cmd.Flags().StringArrayVar(&excludeExtensions, "exclude-extensions", nil, "Exclude extension list separated by commas")
+	cmd.Flags().BoolVar(&removeExtensions, "remove-extensions", false, "Remove extensions in all paths")
+	cmd.Flags().StringArrayVar(&wordlistExtensions, "wordlist-extensions", nil, "File extensions loaded from wordlist directories, separated by commas (default: txt)")
+	cmd.Flags().StringVar(&priorityFile, "priority-file", "", "Wordlist of high-value entries tried on every target before the rest of the wordlist")
+	cmd.Flags().BoolVar(&lowMemory, "low-memory", false, "Stream wordlist entries from disk during the scan instead of loading them into memory (paths are deduplicated by a 64-bit hash that spills to a temp file when large, so a hash collision can rarely skip a distinct path)")
+	cmd.Flags().BoolVar(&ignoreWordlistErrors, "ignore-wordlist-errors", false, "Log wordlist files or sources that fail to load and continue with the rest instead of aborting")
+	cmd.Flags().BoolVar(&caseInsensitiveDedup, "case-insensitive-dedup", false, "Treat paths differing only in case as duplicates, keeping the first (for Windows/IIS targets)")
+	cmd.Flags().BoolVar(&appendSlash, "append-slash", false, "Also try a trailing-slash variant (word/) for wordlist entries without an extension")
+	cmd.Flags().StringArrayVar(&tech, "tech", nil, "Target technology whose common extensions are added, separated by commas (php, asp, java, python, ruby, perl, coldfusion, node)")
+	cmd.Flags().StringArrayVar(&prefixes, "prefixes", nil, "Add custom prefixes to all wordlist entries")
+	cmd.Flags().StringArrayVar(&suffixes, "suffixes", nil, "Add custom suffixes to all wordlist entries")
+	cmd.Flags().BoolVarP(&uppercase, "uppercase", "U", false, "Uppercase wordlist")
+	cmd.Flags().BoolVarP(&lowercase, "lowercase", "L", false, "Lowercase wordlist")
+	cmd.Flags().BoolVarP(&capital, "capital", "C", false, "Capital wordlist")
+
+	// Wordlist源设置
+	cmd.Flags().StringVar(&wordlistSource, "wordlist-source", "file", "Wordlist source type (file, url, database)")
+	cmd.Flags().StringVar(&wordlistURL, "wordlist-url", "", "URL to fetch wordlist from")
+	cmd.Flags().StringVar(&wordlistDBHost, "wordlist-db-host", "", "Database host for wordlist")
+	cmd.Flags().IntVar(&wordlistDBPort, "wordlist-db-port", 3306, "Database port for wordlist")
+	cmd.Flags().StringVar(&wordlistDBUser, "wordlist-db-user", "", "Database user for wordlist")
+	cmd.Flags().StringVar(&wordlistDBPassword, "wordlist-db-password", "", "Database password for wordlist")
+	cmd.Flags().StringVar(&wordlistDBName, "wordlist-db-name", "", "Database name for wordlist")
+	cmd.Flags().StringVar(&wordlistDBTable, "wordlist-db-table", "wordlists", "Database table for wordlist")
+	cmd.Flags().StringVar(&wordlistDBColumn, "wordlist-db-column", "word", "Database column for wordlist")
+}
+
+// checkProxyReachable 检查代理地址是否可以建立TCP连接
+func checkProxyReachable(proxyURL string) error {
+	address, err := proxyAddress(proxyURL)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, 3*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// proxyAddress 获取代理的host:port，未指定端口时按协议补全默认端口
+func proxyAddress(proxyURL string) (string, error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return "", err
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		port := "80"
+		switch parsed.Scheme {
+		case "https":
+			port = "443"
+		case "socks5":
+			port = "1080"
+		}
+		address = net.JoinHostPort(parsed.Hostname(), port)
+	}
+	return address, nil
+}
+
+// runRetryErrors 重新扫描之前JSON报告中出错的条目，并保存合并后的结果
+func runRetryErrors() error {
+	cfg := config.GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("failed to get configuration")
+	}
+
+	updateConfigFromFlags(cfg)
+	cfg.General.RetryErrors = retryErrors
+	if err := config.Validate(cfg); err != nil {
+		return fmt.Errorf("invalid options:\n%w", err)
+	}
+
+	colorManager, err := newColorManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	previous, err := report.LoadJSONReport(retryErrors)
+	if err != nil {
+		return err
+	}
+
+	s, err := scanner.NewScanner(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create scanner: %w", err)
+	}
+
+	results, err := s.RetryErrors(previous)
+	if err != nil {
+		return err
+	}
+
+	// 未指定输出文件时更新原报告
+	reportFile := output
+	if reportFile == "" {
+		reportFile = retryErrors
+		cfg.Output.ReportFormat = "json"
+	}
+	if err := s.SaveResults(reportFile); err != nil {
+		return fmt.Errorf("failed to save results: %w", err)
+	}
+	fmt.Printf("Results saved to: %s\n", report.RedactOutput(reportFile))
+
+	displayResults(results, colorManager, cfg.Output.MaxTitleLength)
+	return nil
+}
+
 // runScanner 运行扫描器
-func runScanner() error {
+func runScanner(cmd *cobra.Command) error {
 	// 获取配置
 	cfg := config.GetConfig()
 	if cfg == nil {
@@ -286,6 +542,25 @@ func runScanner() error {
 		targets = append(targets, fileTargets...)
 	}
 
+	// 从目标清单读取URL
+	if targetsFile != "" {
+		inventoryTargets, err := utils.ReadInventoryTargets(targetsFile, targetTags)
+		if err != nil {
+			return fmt.Errorf("failed to read targets file: %w", err)
+		}
+		targets = append(targets, inventoryTargets...)
+	}
+
+	// 从nmap XML报告读取开放的HTTP/HTTPS服务
+	if nmapReport != "" {
+		nmapTargets, err := utils.ParseNmapReport(nmapReport)
+		if err != nil {
+			return fmt.Errorf("failed to read nmap report: %w", err)
+		}
+		fmt.Printf("Loaded %d HTTP service(s) from nmap report\n", len(nmapTargets))
+		targets = append(targets, nmapTargets...)
+	}
+
 	// 从标准输入读取URL
 	if stdin {
 		stdinTargets, err := utils.ReadLinesFromStdin()
@@ -295,6 +570,21 @@ func runScanner() error {
 		targets = append(targets, stdinTargets...)
 	}
 
+	// 从原始请求文件读取目标和请求配置
+	var rawRequest *config.RequestConfig
+	if rawFile != "" {
+		rawScheme := scheme
+		if rawScheme == "" {
+			rawScheme = cfg.Connection.Scheme
+		}
+		rawTarget, reqCfg, err := connection.LoadRawRequest(rawFile, rawScheme)
+		if err != nil {
+			return fmt.Errorf("failed to load raw request: %w", err)
+		}
+		targets = append(targets, rawTarget)
+		rawRequest = &reqCfg
+	}
+
 	// 从CIDR解析URL
 	if cidr != "" {
 		cidrTargets, err := utils.ParseCIDR(cidr)
@@ -304,6 +594,15 @@ func runScanner() error {
 		targets = append(targets, cidrTargets...)
 	}
 
+	// 只指定--session时从会话文件恢复目标
+	if len(targets) == 0 && sessionFile != "" {
+		state, err := session.Load(sessionFile)
+		if err != nil {
+			return fmt.Errorf("failed to load session: %w", err)
+		}
+		targets = state.Targets
+	}
+
 	if len(targets) == 0 {
 		return fmt.Errorf("no targets specified")
 	}
@@ -323,6 +622,36 @@ func runScanner() error {
 
 	// 更新配置
 	updateConfigFromFlags(cfg)
+	if rawRequest != nil {
+		applyRawRequest(cfg, *rawRequest, cmd.Flags().Changed("http-method"))
+	}
+
+	// 未指定字典时使用默认字典
+	if dictionary.NeedsDefaultWordlist(cfg) {
+		cfg.Dictionary.Wordlists = []string{dictionary.DefaultWordlist()}
+		fmt.Printf("No wordlist was provided (-w), using default wordlist: %s\n", cfg.Dictionary.Wordlists[0])
+	}
+
+	// 验证参数组合
+	if err := config.Validate(cfg); err != nil {
+		return fmt.Errorf("invalid options:\n%w", err)
+	}
+	if cfg.Connection.ReplayProxy != "" {
+		if err := checkProxyReachable(cfg.Connection.ReplayProxy); err != nil {
+			return fmt.Errorf("replay proxy is not reachable: %w", err)
+		}
+	}
+
+	// 同时扫描apex和www.域名（在域名存活检测之前展开）
+	if cfg.General.TryWWW {
+		cleanTargets = utils.ExpandWWW(cleanTargets)
+	}
+
+	// 创建颜色管理器，扫描开始前检查--color-map
+	colorManager, err := newColorManager(cfg)
+	if err != nil {
+		return err
+	}
 
 	// 创建扫描器
 	scanner, err := scanner.NewScanner(cfg)
@@ -330,31 +659,122 @@ func runScanner() error {
 		return fmt.Errorf("failed to create scanner: %w", err)
 	}
 
+	// 只打印原始请求，不发送
+	if cfg.General.DumpRequests > 0 {
+		return scanner.DumpRequests(cleanTargets, os.Stdout)
+	}
+
+	// 供CI解析的单行摘要输出到stderr，扫描提前结束或出错时也输出
+	if cfg.Output.CISummary {
+		defer func() { fmt.Fprintln(os.Stderr, scanner.CISummary()) }()
+	}
+
+	// 日志行带上扫描ID，与报告和webhook事件中的ID对应
+	log.SetPrefix("[" + scanner.ScanID() + "] ")
+	fmt.Printf("Scan ID: %s\n", scanner.ScanID())
+
+	// 使用会话时Ctrl+C停止扫描并保存进度，而不是直接退出
+	if cfg.General.SessionFile != "" {
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		defer signal.Stop(interrupt)
+		go func() {
+			<-interrupt
+			fmt.Println("\nInterrupted, saving session...")
+			scanner.Stop()
+		}()
+	}
+
+	// 未指定-o时，--output-dir按时间戳和格式自动生成报告文件名
+	reportFile, err := reportOutput(cfg, output)
+	if err != nil {
+		return err
+	}
+
+	if err := checkReportOutput(cfg, reportFile); err != nil {
+		return err
+	}
+
+	// 结果写入MySQL时先检查连接，避免扫描结束后才发现无法保存
+	if report.IsMySQLURL(reportFile) {
+		if err := report.CheckMySQL(reportFile); err != nil {
+			return err
+		}
+	}
+
+	// jsonl报告在扫描过程中流式写入，扫描结束后再写入完整结果
+	if reportFile != "" && !report.IsMySQLURL(reportFile) {
+		if filename, ok := report.StreamFilename(reportFile, cfg.Output.ReportFormat, "jsonl"); ok {
+			if err := scanner.StreamResults(filename); err != nil {
+				return fmt.Errorf("failed to create stream report: %w", err)
+			}
+		}
+	}
+
 	// 开始扫描
 	fmt.Printf("Starting scan with %d targets and %d threads...\n", len(cleanTargets), cfg.General.Threads)
 
 	// 执行扫描并获取结果
 	results, err := scanner.Scan(cleanTargets)
+	if noAliveTargets(err) {
+		// 没有存活目标不算错误，--exit-codes时使用单独的退出码
+		fmt.Println("No alive targets to scan, nothing to do.")
+		exitCode = scanExitCode(0, err, cfg.General.ExitCodes)
+		return nil
+	}
 	if err != nil {
+		// --exit-on-error中止时保存已扫描部分的结果，再以错误退出
+		if scanAborted(err) && reportFile != "" {
+			if saveErr := scanner.SaveResults(reportFile); saveErr != nil {
+				fmt.Printf("Warning: Failed to save partial results: %v\n", saveErr)
+			} else {
+				fmt.Printf("Partial results saved to: %s\n", report.RedactOutput(reportFile))
+			}
+		}
 		return fmt.Errorf("scan failed: %w", err)
 	}
 
 	fmt.Printf("Scan completed. Found %d results.\n", len(results))
 
 	// 保存结果
-	if output != "" {
-		if err := scanner.SaveResults(output); err != nil {
+	if reportFile != "" {
+		if err := scanner.SaveResults(reportFile); err != nil {
 			return fmt.Errorf("failed to save results: %w", err)
 		}
-		fmt.Printf("Results saved to: %s\n", output)
+		fmt.Printf("Results saved to: %s\n", report.RedactOutput(reportFile))
+	}
+	if cfg.Output.OutputURLs != "" {
+		if err := scanner.SaveFoundURLs(cfg.Output.OutputURLs); err != nil {
+			return fmt.Errorf("failed to save URL list: %w", err)
+		}
+		fmt.Printf("Found URLs saved to: %s\n", cfg.Output.OutputURLs)
 	}
 
 	// 显示结果
-	displayResults(results)
+	displayResults(results, colorManager, cfg.Output.MaxTitleLength)
 
+	exitCode = scanExitCode(scanner.Findings(), nil, cfg.General.ExitCodes)
 	return nil
 }
 
+// applyRawRequest 使用原始请求中的方法、请求头、Cookie、User-Agent和请求体，命令行中显式指定的选项优先
+func applyRawRequest(cfg *config.Config, raw config.RequestConfig, methodSet bool) {
+	if !methodSet {
+		cfg.Request.HTTPMethod = raw.HTTPMethod
+	}
+	// 命令行的请求头排在后面，同名时覆盖原始请求中的值
+	cfg.Request.Headers = append(raw.Headers, cfg.Request.Headers...)
+	if cfg.Request.Cookie == "" {
+		cfg.Request.Cookie = raw.Cookie
+	}
+	if cfg.Request.UserAgent == "" {
+		cfg.Request.UserAgent = raw.UserAgent
+	}
+	if cfg.Request.Data == "" && cfg.Request.DataFile == "" {
+		cfg.Request.Data = raw.Data
+	}
+}
+
 // updateConfigFromFlags 从命令行标志更新配置
 func updateConfigFromFlags(cfg *config.Config) {
 	// 更新字典配置
@@ -402,8 +822,32 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if len(excludeExtensions) > 0 {
 		cfg.Dictionary.ExcludeExtensions = excludeExtensions
 	}
+	if len(wordlistExtensions) > 0 {
+		cfg.Dictionary.WordlistExtensions = wordlistExtensions
+	}
+	if priorityFile != "" {
+		cfg.Dictionary.PriorityFile = priorityFile
+	}
+	if lowMemory {
+		cfg.Dictionary.LowMemory = true
+	}
+	if ignoreWordlistErrors {
+		cfg.Dictionary.IgnoreWordlistErrors = true
+	}
+	if caseInsensitiveDedup {
+		cfg.Dictionary.CaseInsensitiveDedup = true
+	}
+	if appendSlash {
+		cfg.Dictionary.AppendSlash = true
+	}
+	if len(tech) > 0 {
+		cfg.Dictionary.Tech = tech
+	}
+	if autoTech {
+		cfg.Dictionary.AutoTech = true
+	}
 	if removeExtensions {
-		// TODO: 实现移除扩展名功能
+		cfg.Dictionary.RemoveExtensions = true
 	}
 	if len(prefixes) > 0 {
 		cfg.Dictionary.Prefixes = prefixes
@@ -473,8 +917,11 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if len(recursionStatus) > 0 {
 		cfg.General.RecursionStatus = recursionStatus
 	}
+	if len(noRecursionStatus) > 0 {
+		cfg.General.NoRecursionStatus = noRecursionStatus
+	}
 	if len(subdirs) > 0 {
-		// TODO: 实现子目录扫描
+		cfg.General.Subdirs = subdirs
 	}
 	if len(excludeSubdirs) > 0 {
 		cfg.General.ExcludeSubdirs = excludeSubdirs
@@ -488,9 +935,15 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if len(excludeSizes) > 0 {
 		cfg.General.ExcludeSizes = excludeSizes
 	}
+	if len(excludeLengthSimilar) > 0 {
+		cfg.General.ExcludeLengthSimilar = excludeLengthSimilar
+	}
 	if len(excludeText) > 0 {
 		cfg.General.ExcludeText = excludeText
 	}
+	if len(known404) > 0 {
+		cfg.General.Known404 = known404
+	}
 	if len(excludeRegex) > 0 {
 		cfg.General.ExcludeRegex = excludeRegex
 	}
@@ -509,12 +962,75 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if maxResponseSize > 0 {
 		cfg.General.MaxResponseSize = maxResponseSize
 	}
+	if minResponseTime > 0 {
+		cfg.General.MinResponseTime = minResponseTime
+	}
+	if maxResponseTime > 0 {
+		cfg.General.MaxResponseTime = maxResponseTime
+	}
 	if maxTime > 0 {
 		cfg.General.MaxTime = maxTime
 	}
 	if exitOnError {
 		cfg.General.ExitOnError = true
 	}
+	if exitCodes {
+		cfg.General.ExitCodes = true
+	}
+	if interactive {
+		cfg.General.Interactive = true
+	}
+	if fingerprint {
+		cfg.General.Fingerprint = true
+	}
+	if tryWWW {
+		cfg.General.TryWWW = true
+	}
+	if twoPhase {
+		cfg.General.TwoPhase = true
+	}
+	if shuffle {
+		cfg.General.Shuffle = true
+	}
+	if shuffleSeed != 0 {
+		cfg.General.ShuffleSeed = shuffleSeed
+	}
+	if checkBackups {
+		cfg.General.CheckBackups = true
+	}
+	if len(backupSuffixes) > 0 {
+		cfg.General.BackupSuffixes = backupSuffixes
+	}
+	if dumpRequests > 0 {
+		cfg.General.DumpRequests = dumpRequests
+	}
+	if probeMethods {
+		cfg.General.ProbeMethods = true
+	}
+	if detectWAF {
+		cfg.General.DetectWAF = true
+	}
+	if excludeBlocked {
+		cfg.General.ExcludeBlocked = true
+	}
+	if len(startPaths) > 0 {
+		cfg.General.StartPaths = startPaths
+	}
+	if dedupBy != "" {
+		cfg.General.DedupBy = dedupBy
+	}
+	if skip404After > 0 {
+		cfg.General.Skip404After = skip404After
+	}
+	if sessionFile != "" {
+		cfg.General.SessionFile = sessionFile
+	}
+	if paramFuzz {
+		cfg.General.ParamFuzz = true
+	}
+	if scanID != "" {
+		cfg.General.ScanID = scanID
+	}
 
 	// 更新请求配置
 	if httpMethod != "" {
@@ -526,6 +1042,9 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if dataFile != "" {
 		cfg.Request.DataFile = dataFile
 	}
+	if multipartData {
+		cfg.Request.Multipart = true
+	}
 	if len(headers) > 0 {
 		cfg.Request.Headers = headers
 	}
@@ -535,6 +1054,9 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if followRedirects {
 		cfg.Request.FollowRedirects = true
 	}
+	if stayInScope {
+		cfg.Request.StayInScope = true
+	}
 	if randomAgent {
 		cfg.General.RandomUserAgents = true
 	}
@@ -544,6 +1066,33 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if cookie != "" {
 		cfg.Request.Cookie = cookie
 	}
+	if auth != "" {
+		cfg.Request.Auth = auth
+	}
+	if authType != "" {
+		cfg.Request.AuthType = authType
+	}
+	if authMap != "" {
+		cfg.Request.AuthMap = authMap
+	}
+	if authMapFile != "" {
+		cfg.Request.AuthMapFile = authMapFile
+	}
+	if preflightURL != "" {
+		cfg.Request.PreflightURL = preflightURL
+	}
+	if tokenRegex != "" {
+		cfg.Request.ExtractTokenRegex = tokenRegex
+	}
+	if tokenHeader != "" {
+		cfg.Request.TokenHeader = tokenHeader
+	}
+	if cacheBust {
+		cfg.Request.CacheBust = true
+	}
+	if cacheBustParam != "" {
+		cfg.Request.CacheBustParam = cacheBustParam
+	}
 
 	// 更新连接配置
 	if timeout > 0 {
@@ -559,13 +1108,22 @@ func updateConfigFromFlags(cfg *config.Config) {
 		cfg.Connection.ProxyFile = proxiesFile
 	}
 	if proxyAuth != "" {
-		// TODO: 实现代理认证
+		cfg.Connection.ProxyAuth = proxyAuth
+	}
+	if proxyRules != "" {
+		cfg.Connection.ProxyRules = proxyRules
 	}
 	if replayProxy != "" {
 		cfg.Connection.ReplayProxy = replayProxy
 	}
+	if len(replayHeaders) > 0 {
+		cfg.Connection.ReplayHeaders = replayHeaders
+	}
 	if tor {
-		// TODO: 实现Tor代理
+		cfg.Connection.Tor = true
+	}
+	if torAddress != "" {
+		cfg.Connection.TorAddress = torAddress
 	}
 	if scheme != "" {
 		cfg.Connection.Scheme = scheme
@@ -573,14 +1131,53 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if maxRate > 0 {
 		cfg.Connection.MaxRate = maxRate
 	}
+	if maxBandwidth != "" {
+		cfg.Connection.MaxBandwidth = maxBandwidth
+	}
 	if retries > 0 {
 		cfg.Connection.MaxRetries = retries
 	}
+	if retryOnStatus != "" {
+		cfg.Connection.RetryOnStatus = retryOnStatus
+	}
 	if ip != "" {
-		// TODO: 实现IP绑定
+		cfg.Connection.IP = ip
 	}
 	if interfaceName != "" {
-		// TODO: 实现网络接口绑定
+		cfg.Connection.Interface = interfaceName
+	}
+	if hostTimeout != "" {
+		cfg.Connection.HostTimeout = hostTimeout
+	}
+	if warmup > 0 {
+		cfg.Connection.Warmup = warmup
+	}
+	if disableKeepAlive {
+		cfg.Connection.DisableKeepAlive = true
+	}
+	if http10 {
+		cfg.Connection.HTTP10 = true
+	}
+	if maxConsecErrors > 0 {
+		cfg.Connection.MaxConsecutiveErrors = maxConsecErrors
+	}
+	if certFile != "" {
+		cfg.Connection.CertFile = certFile
+	}
+	if keyFile != "" {
+		cfg.Connection.KeyFile = keyFile
+	}
+	if aliveStatus != "" {
+		cfg.Connection.AliveStatus = aliveStatus
+	}
+	if chromePath != "" {
+		cfg.Connection.ChromePath = chromePath
+	}
+	if len(chromeFlags) > 0 {
+		cfg.Connection.ChromeFlags = chromeFlags
+	}
+	if headlessWait != "" {
+		cfg.Connection.HeadlessWaitFor = headlessWait
 	}
 
 	// 更新高级配置
@@ -598,6 +1195,9 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if noColor {
 		cfg.View.Color = false
 	}
+	if colorMap != "" {
+		cfg.View.ColorMap = colorMap
+	}
 	if quietMode {
 		cfg.View.QuietMode = true
 	}
@@ -624,10 +1224,47 @@ func updateConfigFromFlags(cfg *config.Config) {
 	if logFile != "" {
 		cfg.Output.LogFile = logFile
 	}
+	if screenshotDir != "" {
+		cfg.Output.ScreenshotDir = screenshotDir
+	}
+	if webhook != "" {
+		cfg.Output.Webhook = webhook
+	}
+	if len(webhookHeader) > 0 {
+		cfg.Output.WebhookHeaders = webhookHeader
+	}
+	if csvBOM {
+		cfg.Output.CSVBOM = true
+	}
+	if outputDir != "" {
+		cfg.Output.AutosaveReport = true
+		cfg.Output.AutosaveReportFolder = outputDir
+	}
+	if outputURLs != "" {
+		cfg.Output.OutputURLs = outputURLs
+	}
+	if flushInterval > 0 {
+		cfg.Output.FlushInterval = flushInterval.Seconds()
+	}
+	if maxTitleLen > 0 {
+		cfg.Output.MaxTitleLength = maxTitleLen
+	}
+	if ciSummary {
+		cfg.Output.CISummary = true
+	}
+}
+
+// newColorManager 按--no-color和--color-map创建颜色管理器
+func newColorManager(cfg *config.Config) (*view.ColorManager, error) {
+	colorMap, err := view.ParseColorMap(cfg.View.ColorMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --color-map: %w", err)
+	}
+	return view.NewColorManager(!noColor, colorMap), nil
 }
 
-// displayResults 显示扫描结果
-func displayResults(results []scanner.ScanResult) {
+// displayResults 显示扫描结果，标题超过maxTitleLength个字符时截断
+func displayResults(results []scanner.ScanResult, colorManager *view.ColorManager, maxTitleLength int) {
 	if len(results) == 0 {
 		fmt.Println("No results found.")
 		return
@@ -638,9 +1275,6 @@ func displayResults(results []scanner.ScanResult) {
 		return
 	}
 
-	// 创建颜色管理器
-	colorManager := view.NewColorManager(!noColor)
-
 	// 状态码筛选
 	if statusFilter != "" {
 		results = filterResultsByStatus(results, statusFilter)
@@ -680,7 +1314,7 @@ func displayResults(results []scanner.ScanResult) {
 		fmt.Printf("[%s] %s%s\n", coloredStatus, coloredURL, coloredPath)
 
 		if result.Title != "" {
-			coloredTitle := colorManager.ColorizeTitle(result.Title)
+			coloredTitle := colorManager.ColorizeTitle(report.TruncateTitle(result.Title, maxTitleLength))
 			fmt.Printf("    Title: %s\n", coloredTitle)
 		}
 		if result.Redirect != "" {
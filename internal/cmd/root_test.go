@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// parseRootFlags 只用根命令的指定参数解析args，返回剩余的位置参数
+func parseRootFlags(t *testing.T, args []string, names ...string) []string {
+	t.Helper()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	for _, name := range names {
+		fs.AddFlag(rootCmd.Flags().Lookup(name))
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse(%q) failed: %v", args, err)
+	}
+	return fs.Args()
+}
+
+func TestWarmupFlagValue(t *testing.T) {
+	t.Cleanup(func() { warmup = 0 })
+
+	for _, args := range [][]string{{"--warmup", "5"}, {"--warmup=5"}} {
+		warmup = 0
+		if rest := parseRootFlags(t, args, "warmup"); len(rest) != 0 {
+			t.Errorf("Parse(%q) left positional args %q", args, rest)
+		}
+		if warmup != 5 {
+			t.Errorf("Parse(%q) warmup = %d, want 5", args, warmup)
+		}
+	}
+}
+
+func TestDumpRequestsFlagValue(t *testing.T) {
+	t.Cleanup(func() { dumpRequests = 0 })
+
+	for _, args := range [][]string{{"--dump-requests", "5"}, {"--dump-requests=5"}} {
+		dumpRequests = 0
+		if rest := parseRootFlags(t, args, "dump-requests"); len(rest) != 0 {
+			t.Errorf("Parse(%q) left positional args %q", args, rest)
+		}
+		if dumpRequests != 5 {
+			t.Errorf("Parse(%q) dumpRequests = %d, want 5", args, dumpRequests)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/connection"
+	"dirsearch-go/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var selfTestEgressHost string
+
+// errSelfTestSkipped 自检项不适用时返回
+var errSelfTestSkipped = errors.New("skipped")
+
+// selftestCmd 环境自检子命令
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check the environment: Chrome, proxies, database driver, output folder and network",
+	Long: `Run a series of environment checks and print a pass/fail table:
+Chrome availability for headless mode, proxy reachability, database driver
+presence, write access to the report folder and network egress to a known host.`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetConfig()
+		if cfg == nil {
+			return fmt.Errorf("failed to get configuration")
+		}
+		updateConfigFromFlags(cfg)
+
+		results := runSelfTest(selfTestChecks(cfg, defaultSelfTestEnv()))
+		if failed := printSelfTestResults(os.Stdout, results); failed > 0 {
+			return fmt.Errorf("self-test failed: %d check(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+// init 注册环境自检子命令
+func init() {
+	selftestCmd.Flags().StringVarP(&proxy, "proxy", "p", "", "Proxy URL to check")
+	selftestCmd.Flags().StringVar(&replayProxy, "replay-proxy", "", "Replay proxy URL to check")
+	selftestCmd.Flags().StringVar(&chromePath, "chrome-path", "", "Chrome/Chromium executable to check")
+	selftestCmd.Flags().StringVar(&selfTestEgressHost, "egress-host", "example.com:443", "host:port used to check network egress")
+	rootCmd.AddCommand(selftestCmd)
+}
+
+// selfTestEnv 自检依赖的外部操作，测试时可替换
+type selfTestEnv struct {
+	startBrowser func(cfg *config.Config) error
+	dial         func(address string) error
+	drivers      func() []string
+	reportDir    func(cfg *config.Config) (string, error)
+	egressHost   string
+}
+
+// defaultSelfTestEnv 使用现有的无头浏览器、网络和报告组件
+func defaultSelfTestEnv() selfTestEnv {
+	return selfTestEnv{
+		startBrowser: func(cfg *config.Config) error {
+			browser, err := connection.NewHeadlessBrowser(cfg)
+			if err != nil {
+				return err
+			}
+			browser.Close()
+			return nil
+		},
+		dial: func(address string) error {
+			conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+		drivers: sql.Drivers,
+		reportDir: func(cfg *config.Config) (string, error) {
+			reporter, err := report.NewReporter(cfg)
+			if err != nil {
+				return "", err
+			}
+			if err := reporter.CreateReportDirectory(); err != nil {
+				return "", err
+			}
+			return reporter.ReportDirectory(), nil
+		},
+		egressHost: selfTestEgressHost,
+	}
+}
+
+// selfTestCheck 自检项，Run返回检查详情
+type selfTestCheck struct {
+	Name string
+	Run  func() (string, error)
+}
+
+// selfTestResult 自检结果
+type selfTestResult struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// Status 自检状态：PASS、FAIL或SKIP
+func (r selfTestResult) Status() string {
+	switch {
+	case errors.Is(r.Err, errSelfTestSkipped):
+		return "SKIP"
+	case r.Err != nil:
+		return "FAIL"
+	default:
+		return "PASS"
+	}
+}
+
+// selfTestChecks 根据配置生成自检项
+func selfTestChecks(cfg *config.Config, env selfTestEnv) []selfTestCheck {
+	proxyCheck := func(proxyURL string) func() (string, error) {
+		return func() (string, error) {
+			if proxyURL == "" {
+				return "not configured", errSelfTestSkipped
+			}
+			// 输出中隐藏代理URL中的密码
+			detail := config.RedactProxyURL(proxyURL)
+			address, err := proxyAddress(proxyURL)
+			if err != nil {
+				return detail, fmt.Errorf("invalid proxy URL")
+			}
+			if err := env.dial(address); err != nil {
+				return detail, fmt.Errorf("unreachable: %w", err)
+			}
+			return detail, nil
+		}
+	}
+
+	return []selfTestCheck{
+		{
+			Name: "Chrome (headless)",
+			Run: func() (string, error) {
+				detail := "default Chrome/Chromium"
+				if cfg.Connection.ChromePath != "" {
+					detail = cfg.Connection.ChromePath
+				}
+				if err := env.startBrowser(cfg); err != nil {
+					return detail, err
+				}
+				return detail, nil
+			},
+		},
+		{Name: "Proxy", Run: proxyCheck(cfg.Connection.Proxy)},
+		{Name: "Replay proxy", Run: proxyCheck(cfg.Connection.ReplayProxy)},
+		{
+			Name: "Database driver",
+			Run: func() (string, error) {
+				drivers := env.drivers()
+				for _, driver := range drivers {
+					if driver == "mysql" {
+						return "mysql", nil
+					}
+				}
+				return strings.Join(drivers, ", "), fmt.Errorf("mysql driver not registered")
+			},
+		},
+		{
+			Name: "Report folder",
+			Run: func() (string, error) {
+				dir, err := env.reportDir(cfg)
+				if err != nil {
+					return dir, fmt.Errorf("failed to create report folder: %w", err)
+				}
+				file, err := os.CreateTemp(dir, ".selftest-*")
+				if err != nil {
+					return dir, fmt.Errorf("not writable: %w", err)
+				}
+				file.Close()
+				os.Remove(file.Name())
+				return dir, nil
+			},
+		},
+		{
+			Name: "Network egress",
+			Run: func() (string, error) {
+				if err := env.dial(env.egressHost); err != nil {
+					return env.egressHost, err
+				}
+				return env.egressHost, nil
+			},
+		},
+	}
+}
+
+// runSelfTest 依次执行自检项
+func runSelfTest(checks []selfTestCheck) []selfTestResult {
+	results := make([]selfTestResult, 0, len(checks))
+	for _, check := range checks {
+		detail, err := check.Run()
+		results = append(results, selfTestResult{Name: check.Name, Detail: detail, Err: err})
+	}
+	return results
+}
+
+// printSelfTestResults 打印自检结果表格，返回失败项数量
+func printSelfTestResults(w io.Writer, results []selfTestResult) int {
+	failed := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+	for _, result := range results {
+		detail := result.Detail
+		if result.Status() == "FAIL" {
+			failed++
+			detail = strings.TrimSpace(detail + " (" + result.Err.Error() + ")")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Name, result.Status(), detail)
+	}
+	tw.Flush()
+	return failed
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestSelfTestReportsEachCheck(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Connection.Proxy = "http://127.0.0.1:8080"
+	cfg.Output.AutosaveReportFolder = t.TempDir()
+
+	var dialed []string
+	env := selfTestEnv{
+		startBrowser: func(*config.Config) error {
+			return errors.New("executable file not found")
+		},
+		dial: func(address string) error {
+			dialed = append(dialed, address)
+			if address == "127.0.0.1:8080" {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+		drivers: func() []string { return []string{"mysql"} },
+		reportDir: func(cfg *config.Config) (string, error) {
+			return cfg.Output.AutosaveReportFolder, nil
+		},
+		egressHost: "example.com:443",
+	}
+
+	results := runSelfTest(selfTestChecks(cfg, env))
+
+	expected := map[string]string{
+		"Chrome (headless)": "FAIL",
+		"Proxy":             "FAIL",
+		"Replay proxy":      "SKIP",
+		"Database driver":   "PASS",
+		"Report folder":     "PASS",
+		"Network egress":    "PASS",
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Got %d results, want %d: %+v", len(results), len(expected), results)
+	}
+	for _, result := range results {
+		if want, ok := expected[result.Name]; !ok || result.Status() != want {
+			t.Errorf("Check %q status = %s, want %s (err: %v)", result.Name, result.Status(), want, result.Err)
+		}
+	}
+	if strings.Join(dialed, ",") != "127.0.0.1:8080,example.com:443" {
+		t.Errorf("Dialed %v, want proxy and egress host", dialed)
+	}
+
+	var out bytes.Buffer
+	if failed := printSelfTestResults(&out, results); failed != 2 {
+		t.Errorf("printSelfTestResults() = %d failed, want 2", failed)
+	}
+	table := out.String()
+	for name, status := range expected {
+		found := false
+		for _, line := range strings.Split(table, "\n") {
+			if strings.HasPrefix(line, name) && strings.Contains(line, status) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Table missing %s row with status %s:\n%s", name, status, table)
+		}
+	}
+	if !strings.Contains(table, "connection refused") {
+		t.Errorf("Expected failure reason in table:\n%s", table)
+	}
+}
+
+func TestSelfTestMissingDriver(t *testing.T) {
+	env := selfTestEnv{
+		startBrowser: func(*config.Config) error { return nil },
+		dial:         func(string) error { return nil },
+		drivers:      func() []string { return nil },
+		reportDir:    func(*config.Config) (string, error) { return t.TempDir(), nil },
+	}
+
+	for _, result := range runSelfTest(selfTestChecks(&config.Config{}, env)) {
+		if result.Name == "Database driver" && result.Status() != "FAIL" {
+			t.Errorf("Expected missing mysql driver to fail, got %s", result.Status())
+		}
+	}
+}
@@ -2,10 +2,14 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -24,42 +28,77 @@ type Config struct {
 
 // GeneralConfig 通用配置
 type GeneralConfig struct {
-	Threads           int      `mapstructure:"threads"`
-	Async             bool     `mapstructure:"async"`
-	Recursive         bool     `mapstructure:"recursive"`
-	DeepRecursive     bool     `mapstructure:"deep-recursive"`
-	ForceRecursive    bool     `mapstructure:"force-recursive"`
-	RecursionStatus   []string `mapstructure:"recursion-status"`
-	MaxRecursionDepth int      `mapstructure:"max-recursion-depth"`
-	ExcludeSubdirs    []string `mapstructure:"exclude-subdirs"`
-	RandomUserAgents  bool     `mapstructure:"random-user-agents"`
-	MaxTime           int      `mapstructure:"max-time"`
-	ExitOnError       bool     `mapstructure:"exit-on-error"`
-	IncludeStatus     []string `mapstructure:"include-status"`
-	ExcludeStatus     []string `mapstructure:"exclude-status"`
-	ExcludeSizes      []string `mapstructure:"exclude-sizes"`
-	ExcludeText       []string `mapstructure:"exclude-text"`
-	ExcludeRegex      []string `mapstructure:"exclude-regex"`
-	ExcludeRedirect   []string `mapstructure:"exclude-redirect"`
-	ExcludeResponse   []string `mapstructure:"exclude-response"`
-	SkipOnStatus      []string `mapstructure:"skip-on-status"`
-	MinResponseSize   int      `mapstructure:"min-response-size"`
-	MaxResponseSize   int      `mapstructure:"max-response-size"`
+	Threads              int      `mapstructure:"threads"`
+	Async                bool     `mapstructure:"async"`
+	Recursive            bool     `mapstructure:"recursive"`
+	DeepRecursive        bool     `mapstructure:"deep-recursive"`
+	ForceRecursive       bool     `mapstructure:"force-recursive"`
+	RecursionStatus      []string `mapstructure:"recursion-status"`
+	NoRecursionStatus    []string `mapstructure:"recursion-exclude-status"`
+	MaxRecursionDepth    int      `mapstructure:"max-recursion-depth"`
+	Subdirs              []string `mapstructure:"subdirs"`
+	ExcludeSubdirs       []string `mapstructure:"exclude-subdirs"`
+	RandomUserAgents     bool     `mapstructure:"random-user-agents"`
+	MaxTime              int      `mapstructure:"max-time"`
+	ExitOnError          bool     `mapstructure:"exit-on-error"`
+	ExitCodes            bool     `mapstructure:"exit-codes"`
+	IncludeStatus        []string `mapstructure:"include-status"`
+	ExcludeStatus        []string `mapstructure:"exclude-status"`
+	ExcludeSizes         []string `mapstructure:"exclude-sizes"`
+	ExcludeLengthSimilar []string `mapstructure:"exclude-length-similar"`
+	ExcludeText          []string `mapstructure:"exclude-text"`
+	Known404             []string `mapstructure:"known-404"`
+	ExcludeRegex         []string `mapstructure:"exclude-regex"`
+	ExcludeRedirect      []string `mapstructure:"exclude-redirect"`
+	ExcludeResponse      []string `mapstructure:"exclude-response"`
+	SkipOnStatus         []string `mapstructure:"skip-on-status"`
+	MinResponseSize      int      `mapstructure:"min-response-size"`
+	MaxResponseSize      int      `mapstructure:"max-response-size"`
+	MinResponseTime      int      `mapstructure:"min-response-time"`
+	MaxResponseTime      int      `mapstructure:"max-response-time"`
+	Interactive          bool     `mapstructure:"interactive"`
+	Fingerprint          bool     `mapstructure:"fingerprint"`
+	RetryErrors          string   `mapstructure:"retry-errors"`
+	TwoPhase             bool     `mapstructure:"two-phase"`
+	TryWWW               bool     `mapstructure:"try-www"`
+	Shuffle              bool     `mapstructure:"shuffle"`
+	ShuffleSeed          int64    `mapstructure:"shuffle-seed"`
+	CheckBackups         bool     `mapstructure:"check-backups"`
+	BackupSuffixes       []string `mapstructure:"backup-suffixes"`
+	DumpRequests         int      `mapstructure:"dump-requests"`
+	ProbeMethods         bool     `mapstructure:"probe-methods"`
+	DetectWAF            bool     `mapstructure:"detect-waf"`
+	ExcludeBlocked       bool     `mapstructure:"exclude-blocked"`
+	StartPaths           []string `mapstructure:"start-paths"`
+	DedupBy              string   `mapstructure:"dedup-by"`
+	Skip404After         int      `mapstructure:"skip-404-after"`
+	SessionFile          string   `mapstructure:"session-file"`
+	ParamFuzz            bool     `mapstructure:"param-fuzz"`
+	ScanID               string   `mapstructure:"scan-id"`
 }
 
 // DictionaryConfig 字典配置
 type DictionaryConfig struct {
-	DefaultExtensions   []string     `mapstructure:"default-extensions"`
-	ForceExtensions     bool         `mapstructure:"force-extensions"`
-	OverwriteExtensions bool         `mapstructure:"overwrite-extensions"`
-	Lowercase           bool         `mapstructure:"lowercase"`
-	Uppercase           bool         `mapstructure:"uppercase"`
-	Capitalization      bool         `mapstructure:"capitalization"`
-	ExcludeExtensions   []string     `mapstructure:"exclude-extensions"`
-	Prefixes            []string     `mapstructure:"prefixes"`
-	Suffixes            []string     `mapstructure:"suffixes"`
-	Wordlists           []string     `mapstructure:"wordlists"`
-	Source              SourceConfig `mapstructure:"source"`
+	DefaultExtensions    []string     `mapstructure:"default-extensions"`
+	ForceExtensions      bool         `mapstructure:"force-extensions"`
+	OverwriteExtensions  bool         `mapstructure:"overwrite-extensions"`
+	RemoveExtensions     bool         `mapstructure:"remove-extensions"`
+	Lowercase            bool         `mapstructure:"lowercase"`
+	Uppercase            bool         `mapstructure:"uppercase"`
+	Capitalization       bool         `mapstructure:"capitalization"`
+	ExcludeExtensions    []string     `mapstructure:"exclude-extensions"`
+	Prefixes             []string     `mapstructure:"prefixes"`
+	Suffixes             []string     `mapstructure:"suffixes"`
+	Wordlists            []string     `mapstructure:"wordlists"`
+	WordlistExtensions   []string     `mapstructure:"wordlist-extensions"`
+	PriorityFile         string       `mapstructure:"priority-file"`
+	LowMemory            bool         `mapstructure:"low-memory"`
+	IgnoreWordlistErrors bool         `mapstructure:"ignore-wordlist-errors"`
+	CaseInsensitiveDedup bool         `mapstructure:"case-insensitive-dedup"`
+	AppendSlash          bool         `mapstructure:"append-slash"`
+	Tech                 []string     `mapstructure:"tech"`
+	AutoTech             bool         `mapstructure:"auto-tech"`
+	Source               SourceConfig `mapstructure:"source"`
 }
 
 // SourceConfig wordlist源配置
@@ -78,33 +117,62 @@ type SourceConfig struct {
 
 // RequestConfig 请求配置
 type RequestConfig struct {
-	HTTPMethod      string   `mapstructure:"http-method"`
-	FollowRedirects bool     `mapstructure:"follow-redirects"`
-	HeadersFile     string   `mapstructure:"headers-file"`
-	UserAgent       string   `mapstructure:"user-agent"`
-	Cookie          string   `mapstructure:"cookie"`
-	Data            string   `mapstructure:"data"`
-	DataFile        string   `mapstructure:"data-file"`
-	Headers         []string `mapstructure:"headers"`
-	Auth            string   `mapstructure:"auth"`
-	AuthType        string   `mapstructure:"auth-type"`
+	HTTPMethod        string   `mapstructure:"http-method"`
+	FollowRedirects   bool     `mapstructure:"follow-redirects"`
+	StayInScope       bool     `mapstructure:"stay-in-scope"`
+	HeadersFile       string   `mapstructure:"headers-file"`
+	UserAgent         string   `mapstructure:"user-agent"`
+	Cookie            string   `mapstructure:"cookie"`
+	Data              string   `mapstructure:"data"`
+	DataFile          string   `mapstructure:"data-file"`
+	Multipart         bool     `mapstructure:"multipart"`
+	Headers           []string `mapstructure:"headers"`
+	Auth              string   `mapstructure:"auth"`
+	AuthType          string   `mapstructure:"auth-type"`
+	AuthMap           string   `mapstructure:"auth-map"`
+	AuthMapFile       string   `mapstructure:"auth-map-file"`
+	PreflightURL      string   `mapstructure:"preflight-url"`
+	ExtractTokenRegex string   `mapstructure:"extract-token-regex"`
+	TokenHeader       string   `mapstructure:"token-header"`
+	CacheBust         bool     `mapstructure:"cache-bust"`
+	CacheBustParam    string   `mapstructure:"cache-bust-param"`
 }
 
 // ConnectionConfig 连接配置
 type ConnectionConfig struct {
-	Timeout             float64  `mapstructure:"timeout"`
-	Delay               float64  `mapstructure:"delay"`
-	MaxRate             int      `mapstructure:"max-rate"`
-	MaxRetries          int      `mapstructure:"max-retries"`
-	DomainCheckTimeout  float64  `mapstructure:"domain-check-timeout"`
-	DomainCheckRetries  int      `mapstructure:"domain-check-retries"`
-	HeadlessTimeout     float64  `mapstructure:"headless-timeout"`
-	HeadlessConcurrency int      `mapstructure:"headless-concurrency"`
-	Scheme              string   `mapstructure:"scheme"`
-	Proxy               string   `mapstructure:"proxy"`
-	ProxyFile           string   `mapstructure:"proxy-file"`
-	ReplayProxy         string   `mapstructure:"replay-proxy"`
-	Proxies             []string `mapstructure:"proxies"`
+	Timeout              float64  `mapstructure:"timeout"`
+	Delay                float64  `mapstructure:"delay"`
+	MaxRate              int      `mapstructure:"max-rate"`
+	MaxBandwidth         string   `mapstructure:"max-bandwidth"`
+	MaxRetries           int      `mapstructure:"max-retries"`
+	RetryOnStatus        string   `mapstructure:"retry-on-status"`
+	DomainCheckTimeout   float64  `mapstructure:"domain-check-timeout"`
+	DomainCheckRetries   int      `mapstructure:"domain-check-retries"`
+	AliveStatus          string   `mapstructure:"alive-status"`
+	HeadlessTimeout      float64  `mapstructure:"headless-timeout"`
+	HeadlessConcurrency  int      `mapstructure:"headless-concurrency"`
+	ChromePath           string   `mapstructure:"chrome-path"`
+	ChromeFlags          []string `mapstructure:"chrome-flags"`
+	HeadlessWaitFor      string   `mapstructure:"headless-wait-for"`
+	Scheme               string   `mapstructure:"scheme"`
+	Proxy                string   `mapstructure:"proxy"`
+	ProxyRules           string   `mapstructure:"proxy-rules"`
+	ProxyAuth            string   `mapstructure:"proxy-auth"`
+	Tor                  bool     `mapstructure:"tor"`
+	TorAddress           string   `mapstructure:"tor-address"`
+	ProxyFile            string   `mapstructure:"proxy-file"`
+	ReplayProxy          string   `mapstructure:"replay-proxy"`
+	ReplayHeaders        []string `mapstructure:"replay-headers"`
+	Proxies              []string `mapstructure:"proxies"`
+	HostTimeout          string   `mapstructure:"host-timeout"`
+	Warmup               int      `mapstructure:"warmup"`
+	IP                   string   `mapstructure:"ip"`
+	Interface            string   `mapstructure:"interface"`
+	DisableKeepAlive     bool     `mapstructure:"disable-keepalive"`
+	MaxConsecutiveErrors int      `mapstructure:"max-consecutive-errors"`
+	CertFile             string   `mapstructure:"cert-file"`
+	KeyFile              string   `mapstructure:"key-file"`
+	HTTP10               bool     `mapstructure:"http10"`
 }
 
 // AdvancedConfig 高级配置
@@ -114,23 +182,32 @@ type AdvancedConfig struct {
 
 // ViewConfig 视图配置
 type ViewConfig struct {
-	FullURL              bool `mapstructure:"full-url"`
-	QuietMode            bool `mapstructure:"quiet-mode"`
-	Color                bool `mapstructure:"color"`
-	ShowRedirectsHistory bool `mapstructure:"show-redirects-history"`
-	RealTimeStatus       bool `mapstructure:"real-time-status"`
-	Headless             bool `mapstructure:"headless"`
-	ShowAllStatus        bool `mapstructure:"show-all-status"`
-	RecursiveScan        bool `mapstructure:"recursive-scan"`
+	FullURL              bool   `mapstructure:"full-url"`
+	QuietMode            bool   `mapstructure:"quiet-mode"`
+	Color                bool   `mapstructure:"color"`
+	ShowRedirectsHistory bool   `mapstructure:"show-redirects-history"`
+	RealTimeStatus       bool   `mapstructure:"real-time-status"`
+	Headless             bool   `mapstructure:"headless"`
+	ShowAllStatus        bool   `mapstructure:"show-all-status"`
+	RecursiveScan        bool   `mapstructure:"recursive-scan"`
+	ColorMap             string `mapstructure:"color-map"`
 }
 
 // OutputConfig 输出配置
 type OutputConfig struct {
-	ReportFormat         string `mapstructure:"report-format"`
-	AutosaveReport       bool   `mapstructure:"autosave-report"`
-	AutosaveReportFolder string `mapstructure:"autosave-report-folder"`
-	LogFile              string `mapstructure:"log-file"`
-	LogFileSize          int    `mapstructure:"log-file-size"`
+	ReportFormat         string   `mapstructure:"report-format"`
+	AutosaveReport       bool     `mapstructure:"autosave-report"`
+	AutosaveReportFolder string   `mapstructure:"autosave-report-folder"`
+	LogFile              string   `mapstructure:"log-file"`
+	LogFileSize          int      `mapstructure:"log-file-size"`
+	ScreenshotDir        string   `mapstructure:"screenshots"`
+	CSVBOM               bool     `mapstructure:"csv-bom"`
+	Webhook              string   `mapstructure:"webhook"`
+	WebhookHeaders       []string `mapstructure:"webhook-headers"`
+	OutputURLs           string   `mapstructure:"output-urls"`
+	FlushInterval        float64  `mapstructure:"flush-interval"`
+	CISummary            bool     `mapstructure:"ci-summary"`
+	MaxTitleLength       int      `mapstructure:"max-title-length"`
 }
 
 var (
@@ -140,14 +217,17 @@ var (
 	ConfigFile string
 )
 
-// Init 初始化配置
-func Init() error {
+// Init 初始化配置，configFile为--config指定的配置文件，为空时按DIRSEARCH_CONFIG或自动查找
+func Init(configFile string) error {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("Config Init panic recovered: %v\nStack trace: %s", r, debug.Stack())
 		}
 	}()
 
+	// 重置之前的配置状态
+	viper.Reset()
+
 	// 1. 优先加载.env文件（忽略错误）
 	envFiles := []string{".env", ".env.local", ".env.production"}
 	for _, envFile := range envFiles {
@@ -162,36 +242,40 @@ func Init() error {
 	// 3. 设置默认值
 	setDefaults()
 
-	// 4. 尝试读取config.ini（如果存在且格式正确）
-	configName := "config.ini"
-	if envConfig := os.Getenv("DIRSEARCH_CONFIG"); envConfig != "" {
-		configName = envConfig
-	}
-
-	// 查找配置文件
-	configPaths := []string{
-		".",
-		"./config",
-		"./conf",
-		os.Getenv("HOME"),
-		os.Getenv("USERPROFILE"),
-	}
-
-	for _, path := range configPaths {
-		if path != "" {
-			configFile := filepath.Join(path, configName)
-			if _, err := os.Stat(configFile); err == nil {
-				// 尝试读取配置文件
-				viper.SetConfigFile(configFile)
-				viper.SetConfigType("ini")
-				if err := viper.ReadInConfig(); err == nil {
-					// 成功读取配置文件
-					ConfigFile = configFile
-					break
-				} else {
-					log.Printf("Warning: Failed to read config file %s: %v", configFile, err)
-				}
+	// 4. 查找并读取配置文件
+	ConfigFile = ""
+	if configFile != "" {
+		// --config指定的配置文件必须存在且格式正确，此时不再自动查找config.ini
+		if _, err := os.Stat(configFile); err != nil {
+			return fmt.Errorf("config file %s not found: %w", configFile, err)
+		}
+		if err := readConfigFile(configFile); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", configFile, err)
+		}
+		ConfigFile = configFile
+	} else if envConfig := os.Getenv("DIRSEARCH_CONFIG"); envConfig != "" {
+		// 显式指定的配置文件必须存在且格式正确
+		envFile := findConfigFile(envConfig)
+		if envFile == "" {
+			return fmt.Errorf("config file %s (from DIRSEARCH_CONFIG) not found", envConfig)
+		}
+		if err := readConfigFile(envFile); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", envFile, err)
+		}
+		ConfigFile = envFile
+	} else {
+		// 自动查找的config.ini格式错误时给出警告并继续查找
+		for _, path := range configSearchPaths() {
+			discovered := filepath.Join(path, "config.ini")
+			if _, err := os.Stat(discovered); err != nil {
+				continue
+			}
+			if err := readConfigFile(discovered); err != nil {
+				log.Printf("Warning: Ignoring malformed config file %s: %v", discovered, err)
+				continue
 			}
+			ConfigFile = discovered
+			break
 		}
 	}
 
@@ -199,22 +283,86 @@ func Init() error {
 	if ConfigFile == "" {
 		log.Println("No valid config file found, using built-in default configuration")
 		// 使用内置默认配置
-		if err := viper.ReadConfig(strings.NewReader(defaultConfigINI)); err != nil {
+		if err := viper.ReadConfig(defaultConfigReader()); err != nil {
 			return fmt.Errorf("failed to load default config: %w", err)
 		}
+	} else {
+		log.Printf("Loaded config file: %s", ConfigFile)
+	}
+
+	// 6. 解析并验证配置
+	return unmarshalConfig()
+}
+
+// InitDefaults 只使用内置默认配置初始化（--no-config），忽略.env、环境变量和所有配置文件
+func InitDefaults() error {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Config InitDefaults panic recovered: %v\nStack trace: %s", r, debug.Stack())
+		}
+	}()
+
+	viper.Reset()
+	setDefaults()
+
+	ConfigFile = ""
+	viper.SetConfigType("ini")
+	if err := viper.ReadConfig(defaultConfigReader()); err != nil {
+		return fmt.Errorf("failed to load default config: %w", err)
+	}
+	log.Println("Ignoring config files and environment (--no-config), using built-in default configuration")
+
+	return unmarshalConfig()
+}
+
+// configSearchPaths 配置文件的查找目录
+func configSearchPaths() []string {
+	var paths []string
+	for _, path := range []string{".", "./config", "./conf", os.Getenv("HOME"), os.Getenv("USERPROFILE")} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// findConfigFile 查找配置文件，name可以是路径或在查找目录中的文件名
+func findConfigFile(name string) string {
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	if filepath.IsAbs(name) {
+		return ""
+	}
+	for _, path := range configSearchPaths() {
+		configFile := filepath.Join(path, name)
+		if _, err := os.Stat(configFile); err == nil {
+			return configFile
+		}
 	}
+	return ""
+}
+
+// readConfigFile 使用INI格式读取配置文件
+func readConfigFile(path string) error {
+	viper.SetConfigFile(path)
+	viper.SetConfigType("ini")
+	return viper.ReadInConfig()
+}
 
-	// 6. 解析配置
-	GlobalConfig = &Config{}
-	if err := viper.Unmarshal(GlobalConfig); err != nil {
+// unmarshalConfig 将viper中的配置解析到全局配置并验证
+func unmarshalConfig() error {
+	cfg := &Config{}
+	if err := viper.Unmarshal(cfg); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// 7. 验证配置
-	if err := validateConfig(GlobalConfig); err != nil {
+	// 验证配置
+	if err := validateConfig(cfg); err != nil {
 		log.Printf("Warning: Config validation failed: %v", err)
 	}
 
+	GlobalConfig = cfg
 	return nil
 }
 
@@ -506,6 +654,253 @@ func ParseStatusCodes(statusStr string) ([]int, error) {
 	return codes, nil
 }
 
+// ParseHostTimeouts 解析按主机覆盖的超时配置，如 "slow.example.com=30,fast.example.com=3"
+func ParseHostTimeouts(mapping string) (map[string]float64, error) {
+	timeouts := make(map[string]float64)
+	if strings.TrimSpace(mapping) == "" {
+		return timeouts, nil
+	}
+
+	for _, part := range strings.Split(mapping, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid host timeout entry %q, expected host=seconds", part)
+		}
+
+		host := strings.ToLower(strings.TrimSpace(kv[0]))
+		if host == "" {
+			return nil, fmt.Errorf("invalid host timeout entry %q: empty host", part)
+		}
+
+		var seconds float64
+		if _, err := fmt.Sscanf(strings.TrimSpace(kv[1]), "%g", &seconds); err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid host timeout entry %q: timeout must be a positive number", part)
+		}
+
+		timeouts[host] = seconds
+	}
+
+	return timeouts, nil
+}
+
+// parseAuthEntry 解析"host=credential"格式的按主机认证条目，错误信息中不包含凭据
+func parseAuthEntry(entry string) (string, string, error) {
+	host, credential, ok := strings.Cut(entry, "=")
+	host = strings.ToLower(strings.TrimSpace(host))
+	credential = strings.TrimSpace(credential)
+	if !ok || host == "" {
+		return "", "", fmt.Errorf("invalid auth entry, expected host=credential")
+	}
+	if credential == "" {
+		return "", "", fmt.Errorf("invalid auth entry for %s: empty credential", host)
+	}
+	return host, credential, nil
+}
+
+// ParseAuthMap 解析按主机配置的认证凭据，如 "api.example.com=token1,admin.example.com:8443=token2"
+func ParseAuthMap(mapping string) (map[string]string, error) {
+	credentials := make(map[string]string)
+	for _, part := range strings.Split(mapping, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		host, credential, err := parseAuthEntry(part)
+		if err != nil {
+			return nil, err
+		}
+		credentials[host] = credential
+	}
+	return credentials, nil
+}
+
+// ReadAuthMapFile 读取按主机配置认证凭据的文件，每行一个"host=credential"，忽略空行和#注释
+func ReadAuthMapFile(filename string) (map[string]string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth map file: %w", err)
+	}
+
+	credentials := make(map[string]string)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		host, credential, err := parseAuthEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", filename, i+1, err)
+		}
+		credentials[host] = credential
+	}
+	return credentials, nil
+}
+
+// ProxyRule 按主机选择代理的规则，Proxy为nil表示直连
+type ProxyRule struct {
+	Pattern string
+	Proxy   *url.URL
+}
+
+// Matches 判断主机名（不含端口）是否匹配规则的通配符模式，不区分大小写
+func (r ProxyRule) Matches(host string) bool {
+	matched, err := path.Match(r.Pattern, strings.ToLower(host))
+	return err == nil && matched
+}
+
+// ParseProxyRules 解析按主机路由的代理规则，如 "*.internal=direct;*=socks5://127.0.0.1:1080"，
+// 规则按顺序匹配，第一条匹配的规则生效
+func ParseProxyRules(spec string) ([]ProxyRule, error) {
+	var rules []ProxyRule
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pattern, target, ok := strings.Cut(part, "=")
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		target = strings.TrimSpace(target)
+		if !ok || pattern == "" || target == "" {
+			return nil, fmt.Errorf("invalid proxy rule %q, expected host-pattern=direct|proxy-url", RedactProxyURL(part))
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid proxy rule for %q: bad host pattern: %w", pattern, err)
+		}
+
+		rule := ProxyRule{Pattern: pattern}
+		if !strings.EqualFold(target, "direct") {
+			if err := validateProxyURL(target); err != nil {
+				return nil, fmt.Errorf("invalid proxy rule for %q: %w", pattern, err)
+			}
+			rule.Proxy, _ = url.Parse(target)
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no proxy rules in %q", spec)
+	}
+	return rules, nil
+}
+
+// SizeRange 响应长度范围（包含两端）
+type SizeRange struct {
+	Min int64
+	Max int64
+}
+
+// Contains 判断长度是否在范围内
+func (r SizeRange) Contains(size int64) bool {
+	return size >= r.Min && size <= r.Max
+}
+
+// ParseLengthSimilar 解析近似长度配置，如 "1234±20"、"1234+-20" 或按百分比 "1234±5%"
+func ParseLengthSimilar(spec string) (SizeRange, error) {
+	spec = strings.TrimSpace(spec)
+	base, tolerance, found := strings.Cut(spec, "±")
+	if !found {
+		base, tolerance, found = strings.Cut(spec, "+-")
+	}
+	if !found {
+		return SizeRange{}, fmt.Errorf("invalid length %q, expected <size>±<tolerance>", spec)
+	}
+
+	baseline, err := strconv.ParseInt(strings.TrimSpace(base), 10, 64)
+	if err != nil || baseline < 0 {
+		return SizeRange{}, fmt.Errorf("invalid length %q: size must be a non-negative integer", spec)
+	}
+
+	tolerance = strings.TrimSpace(tolerance)
+	percent := strings.HasSuffix(tolerance, "%")
+	value, err := strconv.ParseFloat(strings.TrimSuffix(tolerance, "%"), 64)
+	if err != nil || value < 0 {
+		return SizeRange{}, fmt.Errorf("invalid length %q: tolerance must be a non-negative number", spec)
+	}
+
+	delta := int64(value)
+	if percent {
+		delta = int64(float64(baseline) * value / 100)
+	}
+
+	low := baseline - delta
+	if low < 0 {
+		low = 0
+	}
+	return SizeRange{Min: low, Max: baseline + delta}, nil
+}
+
+// Known404 已知的404响应特征
+type Known404 struct {
+	Size int64  // 响应大小，-1表示不按大小匹配
+	Text string // 响应体中包含的文本，为空表示不按文本匹配
+}
+
+// Matches 判断响应是否符合已知的404特征
+func (k Known404) Matches(size int64, body string) bool {
+	if k.Size >= 0 {
+		return size == k.Size
+	}
+	return k.Text != "" && strings.Contains(body, k.Text)
+}
+
+// ParseKnown404 解析已知404特征，如 "size=1234" 或 "text=Custom Not Found"
+func ParseKnown404(spec string) (Known404, error) {
+	key, value, found := strings.Cut(spec, "=")
+	if !found {
+		return Known404{}, fmt.Errorf("invalid 404 signature %q, expected size=<bytes> or text=<marker>", spec)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "size":
+		size, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || size < 0 {
+			return Known404{}, fmt.Errorf("invalid 404 signature %q: size must be a non-negative integer", spec)
+		}
+		return Known404{Size: size}, nil
+	case "text":
+		if value == "" {
+			return Known404{}, fmt.Errorf("invalid 404 signature %q: text cannot be empty", spec)
+		}
+		return Known404{Size: -1, Text: value}, nil
+	default:
+		return Known404{}, fmt.Errorf("invalid 404 signature %q: unknown key %q", spec, key)
+	}
+}
+
+// ParseByteSize 解析字节大小，如 "512"、"64kb"、"1mb"、"1g"（按1024换算，不区分大小写）
+func ParseByteSize(spec string) (int64, error) {
+	value := strings.ToLower(strings.TrimSpace(spec))
+	units := []struct {
+		suffix string
+		scale  int64
+	}{
+		{suffix: "gb", scale: 1 << 30}, {suffix: "g", scale: 1 << 30},
+		{suffix: "mb", scale: 1 << 20}, {suffix: "m", scale: 1 << 20},
+		{suffix: "kb", scale: 1 << 10}, {suffix: "k", scale: 1 << 10},
+		{suffix: "b", scale: 1},
+	}
+
+	scale := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			scale = unit.scale
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a positive number with optional unit (b, kb, mb, gb)", spec)
+	}
+	return int64(number * float64(scale)), nil
+}
+
 // parseInt 安全解析整数
 func parseInt(s string) (int, error) {
 	defer func() {
@@ -519,6 +914,20 @@ func parseInt(s string) (int, error) {
 	return result, err
 }
 
+// defaultConfigReader 返回内置默认配置的读取器。INI解析会把"key = []"读成[]string{"[]"}，
+// 因此去掉这些空列表行，让对应的键保持零值
+func defaultConfigReader() io.Reader {
+	lines := strings.Split(defaultConfigINI, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasSuffix(strings.TrimSpace(line), "= []") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.NewReader(strings.Join(kept, "\n"))
+}
+
 // defaultConfigINI 内置默认配置（完整的INI格式）
 const defaultConfigINI = `[general]
 threads = 25
@@ -527,24 +936,51 @@ recursive = false
 deep-recursive = false
 force-recursive = false
 max-recursion-depth = 3
+recursion-exclude-status = []
+subdirs = []
 random-user-agents = false
 max-time = 0
 exit-on-error = false
+exit-codes = false
 include-status = []
 exclude-status = []
 exclude-sizes = []
+exclude-length-similar = []
 exclude-text = []
+known-404 = []
 exclude-regex = []
 exclude-redirect = []
 exclude-response = []
 skip-on-status = []
 min-response-size = 0
 max-response-size = 0
+min-response-time = 0
+max-response-time = 0
+interactive = false
+fingerprint = false
+retry-errors = ""
+two-phase = false
+try-www = false
+shuffle = false
+shuffle-seed = 0
+check-backups = false
+backup-suffixes = []
+dump-requests = 0
+probe-methods = false
+detect-waf = false
+exclude-blocked = false
+start-paths = []
+dedup-by = ""
+skip-404-after = 0
+session-file = ""
+param-fuzz = false
+scan-id = ""
 
 [dictionary]
 default-extensions = []
 force-extensions = false
 overwrite-extensions = false
+remove-extensions = false
 lowercase = false
 uppercase = false
 capitalization = false
@@ -552,6 +988,14 @@ exclude-extensions = []
 prefixes = []
 suffixes = []
 wordlists = []
+wordlist-extensions = []
+priority-file = ""
+low-memory = false
+ignore-wordlist-errors = false
+case-insensitive-dedup = false
+append-slash = false
+tech = []
+auto-tech = false
 type = file
 path = ""
 url = ""
@@ -566,29 +1010,58 @@ db-column = word
 [request]
 http-method = GET
 follow-redirects = false
+stay-in-scope = false
 headers-file = ""
 user-agent = ""
 cookie = ""
 data = ""
 data-file = ""
+multipart = false
 headers = []
 auth = ""
 auth-type = ""
+auth-map = ""
+auth-map-file = ""
+preflight-url = ""
+extract-token-regex = ""
+token-header = ""
+cache-bust = false
+cache-bust-param = "cb"
 
 [connection]
 timeout = 7.5
 delay = 0
 max-rate = 0
+max-bandwidth = ""
 max-retries = 3
+retry-on-status = ""
 domain-check-timeout = 60
 domain-check-retries = 3
+alive-status = ""
 headless-timeout = 30
 headless-concurrency = 5
+chrome-path = ""
+chrome-flags = []
+headless-wait-for = ""
 scheme = ""
 proxy = ""
+proxy-rules = ""
+proxy-auth = ""
+tor = false
+tor-address = "127.0.0.1:9050"
 proxy-file = ""
 replay-proxy = ""
+replay-headers = []
 proxies = []
+host-timeout = ""
+warmup = 0
+ip = ""
+interface = ""
+disable-keepalive = false
+max-consecutive-errors = 0
+cert-file = ""
+key-file = ""
+http10 = false
 
 [advanced]
 crawl = false
@@ -602,6 +1075,7 @@ real-time-status = false
 headless = false
 show-all-status = false
 recursive-scan = false
+color-map = ""
 
 [output]
 report-format = plain
@@ -609,4 +1083,12 @@ autosave-report = false
 autosave-report-folder = ""
 log-file = ""
 log-file-size = 0
+screenshots = ""
+csv-bom = false
+webhook = ""
+webhook-headers = []
+output-urls = ""
+flush-interval = 1
+ci-summary = false
+max-title-length = 0
 `
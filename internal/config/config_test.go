@@ -1,7 +1,11 @@
 package config
 
 import (
+	"bytes"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -86,7 +90,7 @@ func TestInit(t *testing.T) {
 	defer os.Setenv("DIRSEARCH_CONFIG", originalConfig)
 
 	// 测试默认配置初始化
-	err := Init()
+	err := Init("")
 	if err != nil {
 		t.Errorf("Failed to initialize config: %v", err)
 	}
@@ -109,7 +113,7 @@ func TestInit(t *testing.T) {
 func TestGetConfig(t *testing.T) {
 	// 确保配置已初始化
 	if GlobalConfig == nil {
-		Init()
+		Init("")
 	}
 
 	config := GetConfig()
@@ -117,3 +121,319 @@ func TestGetConfig(t *testing.T) {
 		t.Error("GetConfig should not return nil")
 	}
 }
+
+func TestParseHostTimeouts(t *testing.T) {
+	timeouts, err := ParseHostTimeouts("slow.example.com=30, Fast.example.com=3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if timeouts["slow.example.com"] != 30 || timeouts["fast.example.com"] != 3 {
+		t.Errorf("Unexpected timeouts: %v", timeouts)
+	}
+
+	for _, invalid := range []string{"slow.example.com", "=30", "slow.example.com=abc", "slow.example.com=-1"} {
+		if _, err := ParseHostTimeouts(invalid); err == nil {
+			t.Errorf("Expected error for %q but got none", invalid)
+		}
+	}
+}
+
+func TestParseAuthMap(t *testing.T) {
+	credentials, err := ParseAuthMap("API.example.com=token1, admin.example.com:8443 = user:pass")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if credentials["api.example.com"] != "token1" || credentials["admin.example.com:8443"] != "user:pass" {
+		t.Errorf("Unexpected credentials: %v", credentials)
+	}
+
+	for _, invalid := range []string{"api.example.com", "=s3cret", "api.example.com="} {
+		_, err := ParseAuthMap(invalid)
+		if err == nil {
+			t.Errorf("Expected error for %q but got none", invalid)
+		} else if strings.Contains(err.Error(), "s3cret") {
+			t.Errorf("Error for %q leaks the credential: %v", invalid, err)
+		}
+	}
+}
+
+func TestParseProxyRules(t *testing.T) {
+	rules, err := ParseProxyRules("*.Internal=direct; *=socks5://127.0.0.1:1080")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Got %d rules, want 2", len(rules))
+	}
+	if rules[0].Pattern != "*.internal" || rules[0].Proxy != nil {
+		t.Errorf("Unexpected first rule: %+v", rules[0])
+	}
+	if rules[1].Proxy == nil || rules[1].Proxy.String() != "socks5://127.0.0.1:1080" {
+		t.Errorf("Unexpected second rule: %+v", rules[1])
+	}
+	if !rules[0].Matches("db.corp.INTERNAL") || rules[0].Matches("internal.example.com") {
+		t.Errorf("Unexpected matching for %q", rules[0].Pattern)
+	}
+
+	for _, invalid := range []string{"", "*.internal", "=direct", "*.internal=", "[=direct", "*=ftp://proxy:21", "*=socks5://"} {
+		if _, err := ParseProxyRules(invalid); err == nil {
+			t.Errorf("Expected error for %q but got none", invalid)
+		}
+	}
+}
+
+func TestParseLengthSimilar(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected SizeRange
+	}{
+		{spec: "1234±20", expected: SizeRange{Min: 1214, Max: 1254}},
+		{spec: "1234 +- 20", expected: SizeRange{Min: 1214, Max: 1254}},
+		{spec: "1000±5%", expected: SizeRange{Min: 950, Max: 1050}},
+		{spec: "10±50", expected: SizeRange{Min: 0, Max: 60}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLengthSimilar(tt.spec)
+		if err != nil {
+			t.Errorf("ParseLengthSimilar(%q) unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseLengthSimilar(%q) = %+v, want %+v", tt.spec, got, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"1234", "abc±20", "1234±", "1234±-5", "-1±5"} {
+		if _, err := ParseLengthSimilar(invalid); err == nil {
+			t.Errorf("Expected error for %q but got none", invalid)
+		}
+	}
+}
+
+func TestParseKnown404(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Known404
+	}{
+		{spec: "size=1234", expected: Known404{Size: 1234}},
+		{spec: "SIZE = 0", expected: Known404{Size: 0}},
+		{spec: "text=Custom Not Found", expected: Known404{Size: -1, Text: "Custom Not Found"}},
+		{spec: "text=a=b", expected: Known404{Size: -1, Text: "a=b"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseKnown404(tt.spec)
+		if err != nil {
+			t.Errorf("ParseKnown404(%q) unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseKnown404(%q) = %+v, want %+v", tt.spec, got, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"1234", "size=abc", "size=-1", "text=", "status=404"} {
+		if _, err := ParseKnown404(invalid); err == nil {
+			t.Errorf("Expected error for %q but got none", invalid)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected int64
+	}{
+		{spec: "512", expected: 512},
+		{spec: "100b", expected: 100},
+		{spec: "64kb", expected: 64 << 10},
+		{spec: "1MB", expected: 1 << 20},
+		{spec: "1.5m", expected: 3 << 19},
+		{spec: "2g", expected: 2 << 30},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.spec)
+		if err != nil {
+			t.Errorf("ParseByteSize(%q) unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseByteSize(%q) = %d, want %d", tt.spec, got, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "mb", "fast", "-1kb", "0"} {
+		if _, err := ParseByteSize(invalid); err == nil {
+			t.Errorf("Expected error for %q but got none", invalid)
+		}
+	}
+}
+
+// withWorkDir 在临时工作目录中运行测试，并隔离配置文件查找路径
+func withWorkDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	original, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(original) })
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", "")
+	t.Setenv("DIRSEARCH_CONFIG", "")
+	return dir
+}
+
+// captureLog 捕获测试期间的日志输出
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+const malformedConfigINI = "[general\nthreads = 5\n"
+
+func TestInitMalformedExplicitConfig(t *testing.T) {
+	dir := withWorkDir(t)
+	configFile := filepath.Join(dir, "custom.ini")
+	if err := os.WriteFile(configFile, []byte(malformedConfigINI), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("DIRSEARCH_CONFIG", configFile)
+
+	err := Init("")
+	if err == nil {
+		t.Fatal("Expected error for malformed config set via DIRSEARCH_CONFIG")
+	}
+	if !strings.Contains(err.Error(), configFile) {
+		t.Errorf("Error should mention the config file, got: %v", err)
+	}
+
+	if err := Init(configFile); err == nil || !strings.Contains(err.Error(), configFile) {
+		t.Errorf("Init(%q) error = %v, want error mentioning it", configFile, err)
+	}
+	if err := Init(filepath.Join(dir, "missing.ini")); err == nil {
+		t.Error("Expected error for missing config file")
+	}
+}
+
+func TestInitExplicitConfigSkipsDiscovery(t *testing.T) {
+	dir := withWorkDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "config.ini"), []byte(malformedConfigINI), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	configFile := filepath.Join(dir, "custom.ini")
+	if err := os.WriteFile(configFile, []byte("[general]\nthreads = 9\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	logs := captureLog(t)
+
+	if err := Init(configFile); err != nil {
+		t.Fatalf("Init(%q) failed: %v", configFile, err)
+	}
+	if ConfigFile != configFile {
+		t.Errorf("ConfigFile = %q, want %q", ConfigFile, configFile)
+	}
+	if GlobalConfig.General.Threads != 9 {
+		t.Errorf("Threads = %d, want 9 from the explicit config file", GlobalConfig.General.Threads)
+	}
+	if strings.Contains(logs.String(), "Ignoring malformed config file") {
+		t.Errorf("Discovered config.ini should not be read when a config file is given, got logs:\n%s", logs.String())
+	}
+	if n := strings.Count(logs.String(), "Loaded config file"); n != 1 {
+		t.Errorf("Expected exactly one loaded config file log, got %d:\n%s", n, logs.String())
+	}
+}
+
+func TestInitMalformedDiscoveredConfig(t *testing.T) {
+	dir := withWorkDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "config.ini"), []byte(malformedConfigINI), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	logs := captureLog(t)
+
+	if err := Init(""); err != nil {
+		t.Fatalf("Init() should fall back to defaults for a discovered malformed config, got: %v", err)
+	}
+	if ConfigFile != "" {
+		t.Errorf("ConfigFile = %q, want empty when falling back to defaults", ConfigFile)
+	}
+	if !strings.Contains(logs.String(), "Ignoring malformed config file") || !strings.Contains(logs.String(), "config.ini") {
+		t.Errorf("Expected warning about the malformed config file, got logs:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "using built-in default configuration") {
+		t.Errorf("Expected log about using the built-in defaults, got logs:\n%s", logs.String())
+	}
+}
+
+func TestInitReportsLoadedConfig(t *testing.T) {
+	dir := withWorkDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "config.ini"), []byte("[general]\nthreads = 7\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	logs := captureLog(t)
+
+	if err := Init(""); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if ConfigFile != "config.ini" {
+		t.Errorf("ConfigFile = %q, want %q", ConfigFile, "config.ini")
+	}
+	if !strings.Contains(logs.String(), "Loaded config file: config.ini") {
+		t.Errorf("Expected loaded config file to be logged, got logs:\n%s", logs.String())
+	}
+	if GlobalConfig.General.Threads != 7 {
+		t.Errorf("Threads = %d, want 7 from config file", GlobalConfig.General.Threads)
+	}
+}
+
+func TestInitDefaultsIgnoresEnvAndConfigFile(t *testing.T) {
+	dir := withWorkDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "config.ini"), []byte("[general]\nthreads = 7\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("DIRSEARCH_TIMEOUT", "42")
+
+	// 正常初始化会使用配置文件和环境变量
+	if err := Init(""); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if GlobalConfig.General.Threads != 7 || GlobalConfig.Connection.Timeout != 42 {
+		t.Fatalf("Init() threads/timeout = %d/%v, want 7/42", GlobalConfig.General.Threads, GlobalConfig.Connection.Timeout)
+	}
+
+	if err := InitDefaults(); err != nil {
+		t.Fatalf("InitDefaults() failed: %v", err)
+	}
+	if ConfigFile != "" {
+		t.Errorf("ConfigFile = %q, want empty under --no-config", ConfigFile)
+	}
+	if GlobalConfig.General.Threads != 25 {
+		t.Errorf("Threads = %d, want built-in default 25", GlobalConfig.General.Threads)
+	}
+	if GlobalConfig.Connection.Timeout != 7.5 {
+		t.Errorf("Timeout = %v, want built-in default 7.5", GlobalConfig.Connection.Timeout)
+	}
+}
+
+func TestInitDefaultsPassesValidation(t *testing.T) {
+	withWorkDir(t)
+
+	if err := InitDefaults(); err != nil {
+		t.Fatalf("InitDefaults() failed: %v", err)
+	}
+	if err := Validate(GlobalConfig); err != nil {
+		t.Errorf("Validate() on built-in defaults = %v, want nil", err)
+	}
+	// 内置配置中的"key = []"不能被读成包含"[]"的列表
+	if len(GlobalConfig.Connection.Proxies) != 0 {
+		t.Errorf("Proxies = %q, want empty", GlobalConfig.Connection.Proxies)
+	}
+	if len(GlobalConfig.Output.WebhookHeaders) != 0 {
+		t.Errorf("WebhookHeaders = %q, want empty", GlobalConfig.Output.WebhookHeaders)
+	}
+}
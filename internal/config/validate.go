@@ -0,0 +1,227 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Validate 检查互斥和存在依赖关系的配置项，返回所有问题的汇总错误
+func Validate(cfg *Config) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
+
+	var errs []error
+
+	// 大小写转换互斥
+	var caseFlags []string
+	if cfg.Dictionary.Uppercase {
+		caseFlags = append(caseFlags, "--uppercase")
+	}
+	if cfg.Dictionary.Lowercase {
+		caseFlags = append(caseFlags, "--lowercase")
+	}
+	if cfg.Dictionary.Capitalization {
+		caseFlags = append(caseFlags, "--capital")
+	}
+	if len(caseFlags) > 1 {
+		errs = append(errs, fmt.Errorf("%s are mutually exclusive", strings.Join(caseFlags, " and ")))
+	}
+
+	// 扩展名处理方式互斥
+	if cfg.Dictionary.ForceExtensions && cfg.Dictionary.OverwriteExtensions {
+		errs = append(errs, errors.New("--force-extensions and --overwrite-extensions are mutually exclusive"))
+	}
+
+	// wordlist源依赖
+	source := cfg.Dictionary.Source
+	switch source.Type {
+	case "", "file":
+	case "url":
+		if source.URL == "" {
+			errs = append(errs, errors.New("--wordlist-source url requires --wordlist-url"))
+		}
+	case "database":
+		var missing []string
+		if source.DBHost == "" {
+			missing = append(missing, "--wordlist-db-host")
+		}
+		if source.DBUser == "" {
+			missing = append(missing, "--wordlist-db-user")
+		}
+		if source.DBName == "" {
+			missing = append(missing, "--wordlist-db-name")
+		}
+		if len(missing) > 0 {
+			errs = append(errs, fmt.Errorf("--wordlist-source database requires %s", strings.Join(missing, ", ")))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown wordlist source %q (expected file, url or database)", source.Type))
+	}
+
+	// 重放代理格式
+	if cfg.Connection.ReplayProxy != "" {
+		if err := validateProxyURL(cfg.Connection.ReplayProxy); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --replay-proxy: %w", err))
+		}
+	}
+
+	// webhook地址
+	if cfg.Output.Webhook != "" {
+		if parsed, err := url.Parse(cfg.Output.Webhook); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid --webhook %q, expected an http(s) URL", cfg.Output.Webhook))
+		}
+	} else if len(cfg.Output.WebhookHeaders) > 0 {
+		errs = append(errs, errors.New("--webhook-header requires --webhook"))
+	}
+
+	// 结果去重方式
+	switch cfg.General.DedupBy {
+	case "", "url", "path", "hash", "status-size":
+	default:
+		errs = append(errs, fmt.Errorf("invalid --dedup-by %q (expected url, path, hash or status-size)", cfg.General.DedupBy))
+	}
+
+	// 按主机配置的认证凭据
+	if cfg.Request.AuthMap != "" {
+		if _, err := ParseAuthMap(cfg.Request.AuthMap); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --auth-map: %w", err))
+		}
+	}
+
+	// 发起请求的本地地址
+	if cfg.Connection.IP != "" && net.ParseIP(cfg.Connection.IP) == nil {
+		errs = append(errs, fmt.Errorf("invalid --ip %q, expected an IP address", cfg.Connection.IP))
+	}
+
+	// 按主机路由的代理规则
+	if cfg.Connection.ProxyRules != "" {
+		if _, err := ParseProxyRules(cfg.Connection.ProxyRules); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --proxy-rules: %w", err))
+		}
+	}
+
+	// 带宽限制
+	if cfg.Connection.MaxBandwidth != "" {
+		if _, err := ParseByteSize(cfg.Connection.MaxBandwidth); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --max-bandwidth: %w", err))
+		}
+	}
+
+	// 域名存活状态码
+	if spec := strings.TrimSpace(cfg.Connection.AliveStatus); spec != "" {
+		if err := validateStatusCodes(spec); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --alive-status: %w", err))
+		}
+	}
+
+	// 需要重试的状态码
+	if spec := strings.TrimSpace(cfg.Connection.RetryOnStatus); spec != "" {
+		if err := validateStatusCodes(spec); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --retry-on-status: %w", err))
+		}
+	}
+
+	// 范围过滤
+	general := cfg.General
+	if general.MinResponseSize > 0 && general.MaxResponseSize > 0 && general.MinResponseSize > general.MaxResponseSize {
+		errs = append(errs, fmt.Errorf("--min-response-size (%d) is greater than --max-response-size (%d)", general.MinResponseSize, general.MaxResponseSize))
+	}
+	if general.MinResponseTime > 0 && general.MaxResponseTime > 0 && general.MinResponseTime > general.MaxResponseTime {
+		errs = append(errs, fmt.Errorf("--min-response-time (%d) is greater than --max-response-time (%d)", general.MinResponseTime, general.MaxResponseTime))
+	}
+
+	for _, spec := range general.ExcludeLengthSimilar {
+		if _, err := ParseLengthSimilar(spec); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --exclude-length-similar: %w", err))
+		}
+	}
+	for _, spec := range general.Known404 {
+		if _, err := ParseKnown404(spec); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --known-404: %w", err))
+		}
+	}
+
+	// 请求体与预请求依赖
+	if cfg.Request.Multipart && cfg.Request.Data == "" && cfg.Request.DataFile == "" {
+		errs = append(errs, errors.New("--multipart requires --data or --data-file"))
+	}
+	if cfg.Request.ExtractTokenRegex != "" && cfg.Request.PreflightURL == "" {
+		errs = append(errs, errors.New("--extract-token-regex requires --preflight-url"))
+	}
+
+	// 无头模式依赖
+	if !cfg.View.Headless {
+		if cfg.Output.ScreenshotDir != "" {
+			errs = append(errs, errors.New("--screenshots requires --headless"))
+		}
+		if cfg.Connection.HeadlessWaitFor != "" {
+			errs = append(errs, errors.New("--headless-wait-for requires --headless"))
+		}
+	} else if cfg.Connection.Tor {
+		// 浏览器不经过Tor发送请求，会暴露真实IP
+		errs = append(errs, errors.New("--tor cannot be used together with --headless"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateStatusCodes 严格检查逗号分隔的状态码或范围（如 429,500-599）。ParseStatusCodes会忽略无法解析的项，
+// 并把5O3这样的输入解析为5，因此单独逐项检查
+func validateStatusCodes(spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		start, end, isRange := strings.Cut(part, "-")
+		if !isRange {
+			end = start
+		}
+		startCode, startErr := strconv.Atoi(strings.TrimSpace(start))
+		endCode, endErr := strconv.Atoi(strings.TrimSpace(end))
+		if startErr != nil || endErr != nil || startCode < 100 || endCode > 599 || startCode > endCode {
+			return fmt.Errorf("invalid status code %q, expected codes or ranges between 100 and 599 (e.g. 429,500-599)", part)
+		}
+	}
+	return nil
+}
+
+// validateProxyURL 检查代理URL的协议和主机，错误信息中不包含代理密码
+func validateProxyURL(proxy string) error {
+	parsed, err := url.Parse(proxy)
+	if err != nil {
+		return URLParseError(err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("%s must use http, https or socks5 scheme", parsed.Redacted())
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%s has no host", parsed.Redacted())
+	}
+	return nil
+}
+
+// RedactProxyURL 隐藏代理URL中的密码，用于日志和输出
+func RedactProxyURL(proxy string) string {
+	parsed, err := url.Parse(proxy)
+	if err != nil {
+		return "<invalid>"
+	}
+	return parsed.Redacted()
+}
+
+// URLParseError 返回url.Parse错误的原因，去掉其中的完整URL，避免在错误信息中泄露凭据
+func URLParseError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
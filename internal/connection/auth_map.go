@@ -0,0 +1,68 @@
+package connection
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"dirsearch-go/internal/config"
+)
+
+// loadAuthMap 合并--auth-map-file和--auth-map中按主机配置的认证凭据，同一主机以--auth-map为准
+func loadAuthMap(cfg *config.Config) (map[string]string, error) {
+	credentials := make(map[string]string)
+	if cfg.Request.AuthMapFile != "" {
+		fromFile, err := config.ReadAuthMapFile(cfg.Request.AuthMapFile)
+		if err != nil {
+			return nil, err
+		}
+		for host, credential := range fromFile {
+			credentials[host] = credential
+		}
+	}
+
+	if cfg.Request.AuthMap != "" {
+		fromFlag, err := config.ParseAuthMap(cfg.Request.AuthMap)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth map: %w", err)
+		}
+		for host, credential := range fromFlag {
+			credentials[host] = credential
+		}
+	}
+	return credentials, nil
+}
+
+// authForHost 查找请求主机的认证凭据，先按host:port匹配，再按主机名匹配
+func (r *Requester) authForHost(target *url.URL) (string, bool) {
+	if len(r.authMap) == 0 {
+		return "", false
+	}
+	if credential, ok := r.authMap[strings.ToLower(target.Host)]; ok {
+		return credential, true
+	}
+	credential, ok := r.authMap[strings.ToLower(target.Hostname())]
+	return credential, ok
+}
+
+// setAuth 按--auth-type设置全局--auth的认证信息，其他认证类型不设置
+func setAuth(req *http.Request, authType, credential string) {
+	switch authType {
+	case "basic":
+		req.SetBasicAuth("", credential)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+credential)
+	}
+}
+
+// setHostAuth 设置--auth-map中按主机配置的凭据，未指定--auth-type时使用bearer；basic认证的凭据为user:password
+func setHostAuth(req *http.Request, authType, credential string) {
+	switch strings.ToLower(authType) {
+	case "basic":
+		user, password, _ := strings.Cut(credential, ":")
+		req.SetBasicAuth(user, password)
+	case "", "bearer":
+		req.Header.Set("Authorization", "Bearer "+credential)
+	}
+}
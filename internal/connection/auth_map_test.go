@@ -0,0 +1,128 @@
+package connection
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAuthMap(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string]string)
+	newServer := func() *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			got[r.Host] = r.Header.Get("Authorization")
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	first, second, third := newServer(), newServer(), newServer()
+	hostOf := func(server *httptest.Server) string {
+		parsed, _ := url.Parse(server.URL)
+		return parsed.Host
+	}
+
+	// 第三个服务器通过localhost访问，按主机名（不含端口）匹配文件中的凭据
+	thirdURL := strings.Replace(third.URL, "127.0.0.1", "localhost", 1)
+	authFile := filepath.Join(t.TempDir(), "auth.txt")
+	content := "# per-host tokens\nlocalhost=file-token\n" + hostOf(first) + "=overridden\n"
+	if err := os.WriteFile(authFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write auth map file: %v", err)
+	}
+
+	cfg := newTestConfig()
+	cfg.Request.AuthMap = hostOf(first) + "=token1," + hostOf(second) + "=token2"
+	cfg.Request.AuthMapFile = authFile
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{target: first.URL, want: "Bearer token1"},
+		{target: second.URL, want: "Bearer token2"},
+		{target: thirdURL, want: "Bearer file-token"},
+	}
+	for _, tt := range tests {
+		if _, err := requester.Request(tt.target + "/admin"); err != nil {
+			t.Fatalf("Request to %s failed: %v", tt.target, err)
+		}
+		parsed, _ := url.Parse(tt.target)
+		mu.Lock()
+		if got[parsed.Host] != tt.want {
+			t.Errorf("Authorization for %s = %q, want %q", parsed.Host, got[parsed.Host], tt.want)
+		}
+		mu.Unlock()
+	}
+}
+
+func TestAuthCredentialTypes(t *testing.T) {
+	var mu sync.Mutex
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = r.Header.Clone()
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	parsed, _ := url.Parse(server.URL)
+
+	tests := []struct {
+		name         string
+		auth         string
+		authMap      string
+		authType     string
+		wantUser     string
+		wantPassword string
+		wantHeader   string
+	}{
+		// 全局--auth保持原有行为：basic凭据整体作为密码，未指定类型时不发送
+		{name: "全局basic", auth: "admin:pa:ss", authType: "basic", wantPassword: "admin:pa:ss"},
+		{name: "全局未指定类型", auth: "token"},
+		{name: "全局bearer", auth: "token", authType: "bearer", wantHeader: "Bearer token"},
+		// --auth-map的凭据默认bearer，basic凭据为user:password
+		{name: "按主机basic", authMap: parsed.Host + "=admin:pa:ss", authType: "basic", wantUser: "admin", wantPassword: "pa:ss"},
+		{name: "按主机默认bearer", authMap: parsed.Host + "=token", wantHeader: "Bearer token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Request.Auth = tt.auth
+			cfg.Request.AuthMap = tt.authMap
+			cfg.Request.AuthType = tt.authType
+			requester, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+			if _, err := requester.Request(server.URL + "/admin"); err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+
+			mu.Lock()
+			received := &http.Request{Header: got}
+			mu.Unlock()
+			if tt.wantUser != "" || tt.wantPassword != "" {
+				user, password, ok := received.BasicAuth()
+				if !ok || user != tt.wantUser || password != tt.wantPassword {
+					t.Errorf("BasicAuth() = %q, %q, %v, want %q, %q", user, password, ok, tt.wantUser, tt.wantPassword)
+				}
+				return
+			}
+			if auth := received.Header.Get("Authorization"); auth != tt.wantHeader {
+				t.Errorf("Authorization = %q, want %q", auth, tt.wantHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package connection
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// BandwidthLimiter 按字节计的令牌桶，所有工作协程共享，限制读取响应体的总带宽
+type BandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒字节数
+	burst  float64 // 空闲时最多累积的字节数
+	tokens float64
+	last   time.Time
+}
+
+// NewBandwidthLimiter 创建带宽限制器，令牌桶初始为空，最多累积1/4秒的带宽
+func NewBandwidthLimiter(bytesPerSecond int64) *BandwidthLimiter {
+	rate := float64(bytesPerSecond)
+	return &BandwidthLimiter{
+		rate:  rate,
+		burst: max(rate/4, 1),
+		last:  time.Now(),
+	}
+}
+
+// WaitN 预留n字节并等待到可用为止，令牌不足时后续调用依次排队
+func (l *BandwidthLimiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reader 返回受带宽限制的Reader
+func (l *BandwidthLimiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	return &limitedReader{ctx: ctx, r: r, limiter: l}
+}
+
+// limitedReader 每次读取后按读取的字节数等待令牌
+type limitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *BandwidthLimiter
+}
+
+// Read 读取数据，单次读取不超过令牌桶容量，避免一次等待过久
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if chunk := int(lr.limiter.burst); len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := lr.r.Read(p)
+	if n > 0 {
+		if waitErr := lr.limiter.WaitN(lr.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
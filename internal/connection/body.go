@@ -0,0 +1,119 @@
+package connection
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"dirsearch-go/internal/config"
+)
+
+// loadRequestBody 构建请求体：同时指定时--data优先，否则按原始字节读取--data-file（支持二进制内容）
+func loadRequestBody(reqCfg config.RequestConfig) ([]byte, string, error) {
+	if reqCfg.Data != "" || reqCfg.DataFile == "" {
+		return buildRequestBody(reqCfg.Data, reqCfg.Multipart)
+	}
+
+	content, err := os.ReadFile(reqCfg.DataFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read data file: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, "", nil
+	}
+
+	// multipart模式下文件内容是key=value字段描述
+	if reqCfg.Multipart {
+		return buildMultipartBody(strings.TrimSpace(string(content)))
+	}
+	if !utf8.Valid(content) {
+		return content, "application/octet-stream", nil
+	}
+	return content, detectContentType(string(content)), nil
+}
+
+// buildRequestBody 根据请求数据构建请求体并推断Content-Type
+func buildRequestBody(data string, isMultipart bool) ([]byte, string, error) {
+	if data == "" {
+		return nil, "", nil
+	}
+
+	if isMultipart {
+		return buildMultipartBody(data)
+	}
+
+	return []byte(data), detectContentType(data), nil
+}
+
+// detectContentType 根据数据内容推断Content-Type
+func detectContentType(data string) string {
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return "application/json"
+	}
+	if strings.HasPrefix(trimmed, "<") {
+		return "application/xml"
+	}
+	if strings.Contains(trimmed, "=") {
+		if _, err := url.ParseQuery(trimmed); err == nil {
+			return "application/x-www-form-urlencoded"
+		}
+	}
+	return "text/plain"
+}
+
+// buildMultipartBody 将key=value对构建为multipart/form-data请求体，值以@开头时作为文件上传
+func buildMultipartBody(data string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, pair := range strings.Split(data, "&") {
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		key := parts[0]
+		value := ""
+		if len(parts) == 2 {
+			value = parts[1]
+		}
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+
+		if strings.HasPrefix(value, "@") {
+			path := strings.TrimPrefix(value, "@")
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read multipart file %s: %w", path, err)
+			}
+			part, err := writer.CreateFormFile(key, filepath.Base(path))
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to create multipart file field: %w", err)
+			}
+			if _, err := part.Write(content); err != nil {
+				return nil, "", fmt.Errorf("failed to write multipart file field: %w", err)
+			}
+			continue
+		}
+
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, "", fmt.Errorf("failed to write multipart field: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
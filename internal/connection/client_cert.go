@@ -0,0 +1,24 @@
+package connection
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// loadClientCertificate 加载双向TLS使用的客户端证书，证书和私钥文件必须同时指定
+func loadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	switch {
+	case certFile == "" && keyFile == "":
+		return tls.Certificate{}, fmt.Errorf("client certificate not configured")
+	case certFile == "":
+		return tls.Certificate{}, fmt.Errorf("--key-file requires --cert-file")
+	case keyFile == "":
+		return tls.Certificate{}, fmt.Errorf("--cert-file requires --key-file")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate %s / %s: %w", certFile, keyFile, err)
+	}
+	return cert, nil
+}
@@ -0,0 +1,172 @@
+package connection
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCertificate 生成自签名客户端证书，返回证书和私钥文件路径
+func writeClientCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dirsearch-go client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestClientCertificate(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	cfg := newTestConfig()
+	cfg.Connection.CertFile = certFile
+	cfg.Connection.KeyFile = keyFile
+	r, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	transport, ok := r.client.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		t.Fatalf("Expected a transport with a TLS client config, got %#v", r.client.Transport)
+	}
+	if got := len(transport.TLSClientConfig.Certificates); got != 1 {
+		t.Errorf("Got %d client certificates, want 1", got)
+	}
+}
+
+func TestDomainCheckerClientCertificate(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	// 服务端要求客户端证书，没有证书时握手失败
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.CertFile = certFile
+	cfg.Connection.KeyFile = keyFile
+	cfg.Connection.DomainCheckTimeout = 5
+	cfg.Connection.DomainCheckRetries = 1
+	r, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	r.client.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+
+	alive, dead := NewDomainChecker(cfg, r.Transport()).CheckMultipleDomains([]string{server.URL})
+	if len(alive) != 1 || len(dead) != 0 {
+		t.Errorf("alive %v, dead %v, want the mTLS host alive", alive, dead)
+	}
+}
+
+func TestReplayClientCertificate(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	cfg := newTestConfig()
+	cfg.Connection.CertFile = certFile
+	cfg.Connection.KeyFile = keyFile
+	cfg.Connection.ReplayProxy = "http://127.0.0.1:8080"
+	r, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	transport := r.replayClient.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || len(transport.TLSClientConfig.Certificates) != 1 {
+		t.Errorf("Expected replay requests to send the client certificate, got %#v", transport.TLSClientConfig)
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("Expected replay requests to keep skipping verification of the intercepting proxy's certificate")
+	}
+}
+
+func TestReplayClientCertificateHTTP10(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	cfg := newTestConfig()
+	cfg.Connection.CertFile = certFile
+	cfg.Connection.KeyFile = keyFile
+	cfg.Connection.HTTP10 = true
+	cfg.Connection.ReplayProxy = "http://127.0.0.1:8080"
+	r, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	if _, ok := r.client.Transport.(*http10Transport); !ok {
+		t.Fatalf("Expected an HTTP/1.0 transport, got %#v", r.client.Transport)
+	}
+	transport := r.replayClient.Transport.(*http.Transport)
+	if transport.TLSClientConfig == nil || len(transport.TLSClientConfig.Certificates) != 1 {
+		t.Errorf("Expected replay requests to send the client certificate with --http10, got %#v", transport.TLSClientConfig)
+	}
+}
+
+func TestClientCertificateInvalid(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+	}{
+		{name: "只指定证书", certFile: certFile},
+		{name: "只指定私钥", keyFile: keyFile},
+		{name: "证书文件不存在", certFile: certFile + ".missing", keyFile: keyFile},
+		{name: "私钥不是PEM", certFile: certFile, keyFile: certFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Connection.CertFile = tt.certFile
+			cfg.Connection.KeyFile = tt.keyFile
+			if _, err := NewRequester(cfg); err == nil {
+				t.Error("Expected NewRequester to fail")
+			}
+		})
+	}
+}
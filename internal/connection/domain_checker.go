@@ -12,19 +12,29 @@ import (
 
 // DomainChecker 域名检测器
 type DomainChecker struct {
-	config *config.Config
-	client *http.Client
+	config      *config.Config
+	client      *http.Client
+	aliveStatus map[int]bool // 视为存活的状态码，为空时收到任何响应即存活
 }
 
-// NewDomainChecker 创建新的域名检测器
-func NewDomainChecker(cfg *config.Config) *DomainChecker {
+// NewDomainChecker 创建新的域名检测器。transport应为扫描请求使用的传输层（Requester.Transport），
+// 使存活检测与扫描经过相同的代理（包括Tor）、源地址和客户端证书，为nil时使用默认传输层
+func NewDomainChecker(cfg *config.Config, transport http.RoundTripper) *DomainChecker {
 	client := &http.Client{
-		Timeout: time.Duration(cfg.Connection.DomainCheckTimeout * float64(time.Second)),
+		Timeout:   time.Duration(cfg.Connection.DomainCheckTimeout * float64(time.Second)),
+		Transport: transport,
+	}
+
+	aliveStatus := make(map[int]bool)
+	codes, _ := config.ParseStatusCodes(cfg.Connection.AliveStatus)
+	for _, code := range codes {
+		aliveStatus[code] = true
 	}
 
 	return &DomainChecker{
-		config: cfg,
-		client: client,
+		config:      cfg,
+		client:      client,
+		aliveStatus: aliveStatus,
 	}
 }
 
@@ -89,8 +99,15 @@ func (dc *DomainChecker) isDomainAlive(url string) bool {
 	}
 	defer resp.Body.Close()
 
-	// 检查状态码，2xx和3xx都认为是存活的
-	return resp.StatusCode >= 200 && resp.StatusCode < 400
+	return dc.isAliveStatus(resp.StatusCode)
+}
+
+// isAliveStatus 判断状态码是否表示存活，未配置时收到任何HTTP响应即认为主机存活（如根路径401/403的受保护应用）
+func (dc *DomainChecker) isAliveStatus(statusCode int) bool {
+	if len(dc.aliveStatus) == 0 {
+		return true
+	}
+	return dc.aliveStatus[statusCode]
 }
 
 // CheckMultipleDomains 批量检测域名
@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestDomainCheckerAliveStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		aliveStatus string
+		expected    bool
+	}{
+		{name: "默认收到任何响应即存活", aliveStatus: "", expected: true},
+		{name: "只接受2xx和3xx", aliveStatus: "200-399", expected: false},
+		{name: "显式接受403", aliveStatus: "200-399,401,403", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Connection: config.ConnectionConfig{
+					DomainCheckTimeout: 5,
+					DomainCheckRetries: 1,
+					AliveStatus:        tt.aliveStatus,
+				},
+			}
+			alive, dead := NewDomainChecker(cfg, nil).CheckMultipleDomains([]string{server.URL})
+			if got := len(alive) == 1 && len(dead) == 0; got != tt.expected {
+				t.Errorf("403-at-root host alive = %v, want %v (alive %v, dead %v)", got, tt.expected, alive, dead)
+			}
+		})
+	}
+}
+
+func TestDomainCheckerUsesRequesterProxy(t *testing.T) {
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.Proxy = proxy.URL
+	cfg.Connection.DomainCheckTimeout = 5
+	cfg.Connection.DomainCheckRetries = 1
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	// .onion地址无法直接解析，只有经过代理才能检测为存活
+	target := "http://exampleonionaddress.onion/"
+	alive, dead := NewDomainChecker(cfg, requester.Transport()).CheckMultipleDomains([]string{target})
+	if len(alive) != 1 || len(dead) != 0 {
+		t.Fatalf("alive %v, dead %v, want %s alive through the proxy", alive, dead, target)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(proxied) != 1 || proxied[0] != "HEAD "+target {
+		t.Errorf("Proxy saw %v, want a single HEAD %s", proxied, target)
+	}
+}
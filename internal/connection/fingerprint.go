@@ -0,0 +1,95 @@
+package connection
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HostFingerprint 主机指纹信息，用于识别目标前面的CDN/WAF
+type HostFingerprint struct {
+	Server     string
+	PoweredBy  string
+	CDN        []string
+	TLSSubject string
+	TLSIssuer  string
+}
+
+// cdnSignature CDN/WAF识别规则
+type cdnSignature struct {
+	name    string
+	headers []string // 存在任一响应头即命中
+	server  string   // Server头包含该值即命中（小写）
+}
+
+// cdnSignatures 已知的CDN/WAF特征
+var cdnSignatures = []cdnSignature{
+	{name: "Cloudflare", headers: []string{"CF-Ray", "CF-Cache-Status"}, server: "cloudflare"},
+	{name: "CloudFront", headers: []string{"X-Amz-Cf-Id", "X-Amz-Cf-Pop"}, server: "cloudfront"},
+	{name: "Akamai", headers: []string{"X-Akamai-Transformed", "Akamai-Grn"}, server: "akamaighost"},
+	{name: "Fastly", headers: []string{"Fastly-Debug-Digest", "X-Fastly-Request-ID"}},
+	{name: "Sucuri", headers: []string{"X-Sucuri-ID", "X-Sucuri-Cache"}, server: "sucuri"},
+	{name: "Incapsula", headers: []string{"X-Iinfo"}},
+	{name: "Azure Front Door", headers: []string{"X-Azure-Ref"}},
+	{name: "Varnish", headers: []string{"X-Varnish"}, server: "varnish"},
+}
+
+// NewHostFingerprint 根据响应头和TLS连接状态生成指纹
+func NewHostFingerprint(headers http.Header, state *tls.ConnectionState) *HostFingerprint {
+	fp := &HostFingerprint{
+		Server:    headers.Get("Server"),
+		PoweredBy: headers.Get("X-Powered-By"),
+		CDN:       detectCDN(headers),
+	}
+
+	if state != nil && len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		fp.TLSSubject = cert.Subject.String()
+		fp.TLSIssuer = cert.Issuer.String()
+	}
+
+	return fp
+}
+
+// detectCDN 根据响应头识别CDN/WAF
+func detectCDN(headers http.Header) []string {
+	var detected []string
+	server := strings.ToLower(headers.Get("Server"))
+
+	for _, sig := range cdnSignatures {
+		matched := sig.server != "" && strings.Contains(server, sig.server)
+		for _, name := range sig.headers {
+			if matched {
+				break
+			}
+			matched = headers.Get(name) != ""
+		}
+		if matched {
+			detected = append(detected, sig.name)
+		}
+	}
+
+	return detected
+}
+
+// String 格式化指纹信息
+func (fp *HostFingerprint) String() string {
+	var parts []string
+	if fp.Server != "" {
+		parts = append(parts, "Server: "+fp.Server)
+	}
+	if fp.PoweredBy != "" {
+		parts = append(parts, "Powered-By: "+fp.PoweredBy)
+	}
+	if len(fp.CDN) > 0 {
+		parts = append(parts, "CDN/WAF: "+strings.Join(fp.CDN, ", "))
+	}
+	if fp.TLSSubject != "" {
+		parts = append(parts, fmt.Sprintf("TLS: %s (issuer: %s)", fp.TLSSubject, fp.TLSIssuer))
+	}
+	if len(parts) == 0 {
+		return "无可识别信息"
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,91 @@
+package connection
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestDetectCDN(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected []string
+	}{
+		{
+			name:     "Cloudflare通过CF-Ray识别",
+			headers:  map[string]string{"CF-Ray": "7d1c2b3a4e5f6789-LAX"},
+			expected: []string{"Cloudflare"},
+		},
+		{
+			name:     "Cloudflare通过Server头识别",
+			headers:  map[string]string{"Server": "cloudflare"},
+			expected: []string{"Cloudflare"},
+		},
+		{
+			name:     "CloudFront通过X-Amz-Cf-Id识别",
+			headers:  map[string]string{"X-Amz-Cf-Id": "abc123==", "Server": "AmazonS3"},
+			expected: []string{"CloudFront"},
+		},
+		{
+			name:     "多个特征",
+			headers:  map[string]string{"X-Amz-Cf-Id": "abc123==", "X-Varnish": "12345"},
+			expected: []string{"CloudFront", "Varnish"},
+		},
+		{
+			name:     "无CDN",
+			headers:  map[string]string{"Server": "nginx/1.24.0"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := make(http.Header)
+			for key, value := range tt.headers {
+				headers.Set(key, value)
+			}
+			if got := detectCDN(headers); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("detectCDN() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecordFingerprintFirstResponseOnly(t *testing.T) {
+	hm := NewHostManager(&config.Config{})
+
+	first := make(http.Header)
+	first.Set("Server", "cloudflare")
+	first.Set("CF-Ray", "7d1c2b3a4e5f6789-LAX")
+	state := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{
+			Subject: pkix.Name{CommonName: "example.com"},
+			Issuer:  pkix.Name{CommonName: "Test CA"},
+		}},
+	}
+	hm.RecordFingerprint("example.com", first, state)
+
+	second := make(http.Header)
+	second.Set("Server", "nginx")
+	hm.RecordFingerprint("example.com", second, nil)
+
+	fp := hm.GetFingerprints()["example.com"]
+	if fp == nil {
+		t.Fatal("Expected fingerprint for example.com")
+	}
+	if fp.Server != "cloudflare" {
+		t.Errorf("Server = %q, want %q (first response only)", fp.Server, "cloudflare")
+	}
+	if !reflect.DeepEqual(fp.CDN, []string{"Cloudflare"}) {
+		t.Errorf("CDN = %v, want [Cloudflare]", fp.CDN)
+	}
+	if fp.TLSSubject != "CN=example.com" || fp.TLSIssuer != "CN=Test CA" {
+		t.Errorf("TLS = %q / %q, want CN=example.com / CN=Test CA", fp.TLSSubject, fp.TLSIssuer)
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 	"dirsearch-go/internal/config"
 
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 )
 
@@ -18,6 +20,7 @@ type HeadlessBrowser struct {
 	config *config.Config
 	ctx    context.Context
 	cancel context.CancelFunc
+	tabs   chan struct{} // 限制同时打开的标签页数量
 	mu     sync.RWMutex
 }
 
@@ -34,41 +37,124 @@ type HeadlessResult struct {
 	Error         error
 	ResponseTime  time.Duration
 	ContentLength int64
+	Screenshot    []byte // 整页PNG截图，只在ScanURLWithScreenshot要求截图时设置
+	ScreenshotErr error  // 截图失败的原因，不影响扫描结果
 }
 
 // NewHeadlessBrowser 创建新的无头浏览器
 func NewHeadlessBrowser(cfg *config.Config) (*HeadlessBrowser, error) {
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-web-security", true),
-		chromedp.Flag("disable-extensions", true),
-		chromedp.Flag("disable-plugins", true),
-		chromedp.Flag("disable-images", true),
-		chromedp.Flag("disable-background-timer-throttling", true),
-		chromedp.Flag("disable-backgrounding-occluded-windows", true),
-		chromedp.Flag("disable-renderer-backgrounding", true),
-		chromedp.Flag("disable-field-trial-config", true),
-		chromedp.Flag("disable-ipc-flooding-protection", true),
-		chromedp.Flag("enable-automation", false),
-		chromedp.Flag("disable-blink-features", "AutomationControlled"),
-		chromedp.Flag("user-agent", "dirsearch-go/1.0"),
-		chromedp.Flag("disable-logging", true),
-		chromedp.Flag("log-level", "0"),
-	)
-
-	ctx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	ctx, cancel = chromedp.NewContext(ctx, chromedp.WithLogf(log.Printf))
+	flags, err := headlessFlags(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append([]chromedp.ExecAllocatorOption{}, chromedp.DefaultExecAllocatorOptions[:]...)
+	for name, value := range flags {
+		opts = append(opts, chromedp.Flag(name, value))
+	}
+	if cfg.Connection.ChromePath != "" {
+		opts = append(opts, chromedp.ExecPath(cfg.Connection.ChromePath))
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, ctxCancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	cancel := func() {
+		ctxCancel()
+		allocCancel()
+	}
+
+	// 立即启动浏览器，确保Chrome可用
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to start browser: %w", err)
+	}
 
 	return &HeadlessBrowser{
 		config: cfg,
 		ctx:    ctx,
 		cancel: cancel,
+		tabs:   make(chan struct{}, headlessConcurrency(cfg)),
 	}, nil
 }
 
+// headlessConcurrency 获取无头模式最大并发标签页数
+func headlessConcurrency(cfg *config.Config) int {
+	if cfg.Connection.HeadlessConcurrency > 0 {
+		return cfg.Connection.HeadlessConcurrency
+	}
+	return 5
+}
+
+// withTab 在独立标签页中执行任务，并发数受HeadlessConcurrency限制，与扫描线程数无关
+func (hb *HeadlessBrowser) withTab(fn func(ctx context.Context) error) error {
+	select {
+	case hb.tabs <- struct{}{}:
+	case <-hb.ctx.Done():
+		return hb.ctx.Err()
+	}
+	defer func() { <-hb.tabs }()
+
+	tabCtx, tabCancel := chromedp.NewContext(hb.ctx)
+	defer tabCancel()
+
+	ctx, cancel := context.WithTimeout(tabCtx, time.Duration(hb.config.Connection.Timeout)*time.Second)
+	defer cancel()
+
+	return fn(ctx)
+}
+
+// headlessFlags 生成Chrome启动参数，使用配置的User-Agent并合并用户自定义参数
+func headlessFlags(cfg *config.Config) (map[string]interface{}, error) {
+	userAgent := cfg.Request.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	flags := map[string]interface{}{
+		"headless":                               true,
+		"disable-gpu":                            true,
+		"no-sandbox":                             true,
+		"disable-dev-shm-usage":                  true,
+		"disable-web-security":                   true,
+		"disable-extensions":                     true,
+		"disable-plugins":                        true,
+		"disable-images":                         true,
+		"disable-background-timer-throttling":    true,
+		"disable-backgrounding-occluded-windows": true,
+		"disable-renderer-backgrounding":         true,
+		"disable-field-trial-config":             true,
+		"disable-ipc-flooding-protection":        true,
+		"enable-automation":                      false,
+		"disable-blink-features":                 "AutomationControlled",
+		"user-agent":                             userAgent,
+		"disable-logging":                        true,
+		"log-level":                              "0",
+	}
+
+	// 自定义参数：name、name=value，值为false时移除该参数
+	for _, flag := range cfg.Connection.ChromeFlags {
+		flag = strings.TrimLeft(strings.TrimSpace(flag), "-")
+		if flag == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(flag, "=")
+		if name == "" {
+			return nil, fmt.Errorf("invalid chrome flag %q", flag)
+		}
+		switch {
+		case !hasValue || value == "true":
+			flags[name] = true
+		case value == "false":
+			flags[name] = false
+		default:
+			flags[name] = value
+		}
+	}
+
+	return flags, nil
+}
+
 // Close 关闭浏览器
 func (hb *HeadlessBrowser) Close() {
 	hb.mu.Lock()
@@ -80,6 +166,12 @@ func (hb *HeadlessBrowser) Close() {
 
 // ScanURL 扫描单个URL
 func (hb *HeadlessBrowser) ScanURL(targetURL string) *HeadlessResult {
+	return hb.ScanURLWithScreenshot(targetURL, nil)
+}
+
+// ScanURLWithScreenshot 扫描单个URL，shouldCapture对扫描结果返回true时在同一个标签页中截取整页截图，
+// 不重新加载页面。shouldCapture为nil时不截图
+func (hb *HeadlessBrowser) ScanURLWithScreenshot(targetURL string, shouldCapture func(*HeadlessResult) bool) *HeadlessResult {
 	startTime := time.Now()
 	result := &HeadlessResult{
 		URL:          targetURL,
@@ -88,33 +180,52 @@ func (hb *HeadlessBrowser) ScanURL(targetURL string) *HeadlessResult {
 		ResponseTime: 0,
 	}
 
-	// 设置超时
-	ctx, cancel := context.WithTimeout(hb.ctx, time.Duration(hb.config.Connection.Timeout)*time.Second)
-	defer cancel()
-
 	// 执行扫描任务
 	var title, content string
 	var statusCode int
+	var cookies []*network.Cookie
+
+	err := hb.withTab(func(ctx context.Context) error {
+		err := chromedp.Run(ctx,
+			hb.navigateAndWait(ctx, targetURL),
+			chromedp.Title(&title),
+			chromedp.OuterHTML("html", &content),
+			chromedp.Evaluate(`200`, &statusCode), // 简化状态码获取
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				// 获取导航过程中设置的Cookie
+				var err error
+				cookies, err = network.GetCookies().WithUrls([]string{targetURL}).Do(ctx)
+				return err
+			}),
+		)
+		if err != nil {
+			return err
+		}
 
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(targetURL),
-		chromedp.Sleep(1*time.Second), // 等待页面加载
-		chromedp.Title(&title),
-		chromedp.OuterHTML("html", &content),
-		chromedp.Evaluate(`200`, &statusCode), // 简化状态码获取
-	)
+		result.Title = title
+		result.Content = content
+		result.StatusCode = statusCode
+		result.ResponseTime = time.Since(startTime)
+		result.ContentLength = int64(len(content))
+		for _, cookie := range cookies {
+			result.Cookies = append(result.Cookies, cookie.Name+"="+cookie.Value)
+		}
+
+		// 页面仍在当前标签页中，直接截图
+		if shouldCapture != nil && shouldCapture(result) {
+			if err := chromedp.Run(ctx, chromedp.FullScreenshot(&result.Screenshot, 100)); err != nil {
+				result.Screenshot = nil
+				result.ScreenshotErr = fmt.Errorf("screenshot failed: %w", err)
+			}
+		}
+		return nil
+	})
 
 	if err != nil {
 		result.Error = fmt.Errorf("headless scan failed: %w", err)
 		return result
 	}
 
-	result.Title = title
-	result.Content = content
-	result.StatusCode = statusCode
-	result.ResponseTime = time.Since(startTime)
-	result.ContentLength = int64(len(content))
-
 	// 提取重定向信息
 	if len(result.Redirects) > 0 {
 		result.Redirects = append(result.Redirects, targetURL)
@@ -123,6 +234,61 @@ func (hb *HeadlessBrowser) ScanURL(targetURL string) *HeadlessResult {
 	return result
 }
 
+// navigateAndWait 导航到目标URL并按--headless-wait-for配置等待页面就绪
+// 支持CSS选择器（等待元素可见）和networkidle（等待网络空闲），未配置时等待body就绪
+func (hb *HeadlessBrowser) navigateAndWait(ctx context.Context, targetURL string) chromedp.Tasks {
+	waitFor := strings.TrimSpace(hb.config.Connection.HeadlessWaitFor)
+	waitTimeout := time.Duration(hb.config.Connection.HeadlessTimeout * float64(time.Second))
+	if waitTimeout <= 0 {
+		waitTimeout = time.Duration(hb.config.Connection.Timeout * float64(time.Second))
+	}
+
+	switch {
+	case waitFor == "":
+		return chromedp.Tasks{
+			chromedp.Navigate(targetURL),
+			chromedp.WaitReady("body", chromedp.ByQuery),
+		}
+	case strings.EqualFold(waitFor, "networkidle"):
+		// 监听器需在导航前注册
+		idle := make(chan struct{}, 1)
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			if e, ok := ev.(*page.EventLifecycleEvent); ok && e.Name == "networkIdle" {
+				select {
+				case idle <- struct{}{}:
+				default:
+				}
+			}
+		})
+		return chromedp.Tasks{
+			page.SetLifecycleEventsEnabled(true),
+			chromedp.Navigate(targetURL),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				select {
+				case <-idle:
+					return nil
+				case <-time.After(waitTimeout):
+					return fmt.Errorf("wait for network idle timed out after %s", waitTimeout)
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}),
+		}
+	default:
+		return chromedp.Tasks{
+			chromedp.Navigate(targetURL),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				waitCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+				defer cancel()
+				if err := chromedp.Run(waitCtx, chromedp.WaitVisible(waitFor, chromedp.ByQuery)); err != nil {
+					return fmt.Errorf("wait for selector %q failed: %w", waitFor, err)
+				}
+				return nil
+			}),
+		}
+	}
+}
+
 // ScanMultipleURLs 批量扫描URL
 func (hb *HeadlessBrowser) ScanMultipleURLs(urls []string, maxConcurrency int) []*HeadlessResult {
 	if maxConcurrency <= 0 {
@@ -0,0 +1,105 @@
+//go:build headless
+
+package connection
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+// newTestHeadlessBrowser 创建无头浏览器测试实例（需要本地安装Chrome）
+func newTestHeadlessBrowser(t *testing.T, cfg *config.Config) *HeadlessBrowser {
+	t.Helper()
+	if cfg.Connection.Timeout <= 0 {
+		cfg.Connection.Timeout = 15
+	}
+
+	browser, err := NewHeadlessBrowser(cfg)
+	if err != nil {
+		t.Fatalf("NewHeadlessBrowser failed: %v", err)
+	}
+	t.Cleanup(browser.Close)
+	return browser
+}
+
+func TestHeadlessScanURLCapturesCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+		w.Write([]byte("<html><head><title>Login</title></head><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	browser := newTestHeadlessBrowser(t, &config.Config{})
+	result := browser.ScanURL(server.URL + "/login")
+	if result.Error != nil {
+		t.Fatalf("ScanURL failed: %v", result.Error)
+	}
+
+	found := false
+	for _, cookie := range result.Cookies {
+		if cookie == "session=abc123" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected cookie session=abc123, got %v", result.Cookies)
+	}
+}
+
+func TestHeadlessWaitForSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><script>
+setTimeout(function() {
+	var el = document.createElement("div");
+	el.id = "app-ready";
+	el.textContent = "ready";
+	document.body.appendChild(el);
+}, 300);
+</script></body></html>`))
+	}))
+	defer server.Close()
+
+	// 选择器出现时等待完成
+	cfg := &config.Config{Connection: config.ConnectionConfig{HeadlessWaitFor: "#app-ready", HeadlessTimeout: 5}}
+	browser := newTestHeadlessBrowser(t, cfg)
+	if result := browser.ScanURL(server.URL + "/"); result.Error != nil {
+		t.Fatalf("Expected wait to complete, got: %v", result.Error)
+	}
+
+	// 选择器不出现时超时
+	cfg = &config.Config{Connection: config.ConnectionConfig{HeadlessWaitFor: "#never", HeadlessTimeout: 1}}
+	browser = newTestHeadlessBrowser(t, cfg)
+	if result := browser.ScanURL(server.URL + "/"); result.Error == nil {
+		t.Error("Expected wait for missing selector to time out")
+	}
+}
+
+func TestHeadlessScreenshotReusesPageLoad(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			hits.Add(1)
+		}
+		w.Write([]byte("<html><head><title>Admin</title></head><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	browser := newTestHeadlessBrowser(t, &config.Config{})
+	result := browser.ScanURLWithScreenshot(server.URL+"/admin", func(result *HeadlessResult) bool {
+		return result.Title == "Admin"
+	})
+	if result.Error != nil || result.ScreenshotErr != nil {
+		t.Fatalf("ScanURLWithScreenshot failed: %v, %v", result.Error, result.ScreenshotErr)
+	}
+	if !bytes.HasPrefix(result.Screenshot, []byte("\x89PNG")) {
+		t.Error("Screenshot is not a PNG file")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Page loaded %d times, want 1", got)
+	}
+}
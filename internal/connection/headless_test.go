@@ -0,0 +1,84 @@
+package connection
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestHeadlessFlags(t *testing.T) {
+	cfg := &config.Config{
+		Request: config.RequestConfig{UserAgent: "custom-agent/2.0"},
+		Connection: config.ConnectionConfig{
+			ChromeFlags: []string{"--proxy-server=http://127.0.0.1:8080", "disable-gpu=false", "mute-audio"},
+		},
+	}
+
+	flags, err := headlessFlags(cfg)
+	if err != nil {
+		t.Fatalf("headlessFlags failed: %v", err)
+	}
+
+	if flags["user-agent"] != "custom-agent/2.0" {
+		t.Errorf("user-agent = %v, want custom-agent/2.0", flags["user-agent"])
+	}
+	if flags["proxy-server"] != "http://127.0.0.1:8080" {
+		t.Errorf("proxy-server = %v, want http://127.0.0.1:8080", flags["proxy-server"])
+	}
+	if flags["disable-gpu"] != false {
+		t.Errorf("disable-gpu = %v, want false", flags["disable-gpu"])
+	}
+	if flags["mute-audio"] != true {
+		t.Errorf("mute-audio = %v, want true", flags["mute-audio"])
+	}
+
+	// 未配置User-Agent时使用默认值
+	flags, _ = headlessFlags(&config.Config{})
+	if flags["user-agent"] != DefaultUserAgent {
+		t.Errorf("default user-agent = %v, want %v", flags["user-agent"], DefaultUserAgent)
+	}
+}
+
+func TestHeadlessTabsLimitedByConcurrency(t *testing.T) {
+	cfg := &config.Config{
+		Connection: config.ConnectionConfig{Timeout: 5, HeadlessConcurrency: 2},
+	}
+	hb := &HeadlessBrowser{
+		config: cfg,
+		ctx:    context.Background(),
+		tabs:   make(chan struct{}, headlessConcurrency(cfg)),
+	}
+
+	var current, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hb.withTab(func(ctx context.Context) error {
+				n := atomic.AddInt32(&current, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&current, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Peak concurrent tabs = %d, want <= 2", peak)
+	}
+	if peak == 0 {
+		t.Error("Expected tasks to run")
+	}
+}
@@ -1,10 +1,14 @@
 package connection
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"net/url"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,17 +17,22 @@ import (
 
 // HostInfo 主机信息
 type HostInfo struct {
-	PingDelay  time.Duration
-	LastPing   time.Time
-	IsAlive    bool
-	SmartDelay *SmartDelay
+	PingDelay   time.Duration
+	LastPing    time.Time
+	IsAlive     bool
+	SmartDelay  *SmartDelay
+	Fingerprint *HostFingerprint
+
+	ConsecutiveErrors int  // 连续出错的请求数，请求成功时清零
+	Skipped           bool // 连续出错达到--max-consecutive-errors后跳过该主机
 }
 
 // HostManager 主机管理器
 type HostManager struct {
-	hosts  map[string]*HostInfo
-	mu     sync.RWMutex
-	config *config.Config
+	hosts        map[string]*HostInfo
+	hostTimeouts map[string]time.Duration
+	mu           sync.RWMutex
+	config       *config.Config
 }
 
 // NewHostManager 创建主机管理器
@@ -43,13 +52,48 @@ func NewHostManager(cfg *config.Config) *HostManager {
 		}
 	}
 
+	// 解析按主机覆盖的超时时间
+	hostTimeouts := make(map[string]time.Duration)
+	timeouts, err := config.ParseHostTimeouts(cfg.Connection.HostTimeout)
+	if err != nil {
+		log.Printf("Warning: Ignoring host timeout overrides: %v", err)
+	}
+	for host, seconds := range timeouts {
+		hostTimeouts[host] = time.Duration(seconds * float64(time.Second))
+	}
+
 	return &HostManager{
-		hosts:  make(map[string]*HostInfo),
-		config: cfg,
+		hosts:        make(map[string]*HostInfo),
+		hostTimeouts: hostTimeouts,
+		config:       cfg,
 	}
 }
 
-// GetOrCreateHostInfo 获取或创建主机信息
+// HostKey 返回URL对应的主机键（host:port），未指定端口时按协议补全默认端口，
+// 使同一主机不同端口（如host:80与host:443）的延迟、超时等状态相互独立
+func HostKey(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	hostname := strings.ToLower(u.Hostname())
+	if hostname == "" {
+		return ""
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch strings.ToLower(u.Scheme) {
+		case "https", "wss":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(hostname, port)
+}
+
+// GetOrCreateHostInfo 获取或创建主机信息，host应为HostKey返回的host:port
 func (hm *HostManager) GetOrCreateHostInfo(host string) *HostInfo {
 	defer func() {
 		if r := recover(); r != nil {
@@ -107,22 +151,27 @@ func (hm *HostManager) pingHost(host string, info *HostInfo) error {
 		return fmt.Errorf("empty host")
 	}
 
-	// 移除端口号（如果有）
-	if host, _, err := net.SplitHostPort(hostname); err == nil {
-		hostname = host
-	}
-
 	// 测量ping延迟
 	start := time.Now()
 
-	// 尝试80端口
-	conn, err := net.DialTimeout("tcp", hostname+":80", 5*time.Second)
-	if err != nil {
-		// 尝试443端口
-		conn, err = net.DialTimeout("tcp", hostname+":443", 5*time.Second)
+	var conn net.Conn
+	var err error
+	if _, _, splitErr := net.SplitHostPort(hostname); splitErr == nil {
+		// 带端口时直接探测该端口
+		conn, err = net.DialTimeout("tcp", hostname, 5*time.Second)
 		if err != nil {
 			return fmt.Errorf("failed to ping %s: %w", hostname, err)
 		}
+	} else {
+		// 尝试80端口
+		conn, err = net.DialTimeout("tcp", hostname+":80", 5*time.Second)
+		if err != nil {
+			// 尝试443端口
+			conn, err = net.DialTimeout("tcp", hostname+":443", 5*time.Second)
+			if err != nil {
+				return fmt.Errorf("failed to ping %s: %w", hostname, err)
+			}
+		}
 	}
 	defer func() {
 		if conn != nil {
@@ -162,6 +211,11 @@ func (hm *HostManager) GetTimeout(host string) time.Duration {
 		}
 	}()
 
+	// 优先使用按主机配置的超时时间
+	if timeout, ok := hm.hostTimeoutOverride(host); ok {
+		return timeout
+	}
+
 	info := hm.GetOrCreateHostInfo(host)
 	if info == nil || info.SmartDelay == nil {
 		return time.Duration(hm.config.Connection.Timeout) * time.Second
@@ -169,6 +223,26 @@ func (hm *HostManager) GetTimeout(host string) time.Duration {
 	return info.SmartDelay.GetTimeout()
 }
 
+// hostTimeoutOverride 查找主机的超时覆盖配置（先匹配host:port，再匹配主机名）
+func (hm *HostManager) hostTimeoutOverride(host string) (time.Duration, bool) {
+	if len(hm.hostTimeouts) == 0 || host == "" {
+		return 0, false
+	}
+
+	host = strings.ToLower(host)
+	if timeout, ok := hm.hostTimeouts[host]; ok {
+		return timeout, true
+	}
+
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		if timeout, ok := hm.hostTimeouts[hostname]; ok {
+			return timeout, true
+		}
+	}
+
+	return 0, false
+}
+
 // IsSlowResponse 判断是否为慢响应
 func (hm *HostManager) IsSlowResponse(host string, responseTime time.Duration) bool {
 	defer func() {
@@ -184,6 +258,88 @@ func (hm *HostManager) IsSlowResponse(host string, responseTime time.Duration) b
 	return info.SmartDelay.IsSlowResponse(responseTime)
 }
 
+// RecordFingerprint 记录主机的首个响应指纹，已记录时忽略
+func (hm *HostManager) RecordFingerprint(host string, headers http.Header, state *tls.ConnectionState) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("RecordFingerprint panic recovered: %v", r)
+		}
+	}()
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	info, exists := hm.hosts[host]
+	if !exists {
+		info = &HostInfo{SmartDelay: NewSmartDelay(hm.config)}
+		hm.hosts[host] = info
+	}
+	if info.Fingerprint == nil {
+		info.Fingerprint = NewHostFingerprint(headers, state)
+	}
+}
+
+// RecordRequest 记录主机的请求结果并统计连续错误，连续错误数达到--max-consecutive-errors时
+// 将主机标记为跳过，只在主机刚被标记时返回true
+func (hm *HostManager) RecordRequest(host string, failed bool) bool {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("RecordRequest panic recovered: %v", r)
+		}
+	}()
+
+	limit := hm.config.Connection.MaxConsecutiveErrors
+	if limit <= 0 || host == "" {
+		return false
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	info, exists := hm.hosts[host]
+	if !exists {
+		info = &HostInfo{SmartDelay: NewSmartDelay(hm.config)}
+		hm.hosts[host] = info
+	}
+	if info.Skipped {
+		return false
+	}
+	if !failed {
+		info.ConsecutiveErrors = 0
+		return false
+	}
+
+	info.ConsecutiveErrors++
+	if info.ConsecutiveErrors >= limit {
+		info.Skipped = true
+		return true
+	}
+	return false
+}
+
+// IsSkipped 检查主机是否因连续出错被跳过
+func (hm *HostManager) IsSkipped(host string) bool {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	info, exists := hm.hosts[host]
+	return exists && info.Skipped
+}
+
+// GetFingerprints 获取所有已记录的主机指纹
+func (hm *HostManager) GetFingerprints() map[string]*HostFingerprint {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	fingerprints := make(map[string]*HostFingerprint)
+	for host, info := range hm.hosts {
+		if info.Fingerprint != nil {
+			fingerprints[host] = info.Fingerprint
+		}
+	}
+	return fingerprints
+}
+
 // GetHostStats 获取主机统计信息
 func (hm *HostManager) GetHostStats() map[string]*HostInfo {
 	defer func() {
@@ -0,0 +1,152 @@
+package connection
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestGetTimeoutHostOverride(t *testing.T) {
+	cfg := &config.Config{
+		Connection: config.ConnectionConfig{
+			Timeout:     7.5,
+			HostTimeout: "slow.example.com=30,fast.example.com=3",
+		},
+	}
+	hm := NewHostManager(cfg)
+
+	// 预置未配置覆盖的主机，避免测试中进行真实的ping
+	hm.hosts["other.example.com"] = &HostInfo{SmartDelay: NewSmartDelay(cfg)}
+
+	tests := []struct {
+		name     string
+		host     string
+		expected time.Duration
+	}{
+		{
+			name:     "慢主机覆盖",
+			host:     "slow.example.com",
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "快主机覆盖",
+			host:     "fast.example.com",
+			expected: 3 * time.Second,
+		},
+		{
+			name:     "带端口的主机匹配主机名",
+			host:     "slow.example.com:8443",
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "未配置的主机使用全局超时",
+			host:     "other.example.com",
+			expected: 7500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hm.GetTimeout(tt.host); got != tt.expected {
+				t.Errorf("GetTimeout(%q) = %v, want %v", tt.host, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHostKey(t *testing.T) {
+	tests := []struct {
+		rawURL   string
+		expected string
+	}{
+		{rawURL: "http://Example.com/admin", expected: "example.com:80"},
+		{rawURL: "https://example.com/", expected: "example.com:443"},
+		{rawURL: "http://example.com:8080/", expected: "example.com:8080"},
+		{rawURL: "https://[::1]/", expected: "[::1]:443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawURL, func(t *testing.T) {
+			u, err := url.Parse(tt.rawURL)
+			if err != nil {
+				t.Fatalf("url.Parse failed: %v", err)
+			}
+			if got := HostKey(u); got != tt.expected {
+				t.Errorf("HostKey(%q) = %q, want %q", tt.rawURL, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHostStatePerPort(t *testing.T) {
+	cfg := &config.Config{Connection: config.ConnectionConfig{Timeout: 5, Delay: 1}}
+	hm := NewHostManager(cfg)
+
+	httpURL, _ := url.Parse("http://example.com/")
+	httpsURL, _ := url.Parse("https://example.com/")
+	httpKey, httpsKey := HostKey(httpURL), HostKey(httpsURL)
+
+	// 预置主机信息，避免测试中进行真实的ping
+	hm.hosts[httpKey] = &HostInfo{SmartDelay: NewSmartDelay(cfg)}
+	hm.hosts[httpsKey] = &HostInfo{SmartDelay: NewSmartDelay(cfg)}
+
+	httpInfo := hm.GetOrCreateHostInfo(httpKey)
+	httpsInfo := hm.GetOrCreateHostInfo(httpsKey)
+	if httpInfo == httpsInfo || httpInfo.SmartDelay == httpsInfo.SmartDelay {
+		t.Fatalf("Expected %s and %s to have independent host state", httpKey, httpsKey)
+	}
+
+	// 只更新http端口的ping延迟，https端口仍使用配置的延迟
+	httpInfo.SmartDelay.pingDelay = 50 * time.Millisecond
+	httpInfo.SmartDelay.SetMultiplier(4)
+	if got := hm.GetSmartDelay(httpKey); got != 200*time.Millisecond {
+		t.Errorf("GetSmartDelay(%s) = %v, want 200ms", httpKey, got)
+	}
+	if got := hm.GetSmartDelay(httpsKey); got != time.Second {
+		t.Errorf("GetSmartDelay(%s) = %v, want unaffected 1s", httpsKey, got)
+	}
+	if stats := hm.GetHostStats(); len(stats) != 2 {
+		t.Errorf("Expected 2 tracked hosts, got %d", len(stats))
+	}
+}
+
+func TestRecordRequestSkipsHost(t *testing.T) {
+	cfg := &config.Config{Connection: config.ConnectionConfig{Timeout: 7.5, MaxConsecutiveErrors: 3}}
+	hm := NewHostManager(cfg)
+	host := "down.example.com:80"
+
+	// 成功的请求清零计数
+	for _, failed := range []bool{true, true, false, true, true} {
+		if hm.RecordRequest(host, failed) {
+			t.Fatalf("Host skipped before %d consecutive errors", cfg.Connection.MaxConsecutiveErrors)
+		}
+	}
+	if hm.IsSkipped(host) {
+		t.Fatal("IsSkipped() = true before reaching the limit")
+	}
+
+	if !hm.RecordRequest(host, true) {
+		t.Error("RecordRequest() = false on the third consecutive error, want true")
+	}
+	if !hm.IsSkipped(host) {
+		t.Error("IsSkipped() = false after 3 consecutive errors")
+	}
+	if hm.RecordRequest(host, true) {
+		t.Error("RecordRequest() reported an already skipped host again")
+	}
+	if hm.IsSkipped("other.example.com:80") {
+		t.Error("Other hosts should not be skipped")
+	}
+}
+
+func TestRecordRequestDisabled(t *testing.T) {
+	hm := NewHostManager(&config.Config{Connection: config.ConnectionConfig{Timeout: 7.5}})
+	for i := 0; i < 10; i++ {
+		hm.RecordRequest("down.example.com:80", true)
+	}
+	if hm.IsSkipped("down.example.com:80") {
+		t.Error("Host skipped although --max-consecutive-errors is not set")
+	}
+}
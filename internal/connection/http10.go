@@ -0,0 +1,148 @@
+package connection
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// http10Transport 以HTTP/1.0发送请求的传输层（--http10）。net/http总是以HTTP/1.1写出请求行，
+// 因此自行建立连接、写出请求并读取响应；HTTP/1.0没有持久连接，每个请求使用新的连接，响应体关闭时断开
+type http10Transport struct {
+	dial      func(ctx context.Context, network, addr string) (net.Conn, error)
+	tlsConfig *tls.Config
+}
+
+// newHTTP10Transport 创建HTTP/1.0传输层，沿用base的拨号器（--ip、--interface）和TLS配置（客户端证书）
+func newHTTP10Transport(base *http.Transport) *http10Transport {
+	dial := base.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return &http10Transport{dial: dial, tlsConfig: base.TLSClientConfig}
+}
+
+// RoundTrip 通过新的连接以HTTP/1.0发送请求
+func (t *http10Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	conn, err := t.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求取消或超时时关闭连接，中断阻塞的读写
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+
+	if err := writeHTTP10Request(conn, req); err != nil {
+		stop()
+		conn.Close()
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		stop()
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	resp.Body = &http10Body{ReadCloser: resp.Body, conn: conn, stop: stop}
+	return resp, nil
+}
+
+// connect 建立到目标主机的连接，https目标完成TLS握手
+func (t *http10Transport) connect(ctx context.Context, req *http.Request) (net.Conn, error) {
+	host := req.URL.Hostname()
+	port := req.URL.Port()
+	if port == "" {
+		port = "80"
+		if req.URL.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	conn, err := t.dial(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	if req.URL.Scheme != "https" {
+		return conn, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if t.tlsConfig != nil {
+		tlsConfig = t.tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// writeHTTP10Request 写出HTTP/1.0请求：请求行、Host、请求头（Connection固定为close）和带Content-Length的请求体
+func writeHTTP10Request(w io.Writer, req *http.Request) error {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+		body = data
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s HTTP/1.0\r\n", req.Method, req.URL.RequestURI())
+	fmt.Fprintf(&buf, "Host: %s\r\n", host)
+	header := req.Header.Clone()
+	for _, name := range []string{"Host", "Connection", "Content-Length", "Transfer-Encoding"} {
+		header.Del(name)
+	}
+	if err := header.Write(&buf); err != nil {
+		return err
+	}
+	buf.WriteString("Connection: close\r\n")
+	if len(body) > 0 || req.ContentLength > 0 {
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n", len(body))
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// http10Body 响应体关闭时关闭底层连接
+type http10Body struct {
+	io.ReadCloser
+	conn net.Conn
+	stop func() bool
+}
+
+// Close 关闭响应体和连接
+func (b *http10Body) Close() error {
+	b.stop()
+	err := b.ReadCloser.Close()
+	if closeErr := b.conn.Close(); err == nil && !errors.Is(closeErr, net.ErrClosed) {
+		err = closeErr
+	}
+	return err
+}
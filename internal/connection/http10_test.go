@@ -0,0 +1,70 @@
+package connection
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTP10Requests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		// 回显请求的协议版本、Host、是否关闭连接和请求体
+		fmt.Fprintf(w, "<title>%s</title>host=%s close=%v body=%s path=%s", r.Proto, r.Host, r.Close, body, r.URL.RequestURI())
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		http10 bool
+		method string
+		data   string
+		want   []string
+	}{
+		{name: "HTTP/1.0 GET", http10: true, method: "GET", want: []string{"HTTP/1.0", "close=true", "path=/admin?id=1"}},
+		{name: "HTTP/1.0 POST", http10: true, method: "POST", data: "user=admin", want: []string{"HTTP/1.0", "body=user=admin"}},
+		{name: "默认HTTP/1.1", http10: false, method: "GET", want: []string{"HTTP/1.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Connection.HTTP10 = tt.http10
+			cfg.Request.HTTPMethod = tt.method
+			cfg.Request.Data = tt.data
+			r, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			resp, err := r.Request(server.URL + "/admin?id=1")
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+			}
+			host := strings.TrimPrefix(server.URL, "http://")
+			if !strings.Contains(resp.Body, "host="+host) {
+				t.Errorf("Body %q does not contain host=%s", resp.Body, host)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(resp.Body, want) {
+					t.Errorf("Body %q does not contain %q", resp.Body, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTP10RejectsProxy(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Connection.HTTP10 = true
+	cfg.Connection.Proxy = "http://127.0.0.1:8080"
+	if _, err := NewRequester(cfg); err == nil {
+		t.Error("Expected an error when --http10 is used with a proxy")
+	}
+}
@@ -0,0 +1,99 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runPreflight 执行预请求，从响应中提取令牌并注入到后续所有请求的请求头中
+func (r *Requester) runPreflight() error {
+	preflightURL := r.config.Request.PreflightURL
+	if preflightURL == "" {
+		return nil
+	}
+
+	if r.config.Request.ExtractTokenRegex == "" {
+		return fmt.Errorf("--extract-token-regex is required when --preflight-url is set")
+	}
+	tokenRegex, err := regexp.Compile(r.config.Request.ExtractTokenRegex)
+	if err != nil {
+		return fmt.Errorf("invalid token regex: %w", err)
+	}
+
+	tokenHeader := r.config.Request.TokenHeader
+	if tokenHeader == "" {
+		tokenHeader = "X-CSRF-Token"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.config.Connection.Timeout*float64(time.Second)))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, preflightURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create preflight request: %w", err)
+	}
+	for key, value := range r.headers {
+		if strings.EqualFold(key, "Content-Type") {
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	// 预请求始终跟随重定向，以便从最终页面提取令牌
+	client := *r.client
+	client.CheckRedirect = nil
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("preflight request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read preflight response: %w", err)
+	}
+
+	token := extractToken(tokenRegex, resp.Header, string(body))
+	if token == "" {
+		return fmt.Errorf("no token matching %q found in preflight response from %s", tokenRegex.String(), preflightURL)
+	}
+
+	r.headers[tokenHeader] = token
+	return nil
+}
+
+// extractToken 在响应头和响应体中查找令牌，有捕获组时返回第一个捕获组。
+// 响应头按名称排序后查找，多个响应头匹配时每次运行得到相同的令牌
+func extractToken(tokenRegex *regexp.Regexp, headers http.Header, body string) string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var headerText strings.Builder
+	for _, key := range keys {
+		for _, value := range headers[key] {
+			fmt.Fprintf(&headerText, "%s: %s\n", key, value)
+		}
+	}
+
+	for _, text := range []string{headerText.String(), body} {
+		match := tokenRegex.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		if len(match) > 1 {
+			return match[1]
+		}
+		return match[0]
+	}
+
+	return ""
+}
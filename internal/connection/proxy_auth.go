@@ -0,0 +1,22 @@
+package connection
+
+import (
+	"net/url"
+	"strings"
+)
+
+// applyProxyAuth 将--proxy-auth凭据（user:pass）设置到代理URL上，HTTP代理据此发送Proxy-Authorization，
+// SOCKS5代理据此进行用户名/密码认证。代理URL中已包含凭据时保持不变
+func applyProxyAuth(proxyURL *url.URL, auth string) *url.URL {
+	if proxyURL == nil || auth == "" || proxyURL.User != nil {
+		return proxyURL
+	}
+
+	withAuth := *proxyURL
+	if user, password, ok := strings.Cut(auth, ":"); ok {
+		withAuth.User = url.UserPassword(user, password)
+	} else {
+		withAuth.User = url.User(auth)
+	}
+	return &withAuth
+}
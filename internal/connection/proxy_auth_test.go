@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestProxyAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		userinfo  string // 代理URL中内嵌的凭据
+		proxyAuth string
+		want      string
+	}{
+		{name: "使用--proxy-auth", proxyAuth: "user:s3cret", want: "user:s3cret"},
+		{name: "保留URL中的凭据", userinfo: "embedded:pw@", proxyAuth: "user:s3cret", want: "embedded:pw"},
+		{name: "未配置认证", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got string
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				got = r.Header.Get("Proxy-Authorization")
+				mu.Unlock()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer proxy.Close()
+
+			cfg := newTestConfig()
+			cfg.Connection.Proxy = strings.Replace(proxy.URL, "http://", "http://"+tt.userinfo, 1)
+			cfg.Connection.ProxyAuth = tt.proxyAuth
+			requester, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+			if _, err := requester.Request(proxy.URL + "/admin"); err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+
+			want := ""
+			if tt.want != "" {
+				want = "Basic " + base64.StdEncoding.EncodeToString([]byte(tt.want))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got != want {
+				t.Errorf("Proxy-Authorization = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyProxyAuthSOCKS5(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Connection.ProxyRules = "*=socks5://127.0.0.1:1080"
+	cfg.Connection.ProxyAuth = "user:s3cret"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	proxyURL, err := requester.client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxyURL == nil {
+		t.Fatalf("Proxy callback = %v, %v", proxyURL, err)
+	}
+	if password, _ := proxyURL.User.Password(); proxyURL.User.Username() != "user" || password != "s3cret" {
+		t.Errorf("SOCKS5 proxy credentials = %v, want user:s3cret", proxyURL.User)
+	}
+}
@@ -0,0 +1,126 @@
+package connection
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+// proxyMaxFailures 代理连续失败多少次后暂时移出轮换
+const proxyMaxFailures = 3
+
+// proxyEjectDuration 失败的代理移出轮换的时间，之后重新参与轮换
+var proxyEjectDuration = 30 * time.Second
+
+// pooledProxy 代理池中的单个代理，拥有独立的连接池
+type pooledProxy struct {
+	url          *url.URL
+	client       *http.Client
+	failures     int       // 连续失败次数
+	ejectedUntil time.Time // 移出轮换的截止时间
+}
+
+// proxyPool --proxies-file代理池，每个请求按轮询选择一个代理，连续失败的代理暂时移出轮换
+type proxyPool struct {
+	mu      sync.Mutex
+	proxies []*pooledProxy
+	next    int
+}
+
+// loadProxyList 读取--proxies-file和配置中的proxies，每行一个代理，忽略空行和#注释，未写协议时使用http://
+func loadProxyList(cfg *config.Config) ([]string, error) {
+	proxies := append([]string(nil), cfg.Connection.Proxies...)
+	if cfg.Connection.ProxyFile != "" {
+		data, err := os.ReadFile(cfg.Connection.ProxyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read proxies file: %w", err)
+		}
+		proxies = append(proxies, strings.Split(string(data), "\n")...)
+	}
+
+	var list []string
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" || strings.HasPrefix(proxy, "#") {
+			continue
+		}
+		if !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy
+		}
+		list = append(list, proxy)
+	}
+	return list, nil
+}
+
+// newProxyPool 为每个代理创建使用独立传输层的客户端，base提供超时、重定向和传输层的其余配置
+func newProxyPool(proxies []string, auth string, base *http.Client) (*proxyPool, error) {
+	baseTransport := ensureTransport(&http.Client{Transport: base.Transport})
+
+	pool := &proxyPool{}
+	for _, proxy := range proxies {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL in proxy list: %w", config.URLParseError(err))
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %s in proxy list: no host", proxyURL.Redacted())
+		}
+		proxyURL = applyProxyAuth(proxyURL, auth)
+
+		transport := baseTransport.Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		client := *base
+		client.Transport = transport
+		pool.proxies = append(pool.proxies, &pooledProxy{url: proxyURL, client: &client})
+	}
+	return pool, nil
+}
+
+// Size 返回代理池中的代理数量
+func (p *proxyPool) Size() int {
+	return len(p.proxies)
+}
+
+// pick 按轮询选择下一个可用代理；所有代理都被移出轮换时选择最早恢复的代理，不阻塞扫描
+func (p *proxyPool) pick() *pooledProxy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var earliest *pooledProxy
+	for i := 0; i < len(p.proxies); i++ {
+		proxy := p.proxies[(p.next+i)%len(p.proxies)]
+		if !now.Before(proxy.ejectedUntil) {
+			p.next = (p.next + i + 1) % len(p.proxies)
+			return proxy
+		}
+		if earliest == nil || proxy.ejectedUntil.Before(earliest.ejectedUntil) {
+			earliest = proxy
+		}
+	}
+	return earliest
+}
+
+// record 记录通过代理发送的请求结果，连续失败proxyMaxFailures次的代理移出轮换proxyEjectDuration
+func (p *proxyPool) record(proxy *pooledProxy, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !failed {
+		proxy.failures = 0
+		return
+	}
+	proxy.failures++
+	if proxy.failures >= proxyMaxFailures {
+		proxy.failures = 0
+		proxy.ejectedUntil = time.Now().Add(proxyEjectDuration)
+		log.Printf("Warning: Proxy %s failed %d times in a row, removed from rotation for %v", proxy.url.Redacted(), proxyMaxFailures, proxyEjectDuration)
+	}
+}
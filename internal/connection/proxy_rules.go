@@ -0,0 +1,26 @@
+package connection
+
+import (
+	"net/http"
+	"net/url"
+
+	"dirsearch-go/internal/config"
+)
+
+// proxyRulesFunc 返回按请求主机选择代理的Transport.Proxy回调，
+// 第一条匹配的规则生效，没有规则匹配时使用fallback（为nil时直连）
+func proxyRulesFunc(rules []config.ProxyRule, fallback *url.URL) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(rules, req.URL.Hostname(), fallback), nil
+	}
+}
+
+// selectProxy 为主机选择代理，返回nil表示直连
+func selectProxy(rules []config.ProxyRule, host string, fallback *url.URL) *url.URL {
+	for _, rule := range rules {
+		if rule.Matches(host) {
+			return rule.Proxy
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,49 @@
+package connection
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProxyRules(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Connection.Proxy = "http://fallback.example:8080"
+	cfg.Connection.ProxyRules = "*.internal=direct;*.example.com=socks5://127.0.0.1:1080"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	transport, ok := requester.client.Transport.(*http.Transport)
+	if !ok || transport.Proxy == nil {
+		t.Fatalf("Transport has no proxy callback")
+	}
+
+	tests := []struct {
+		url  string
+		want string // 空字符串表示直连
+	}{
+		{url: "http://db.internal/admin", want: ""},
+		{url: "https://api.corp.internal:8443/", want: ""},
+		{url: "https://www.example.com/", want: "socks5://127.0.0.1:1080"},
+		{url: "http://other.org/", want: "http://fallback.example:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+			proxyURL, err := transport.Proxy(req)
+			if err != nil {
+				t.Fatalf("Proxy callback failed: %v", err)
+			}
+			got := ""
+			if proxyURL != nil {
+				got = proxyURL.String()
+			}
+			if got != tt.want {
+				t.Errorf("Proxy for %s = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package connection
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http/httputil"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strings"
+
+	"dirsearch-go/internal/config"
+)
+
+// rawSkippedHeaders 原始请求中不作为自定义请求头发送的字段：
+// Host用于生成目标URL，Content-Length和Transfer-Encoding按实际发送的请求体重新计算
+var rawSkippedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+}
+
+// LoadRawRequest 读取--raw指定的原始HTTP请求文件（如Burp保存的请求）
+func LoadRawRequest(filename, scheme string) (string, config.RequestConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", config.RequestConfig{}, fmt.Errorf("failed to read raw request: %w", err)
+	}
+	return ParseRawRequest(data, scheme)
+}
+
+// ParseRawRequest 解析原始HTTP请求，返回目标URL和请求配置（方法、请求头、Cookie、User-Agent和请求体）。
+// 原始请求没有协议，使用scheme补全（为空时使用http）；请求行为绝对URL时直接使用
+func ParseRawRequest(data []byte, scheme string) (string, config.RequestConfig, error) {
+	var reqCfg config.RequestConfig
+
+	// 请求头和请求体以第一个空行分隔，兼容CRLF和LF换行
+	head, body := data, []byte(nil)
+	if i := bytes.Index(data, []byte("\r\n\r\n")); i >= 0 {
+		head, body = data[:i], data[i+4:]
+	} else if i := bytes.Index(data, []byte("\n\n")); i >= 0 {
+		head, body = data[:i], data[i+2:]
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r\n", "\n"), "\n")
+	method, target, ok := parseRawRequestLine(lines[0])
+	if !ok {
+		return "", reqCfg, fmt.Errorf("invalid request line: %q", lines[0])
+	}
+	reqCfg.HTTPMethod = method
+
+	host := ""
+	chunked := false
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", reqCfg, fmt.Errorf("invalid header line: %q", line)
+		}
+		name = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		switch name {
+		case "Host":
+			host = value
+		case "Transfer-Encoding":
+			chunked = strings.EqualFold(value, "chunked")
+		case "Cookie":
+			reqCfg.Cookie = value
+		case "User-Agent":
+			reqCfg.UserAgent = value
+		}
+		if rawSkippedHeaders[name] || name == "Cookie" || name == "User-Agent" {
+			continue
+		}
+		reqCfg.Headers = append(reqCfg.Headers, name+": "+value)
+	}
+
+	if chunked {
+		decoded, err := io.ReadAll(httputil.NewChunkedReader(bytes.NewReader(body)))
+		if err != nil {
+			return "", reqCfg, fmt.Errorf("failed to decode chunked body: %w", err)
+		}
+		body = decoded
+	}
+	reqCfg.Data = string(body)
+
+	targetURL, err := rawTargetURL(target, host, scheme)
+	if err != nil {
+		return "", reqCfg, err
+	}
+	return targetURL, reqCfg, nil
+}
+
+// parseRawRequestLine 解析请求行：METHOD request-target [HTTP-version]
+func parseRawRequestLine(line string) (method, target string, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return "", "", false
+	}
+	if len(fields) == 3 && !strings.HasPrefix(fields[2], "HTTP/") {
+		return "", "", false
+	}
+	return strings.ToUpper(fields[0]), fields[1], true
+}
+
+// rawTargetURL 根据请求行中的目标、Host请求头和协议生成目标URL
+func rawTargetURL(target, host, scheme string) (string, error) {
+	// 代理格式的请求行已包含完整URL
+	if parsed, err := url.Parse(target); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		return parsed.String(), nil
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("raw request has no Host header")
+	}
+	if !strings.HasPrefix(target, "/") {
+		return "", fmt.Errorf("invalid request target: %q", target)
+	}
+
+	scheme = strings.ToLower(strings.TrimSuffix(scheme, "://"))
+	if scheme == "" {
+		scheme = "http"
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme for raw request: %s", scheme)
+	}
+
+	parsed, err := url.Parse(scheme + "://" + host + target)
+	if err != nil {
+		return "", fmt.Errorf("invalid raw request URL: %w", err)
+	}
+	return parsed.String(), nil
+}
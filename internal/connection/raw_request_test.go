@@ -0,0 +1,123 @@
+package connection
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRawRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		scheme    string
+		wantURL   string
+		method    string
+		headers   []string
+		cookie    string
+		userAgent string
+		data      string
+	}{
+		{
+			name: "Burp保存的POST请求",
+			raw: "POST /app/login HTTP/1.1\r\n" +
+				"Host: example.com\r\n" +
+				"User-Agent: Mozilla/5.0\r\n" +
+				"Cookie: session=abc\r\n" +
+				"Content-Type: application/x-www-form-urlencoded\r\n" +
+				"Content-Length: 3\r\n" +
+				"\r\n" +
+				"user=admin&pass=secret",
+			scheme:    "https",
+			wantURL:   "https://example.com/app/login",
+			method:    "POST",
+			headers:   []string{"Content-Type: application/x-www-form-urlencoded"},
+			cookie:    "session=abc",
+			userAgent: "Mozilla/5.0",
+			data:      "user=admin&pass=secret",
+		},
+		{
+			name:    "LF换行且未指定协议",
+			raw:     "get /admin/ HTTP/1.1\nhost: example.com:8080\nx-api-key: 123\n\n",
+			wantURL: "http://example.com:8080/admin/",
+			method:  "GET",
+			headers: []string{"X-Api-Key: 123"},
+		},
+		{
+			name:    "代理格式的绝对URL",
+			raw:     "GET https://api.example.com/v1/ HTTP/1.1\r\nHost: ignored.example.com\r\n\r\n",
+			scheme:  "http",
+			wantURL: "https://api.example.com/v1/",
+			method:  "GET",
+		},
+		{
+			name: "分块传输的请求体",
+			raw: "PUT /upload HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n" +
+				"5\r\nhello\r\n6\r\n world\r\n0\r\n\r\n",
+			wantURL: "http://example.com/upload",
+			method:  "PUT",
+			data:    "hello world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, reqCfg, err := ParseRawRequest([]byte(tt.raw), tt.scheme)
+			if err != nil {
+				t.Fatalf("ParseRawRequest failed: %v", err)
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", gotURL, tt.wantURL)
+			}
+			if reqCfg.HTTPMethod != tt.method {
+				t.Errorf("Method = %q, want %q", reqCfg.HTTPMethod, tt.method)
+			}
+			if !reflect.DeepEqual(reqCfg.Headers, tt.headers) {
+				t.Errorf("Headers = %q, want %q", reqCfg.Headers, tt.headers)
+			}
+			if reqCfg.Cookie != tt.cookie || reqCfg.UserAgent != tt.userAgent {
+				t.Errorf("Cookie = %q, User-Agent = %q, want %q, %q", reqCfg.Cookie, reqCfg.UserAgent, tt.cookie, tt.userAgent)
+			}
+			if reqCfg.Data != tt.data {
+				t.Errorf("Data = %q, want %q", reqCfg.Data, tt.data)
+			}
+		})
+	}
+}
+
+func TestParseRawRequestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		scheme string
+	}{
+		{name: "缺少Host请求头", raw: "GET / HTTP/1.1\r\nAccept: */*\r\n\r\n"},
+		{name: "无效的请求行", raw: "GET\r\nHost: example.com\r\n\r\n"},
+		{name: "无效的请求头", raw: "GET / HTTP/1.1\r\nHost example.com\r\n\r\n"},
+		{name: "不支持的协议", raw: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n", scheme: "ftp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseRawRequest([]byte(tt.raw), tt.scheme); err == nil {
+				t.Error("ParseRawRequest succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestLoadRawRequest(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "request.txt")
+	if err := os.WriteFile(filename, []byte("GET /api/ HTTP/1.1\r\nHost: example.com\r\n\r\n"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	gotURL, _, err := LoadRawRequest(filename, "https://")
+	if err != nil || gotURL != "https://example.com/api/" {
+		t.Errorf("LoadRawRequest() = %q, %v, want https://example.com/api/", gotURL, err)
+	}
+	if _, _, err := LoadRawRequest(filepath.Join(t.TempDir(), "missing.txt"), ""); err == nil {
+		t.Error("LoadRawRequest(missing) succeeded, want error")
+	}
+}
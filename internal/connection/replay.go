@@ -0,0 +1,90 @@
+package connection
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/utils"
+)
+
+// newReplayClient 根据重放代理配置创建重放客户端，未配置时返回nil。
+// 重放请求发往用户自己的拦截代理（如Burp），代理的CA通常不受系统信任，因此重放时不校验服务端证书
+func newReplayClient(cfg *config.Config) (*http.Client, error) {
+	if cfg.Connection.ReplayProxy == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(cfg.Connection.ReplayProxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replay proxy URL: %w", err)
+	}
+
+	timeout := time.Duration(cfg.Connection.Timeout * float64(time.Second))
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(proxyURL),
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		// 重放请求只需经过代理，不跟随重定向
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}, nil
+}
+
+// CanReplay 是否配置了重放代理
+func (r *Requester) CanReplay() bool {
+	return r.replayClient != nil
+}
+
+// Replay 通过重放代理重新发送请求
+func (r *Requester) Replay(targetURL string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Replay panic recovered: %v", r)
+			err = utils.PanicError(r)
+		}
+	}()
+
+	if r.replayClient == nil {
+		return nil
+	}
+
+	req, err := r.newReplayRequest(targetURL)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.replayClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("replay request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 读完响应体以便代理记录完整响应
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// newReplayRequest 创建重放请求，沿用扫描请求的方法、请求头和请求体，并附加--replay-header指定的请求头
+func (r *Requester) newReplayRequest(targetURL string) (*http.Request, error) {
+	req, err := r.newRequest(targetURL, "")
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range r.replayHeaders {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
@@ -0,0 +1,148 @@
+package connection
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// headerRecorder 记录服务端收到的请求头
+type headerRecorder struct {
+	mu      sync.Mutex
+	method  string
+	url     string
+	headers http.Header
+}
+
+func (h *headerRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.method = r.Method
+	h.url = r.URL.String()
+	h.headers = r.Header.Clone()
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestReplayHeadersOnlyOnReplay(t *testing.T) {
+	target := &headerRecorder{}
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+
+	// 重放代理收到的是发往目标的代理请求，直接记录即可
+	proxy := &headerRecorder{}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+
+	cfg := newTestConfig()
+	cfg.Request.HTTPMethod = "POST"
+	cfg.Request.Data = "a=1"
+	cfg.Request.Headers = []string{"X-Scan: yes"}
+	cfg.Connection.ReplayProxy = proxyServer.URL
+	cfg.Connection.ReplayHeaders = []string{"X-Burp-Tag: dirsearch"}
+
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if !requester.CanReplay() {
+		t.Fatal("Expected requester to be able to replay")
+	}
+
+	fullURL := targetServer.URL + "/admin"
+	if _, err := requester.Request(fullURL); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if got := target.headers.Get("X-Burp-Tag"); got != "" {
+		t.Errorf("Scan request should not carry replay header, got %q", got)
+	}
+
+	if err := requester.Replay(fullURL); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if proxy.url != fullURL {
+		t.Errorf("Replay proxy got URL %q, want %q", proxy.url, fullURL)
+	}
+	if proxy.method != "POST" {
+		t.Errorf("Replay method = %q, want POST", proxy.method)
+	}
+	if got := proxy.headers.Get("X-Burp-Tag"); got != "dirsearch" {
+		t.Errorf("Replay header X-Burp-Tag = %q, want %q", got, "dirsearch")
+	}
+	if got := proxy.headers.Get("X-Scan"); got != "yes" {
+		t.Errorf("Replay should reuse scan headers, X-Scan = %q", got)
+	}
+}
+
+// connectProxy 只转发CONNECT隧道的代理，记录隧道的目标地址
+type connectProxy struct {
+	mu      sync.Mutex
+	tunnels []string
+}
+
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "expected CONNECT", http.StatusMethodNotAllowed)
+		return
+	}
+	p.mu.Lock()
+	p.tunnels = append(p.tunnels, r.Host)
+	p.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	go func() {
+		io.Copy(upstream, conn)
+		upstream.Close()
+	}()
+	io.Copy(conn, upstream)
+	conn.Close()
+}
+
+func TestReplayHTTPS(t *testing.T) {
+	// 目标使用不受信任的自签名证书，相当于经Burp等拦截代理时看到的代理CA
+	target := &headerRecorder{}
+	targetServer := httptest.NewTLSServer(target)
+	defer targetServer.Close()
+
+	proxy := &connectProxy{}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.ReplayProxy = proxyServer.URL
+	cfg.Connection.ReplayHeaders = []string{"X-Burp-Tag: dirsearch"}
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	defer requester.replayClient.CloseIdleConnections()
+
+	fullURL := targetServer.URL + "/admin"
+	if err := requester.Replay(fullURL); err != nil {
+		t.Fatalf("Replay over HTTPS failed: %v", err)
+	}
+
+	proxy.mu.Lock()
+	tunnels := proxy.tunnels
+	proxy.mu.Unlock()
+	if len(tunnels) != 1 || tunnels[0] != targetServer.Listener.Addr().String() {
+		t.Errorf("Proxy tunnels = %v, want one to %s", tunnels, targetServer.Listener.Addr())
+	}
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if target.url != "/admin" || target.headers.Get("X-Burp-Tag") != "dirsearch" {
+		t.Errorf("Target got %q with X-Burp-Tag %q, want /admin with the replay header", target.url, target.headers.Get("X-Burp-Tag"))
+	}
+}
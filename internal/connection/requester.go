@@ -1,26 +1,38 @@
 package connection
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/http/httputil"
 	"net/url"
+	"os"
 	"runtime/debug"
 	"strings"
 	"time"
 
 	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/utils"
+
+	"golang.org/x/time/rate"
 )
 
+// DefaultUserAgent 默认User-Agent
+const DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
 // Response HTTP响应
 type Response struct {
 	StatusCode    int
 	ContentLength int64
 	Body          string
-	Redirect      string
+	Redirect      string // 按请求URL解析后的绝对重定向地址
+	RawRedirect   string // 原始Location响应头
 	Headers       http.Header
+	ResponseTime  time.Duration // 从发送请求到读取完响应体的总耗时
 }
 
 // Requester HTTP请求器
@@ -28,14 +40,26 @@ type Requester struct {
 	client      *http.Client
 	config      *config.Config
 	headers     map[string]string
+	body        []byte
 	HostManager *HostManager
+	bandwidth   *BandwidthLimiter // --max-bandwidth限制读取响应体的总带宽，为nil时不限制
+	authMap     map[string]string // --auth-map按主机配置的认证凭据
+
+	replayClient  *http.Client      // --replay-proxy重放客户端，为nil时不重放
+	replayHeaders map[string]string // --replay-header只添加到重放请求上的请求头
+
+	ctx         context.Context // 扫描上下文，取消后不再重试失败的请求
+	retryStatus map[int]bool    // --retry-on-status需要重试的状态码，为nil时重试5xx和429
+	proxyPool   *proxyPool      // --proxies-file代理池，为nil时使用client的代理设置
+	limiter     *rate.Limiter   // --max-rate请求速率限制，每次发送（包括重试）前等待，为nil时不限制
 }
 
 // NewRequester 创建新的请求器
-func NewRequester(cfg *config.Config) (*Requester, error) {
+func NewRequester(cfg *config.Config) (_ *Requester, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("NewRequester panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
 		}
 	}()
 
@@ -43,28 +67,131 @@ func NewRequester(cfg *config.Config) (*Requester, error) {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
-	// 创建HTTP客户端
-	client := &http.Client{
-		Timeout: time.Duration(cfg.Connection.Timeout) * time.Second,
+	// 验证按主机覆盖的超时配置
+	if _, err := config.ParseHostTimeouts(cfg.Connection.HostTimeout); err != nil {
+		return nil, fmt.Errorf("invalid host timeout: %w", err)
 	}
 
-	// 设置代理
-	if cfg.Connection.Proxy != "" {
-		proxyURL, err := url.Parse(cfg.Connection.Proxy)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	// 创建HTTP客户端，超时由每个请求按主机设置（--host-timeout可以超过全局超时）
+	client := &http.Client{}
+
+	client.CheckRedirect = redirectPolicy(cfg.Request)
+
+	// 设置代理，启用Tor时使用Tor的SOCKS5代理
+	proxy := cfg.Connection.Proxy
+	if cfg.Connection.Tor {
+		if proxy, err = torProxy(cfg.Connection.TorAddress); err != nil {
+			return nil, err
+		}
+	}
+	if proxy != "" || cfg.Connection.ProxyRules != "" {
+		var proxyURL *url.URL
+		if proxy != "" {
+			if proxyURL, err = url.Parse(proxy); err != nil {
+				return nil, fmt.Errorf("invalid proxy URL: %w", config.URLParseError(err))
+			}
+			proxyURL = applyProxyAuth(proxyURL, cfg.Connection.ProxyAuth)
+		}
+		proxyFunc := http.ProxyURL(proxyURL)
+		if cfg.Connection.ProxyRules != "" {
+			rules, err := config.ParseProxyRules(cfg.Connection.ProxyRules)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy rules: %w", err)
+			}
+			for i := range rules {
+				rules[i].Proxy = applyProxyAuth(rules[i].Proxy, cfg.Connection.ProxyAuth)
+			}
+			proxyFunc = proxyRulesFunc(rules, proxyURL)
 		}
 		client.Transport = &http.Transport{
-			Proxy: http.ProxyURL(proxyURL),
+			Proxy: proxyFunc,
 		}
 	}
 
+	// 每个请求使用新的连接，部分WAF对复用连接和新连接的处理不同
+	if cfg.Connection.DisableKeepAlive {
+		ensureTransport(client).DisableKeepAlives = true
+	}
+
+	// 从指定的本地地址或网络接口发起连接，同时指定时--ip优先
+	switch {
+	case cfg.Connection.IP != "":
+		if cfg.Connection.Interface != "" {
+			log.Printf("Warning: Both --ip and --interface are set, using --ip %s", cfg.Connection.IP)
+		}
+		localAddr, err := sourceAddr(cfg.Connection.IP)
+		if err != nil {
+			return nil, err
+		}
+		dialer := newSourceDialer()
+		dialer.LocalAddr = localAddr
+		ensureTransport(client).DialContext = dialer.DialContext
+	case cfg.Connection.Interface != "":
+		dialer, err := newInterfaceDialer(cfg.Connection.Interface)
+		if err != nil {
+			return nil, err
+		}
+		ensureTransport(client).DialContext = dialer.DialContext
+	}
+
+	// 目标要求双向TLS时发送客户端证书
+	if cfg.Connection.CertFile != "" || cfg.Connection.KeyFile != "" {
+		cert, err := loadClientCertificate(cfg.Connection.CertFile, cfg.Connection.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		transport := ensureTransport(client)
+		tlsConfig := &tls.Config{}
+		if transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	// 配置了代理列表时每个请求轮流使用其中一个代理，--proxy也加入轮换
+	proxies, err := loadProxyList(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var pool *proxyPool
+	if len(proxies) > 0 {
+		if cfg.Connection.ProxyRules != "" {
+			return nil, fmt.Errorf("--proxies-file cannot be used together with --proxy-rules")
+		}
+		// 轮换时大部分请求会经其他代理发出而不经过Tor
+		if cfg.Connection.Tor {
+			return nil, fmt.Errorf("--tor cannot be used together with --proxies-file or a proxy list")
+		}
+		if proxy != "" {
+			proxies = append([]string{proxy}, proxies...)
+		}
+		if pool, err = newProxyPool(proxies, cfg.Connection.ProxyAuth, client); err != nil {
+			return nil, err
+		}
+		log.Printf("Loaded %d proxies, rotating per request", pool.Size())
+	}
+
+	// 重放客户端沿用扫描请求的TLS配置，需在--http10替换传输层之前取出
+	var clientTLSConfig *tls.Config
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		clientTLSConfig = transport.TLSClientConfig
+	}
+
+	// --http10以HTTP/1.0发送请求，需要自行建立连接，不支持经代理发送
+	if cfg.Connection.HTTP10 {
+		if proxy != "" || cfg.Connection.ProxyRules != "" || pool != nil {
+			return nil, fmt.Errorf("--http10 cannot be used together with proxies")
+		}
+		client.Transport = newHTTP10Transport(ensureTransport(client))
+	}
+
 	// 设置请求头
 	headers := make(map[string]string)
 	if cfg.Request.UserAgent != "" {
 		headers["User-Agent"] = cfg.Request.UserAgent
 	} else {
-		headers["User-Agent"] = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+		headers["User-Agent"] = DefaultUserAgent
 	}
 
 	// 设置正常的浏览器请求头
@@ -72,94 +199,184 @@ func NewRequester(cfg *config.Config) (*Requester, error) {
 	headers["Accept-Language"] = "en-US,en;q=0.9"
 	headers["Accept-Encoding"] = "gzip, deflate, br"
 	headers["Connection"] = "keep-alive"
+	if cfg.Connection.DisableKeepAlive {
+		headers["Connection"] = "close"
+	}
 	headers["Upgrade-Insecure-Requests"] = "1"
 
-	// 添加自定义请求头
-	for _, header := range cfg.Request.Headers {
-		if strings.Contains(header, ":") {
-			parts := strings.SplitN(header, ":", 2)
-			if len(parts) == 2 {
-				headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-			}
+	// 添加--headers-file中的请求头，命令行的--header在后面添加，同名时覆盖文件中的值
+	if cfg.Request.HeadersFile != "" {
+		fileHeaders, err := readHeadersFile(cfg.Request.HeadersFile)
+		if err != nil {
+			return nil, err
 		}
+		addHeaderLines(headers, fileHeaders)
 	}
 
+	// 添加自定义请求头
+	addHeaderLines(headers, cfg.Request.Headers)
+
 	// 设置Cookie
 	if cfg.Request.Cookie != "" {
 		headers["Cookie"] = cfg.Request.Cookie
 	}
 
-	return &Requester{
-		client:      client,
-		config:      cfg,
-		headers:     headers,
-		HostManager: NewHostManager(cfg),
-	}, nil
-}
+	// 构建请求体，未自定义Content-Type时自动设置
+	body, contentType, err := loadRequestBody(cfg.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request body: %w", err)
+	}
+	if contentType != "" && !hasHeader(headers, "Content-Type") {
+		headers["Content-Type"] = contentType
+	}
 
-// Request 发送HTTP请求
-func (r *Requester) Request(targetURL string) (*Response, error) {
-	defer func() {
-		if r := recover(); r != nil {
-			log.Printf("Request panic recovered: %v\nStack trace: %s", r, debug.Stack())
-		}
-	}()
+	// 加载按主机配置的认证凭据
+	authMap, err := loadAuthMap(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// 解析URL获取主机名
-	parsedURL, err := url.Parse(targetURL)
+	// 创建重放客户端
+	replayClient, err := newReplayClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
+		return nil, err
+	}
+	// 重放请求与扫描请求使用相同的客户端证书，需要双向TLS的目标也能在代理中重放
+	if replayClient != nil && clientTLSConfig != nil {
+		transport := replayClient.Transport.(*http.Transport)
+		tlsConfig := clientTLSConfig.Clone()
+		tlsConfig.InsecureSkipVerify = transport.TLSClientConfig.InsecureSkipVerify
+		transport.TLSClientConfig = tlsConfig
+	}
+	replayHeaders := make(map[string]string)
+	addHeaderLines(replayHeaders, cfg.Connection.ReplayHeaders)
+
+	requester := &Requester{
+		client:        client,
+		config:        cfg,
+		headers:       headers,
+		body:          body,
+		HostManager:   NewHostManager(cfg),
+		replayClient:  replayClient,
+		replayHeaders: replayHeaders,
+		authMap:       authMap,
+		retryStatus:   retryStatusCodes(cfg.Connection.RetryOnStatus),
+		proxyPool:     pool,
 	}
 
-	// 获取主机信息（包含ping延迟，自动进行ping验证）
-	r.HostManager.GetOrCreateHostInfo(parsedURL.Host)
+	// 所有工作协程共享同一个带宽限制器
+	if cfg.Connection.MaxBandwidth != "" {
+		bytesPerSecond, err := config.ParseByteSize(cfg.Connection.MaxBandwidth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max bandwidth: %w", err)
+		}
+		requester.bandwidth = NewBandwidthLimiter(bytesPerSecond)
+	}
 
-	// 创建请求
-	var req *http.Request
-	method := strings.ToUpper(r.config.Request.HTTPMethod)
+	// 执行预请求以获取令牌
+	if err := requester.runPreflight(); err != nil {
+		return nil, fmt.Errorf("preflight failed: %w", err)
+	}
 
-	if method == "POST" || method == "PUT" || method == "PATCH" {
-		var body io.Reader
-		if r.config.Request.Data != "" {
-			body = strings.NewReader(r.config.Request.Data)
+	return requester, nil
+}
+
+// Transport 返回扫描请求使用的传输层，包含代理、源地址和客户端证书等设置
+func (r *Requester) Transport() http.RoundTripper {
+	return r.client.Transport
+}
+
+// ensureTransport 返回客户端的Transport，未设置时基于默认Transport创建
+func ensureTransport(client *http.Client) *http.Transport {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		client.Transport = transport
+	}
+	return transport
+}
+
+// addHeaderLines 解析"Name: value"格式的请求头并加入headers
+func addHeaderLines(headers map[string]string, lines []string) {
+	for _, header := range lines {
+		if name, value, ok := strings.Cut(header, ":"); ok {
+			name = strings.TrimSpace(name)
+			// 请求头名称不区分大小写，覆盖已有的同名请求头
+			for key := range headers {
+				if strings.EqualFold(key, name) {
+					delete(headers, key)
+				}
+			}
+			headers[name] = strings.TrimSpace(value)
 		}
-		req, err = http.NewRequest(method, targetURL, body)
-	} else {
-		req, err = http.NewRequest(method, targetURL, nil)
 	}
+}
 
+// readHeadersFile 读取--headers-file，每行一个"Name: Value"，忽略空行和#注释，格式错误的行返回错误
+func readHeadersFile(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to read headers file: %w", err)
 	}
 
-	// 设置请求头
-	for key, value := range r.headers {
-		req.Header.Set(key, value)
+	var lines []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, _, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" || strings.ContainsAny(strings.TrimSpace(name), " \t") {
+			return nil, fmt.Errorf("invalid header on line %d of %s: %q (expected \"Name: Value\")", i+1, filename, line)
+		}
+		lines = append(lines, line)
 	}
+	return lines, nil
+}
 
-	// 设置认证
-	if r.config.Request.Auth != "" {
-		if r.config.Request.AuthType == "basic" {
-			req.SetBasicAuth("", r.config.Request.Auth)
-		} else if r.config.Request.AuthType == "bearer" {
-			req.Header.Set("Authorization", "Bearer "+r.config.Request.Auth)
+// hasHeader 检查请求头是否已设置（不区分大小写）
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
 		}
 	}
+	return false
+}
 
-	// 设置智能超时
-	timeout := r.HostManager.GetTimeout(parsedURL.Host)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	req = req.WithContext(ctx)
+// Request 使用配置的方法发送HTTP请求
+func (r *Requester) Request(targetURL string) (*Response, error) {
+	return r.RequestMethod(targetURL, "")
+}
+
+// RequestMethod 使用指定方法发送HTTP请求，method为空时使用配置的方法
+func (r *Requester) RequestMethod(targetURL, method string) (_ *Response, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Request panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
+		}
+	}()
+
+	// 解析URL获取主机名
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	// 获取主机信息（包含ping延迟，自动进行ping验证），按host:port区分
+	hostKey := HostKey(parsedURL)
+	r.HostManager.GetOrCreateHostInfo(hostKey)
 
 	// 记录请求开始时间
 	startTime := time.Now()
 
-	// 发送请求
-	resp, err := r.client.Do(req)
+	// 发送请求，失败时按--retries重试
+	resp, ctx, cancel, err := r.send(targetURL, method, hostKey)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
+	defer cancel()
 	defer func() {
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
@@ -169,29 +386,39 @@ func (r *Requester) Request(targetURL string) (*Response, error) {
 	// 计算响应时间
 	responseTime := time.Since(startTime)
 
+	// 记录主机指纹（每个主机只记录首个响应）
+	if r.config.General.Fingerprint {
+		r.HostManager.RecordFingerprint(hostKey, resp.Header, resp.TLS)
+	}
+
 	// 判断是否为慢响应
-	isSlowResponse := r.HostManager.IsSlowResponse(parsedURL.Host, responseTime)
+	isSlowResponse := r.HostManager.IsSlowResponse(hostKey, responseTime)
 
 	// 读取响应体（根据响应速度决定是否完整读取）
+	var body io.Reader = resp.Body
+	if r.bandwidth != nil {
+		body = r.bandwidth.Reader(ctx, resp.Body)
+	}
 	var bodyBytes []byte
 	if isSlowResponse {
-		// 慢响应：只读取前1KB用于状态码判断
+		// 慢响应：只读取前1KB用于状态码判断，--max-bandwidth只按实际读取的字节限速
 		bodyBytes = make([]byte, 1024)
-		n, _ := io.ReadAtLeast(resp.Body, bodyBytes, 1)
+		n, _ := io.ReadAtLeast(body, bodyBytes, 1)
 		bodyBytes = bodyBytes[:n]
 	} else {
 		// 正常响应：完整读取
-		bodyBytes, err = io.ReadAll(resp.Body)
+		bodyBytes, err = io.ReadAll(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read response body: %w", err)
 		}
 	}
 
 	// 处理重定向
-	var redirect string
+	var redirect, rawRedirect string
 	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
 		if location := resp.Header.Get("Location"); location != "" {
-			redirect = location
+			rawRedirect = location
+			redirect = resolveRedirect(resp.Request, location)
 		}
 	}
 
@@ -200,10 +427,185 @@ func (r *Requester) Request(targetURL string) (*Response, error) {
 		ContentLength: int64(len(bodyBytes)),
 		Body:          string(bodyBytes),
 		Redirect:      redirect,
+		RawRedirect:   rawRedirect,
 		Headers:       resp.Header,
+		ResponseTime:  time.Since(startTime),
 	}, nil
 }
 
+// send 发送请求，网络错误和5xx/429响应最多重试--retries次，重试间隔指数退避。
+// 返回最后一次请求的响应及其上下文，调用方读取完响应体后需要调用cancel
+func (r *Requester) send(targetURL, method, hostKey string) (*http.Response, context.Context, context.CancelFunc, error) {
+	maxAttempts := r.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		// 每次请求重新创建，以便重新发送请求体
+		req, err := r.newRequest(targetURL, method)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		// 超过--max-rate时等待，扫描停止时立即返回
+		if r.limiter != nil {
+			if err := r.limiter.Wait(r.scanContext()); err != nil {
+				return nil, nil, nil, fmt.Errorf("request canceled: %w", err)
+			}
+		}
+
+		// 设置智能超时
+		timeout := r.HostManager.GetTimeout(hostKey)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		client := r.client
+		var proxy *pooledProxy
+		if r.proxyPool != nil {
+			proxy = r.proxyPool.pick()
+			client = proxy.client
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if proxy != nil {
+			r.proxyPool.record(proxy, err != nil && isNetworkError(err))
+		}
+
+		if attempt >= maxAttempts || !isRetryable(resp, err, r.retryStatus) || r.scanContext().Err() != nil {
+			if err != nil {
+				cancel()
+				if attempt > 1 {
+					log.Printf("Request to %s failed after %d attempts: %v", targetURL, attempt, err)
+					return nil, nil, nil, fmt.Errorf("request failed after %d attempts: %w", attempt, err)
+				}
+				return nil, nil, nil, fmt.Errorf("request failed: %w", err)
+			}
+			if attempt > 1 {
+				log.Printf("Request to %s returned %d after %d attempts", targetURL, resp.StatusCode, attempt)
+			}
+			return resp, ctx, cancel, nil
+		}
+
+		// 丢弃本次响应后等待重试（Retry-After在关闭响应前读取）
+		delay := retryDelay(resp, attempt)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+		cancel()
+
+		log.Printf("Retrying %s in %v (attempt %d/%d): %s", targetURL, delay.Round(time.Millisecond), attempt+1, maxAttempts, reason)
+		if !sleepContext(r.scanContext(), delay) {
+			return nil, nil, nil, fmt.Errorf("request canceled after %d attempts: %w", attempt, r.scanContext().Err())
+		}
+	}
+}
+
+// DumpRequest 按与Request相同的方式构建请求，返回序列化后的原始请求而不发送
+func (r *Requester) DumpRequest(targetURL string) ([]byte, error) {
+	req, err := r.newRequest(targetURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump request: %w", err)
+	}
+	return dump, nil
+}
+
+// maxRedirects 跟随重定向的最大次数，与http.Client默认值一致
+const maxRedirects = 10
+
+// redirectPolicy 按--follow-redirects和--stay-in-scope返回客户端的重定向策略。
+// 默认不跟随重定向，返回3xx响应本身并记录重定向地址，与--follow-redirects默认关闭的含义一致
+func redirectPolicy(cfg config.RequestConfig) func(req *http.Request, via []*http.Request) error {
+	switch {
+	case !cfg.FollowRedirects:
+		return func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	case cfg.StayInScope:
+		return stayInScopeRedirect
+	default:
+		return nil
+	}
+}
+
+// stayInScopeRedirect 只跟随同一主机内的重定向，重定向到其他主机时返回3xx响应本身，以便记录重定向地址
+func stayInScopeRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if !strings.EqualFold(req.URL.Hostname(), via[0].URL.Hostname()) {
+		return http.ErrUseLastResponse
+	}
+	return nil
+}
+
+// resolveRedirect 将Location按请求URL解析为绝对地址，无法解析时返回原值
+func resolveRedirect(req *http.Request, location string) string {
+	if req == nil || req.URL == nil {
+		return location
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	return req.URL.ResolveReference(ref).String()
+}
+
+// newRequest 按配置的请求头、请求体和认证创建请求，method为空时使用配置的方法
+func (r *Requester) newRequest(targetURL, method string) (*http.Request, error) {
+	var req *http.Request
+	var err error
+	if method == "" {
+		method = r.config.Request.HTTPMethod
+	}
+	method = strings.ToUpper(method)
+
+	hasBody := method == "POST" || method == "PUT" || method == "PATCH"
+	if hasBody {
+		var body io.Reader
+		if len(r.body) > 0 {
+			body = bytes.NewReader(r.body)
+		}
+		req, err = http.NewRequest(method, targetURL, body)
+	} else {
+		req, err = http.NewRequest(method, targetURL, nil)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// 设置请求头（无请求体时不发送Content-Type）
+	for key, value := range r.headers {
+		if !hasBody && strings.EqualFold(key, "Content-Type") {
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	// --random-agent每个请求使用随机的User-Agent
+	if r.config.General.RandomUserAgents {
+		req.Header.Set("User-Agent", randomUserAgent())
+	}
+
+	// 设置认证，按主机配置的凭据优先于全局凭据
+	if credential, ok := r.authForHost(req.URL); ok {
+		setHostAuth(req, r.config.Request.AuthType, credential)
+	} else if r.config.Request.Auth != "" {
+		setAuth(req, r.config.Request.AuthType, r.config.Request.Auth)
+	}
+
+	return req, nil
+}
+
+// SetRateLimiter 设置--max-rate请求速率限制器，与扫描器共享，使重试、确认和基线等所有请求都受同一速率限制
+func (r *Requester) SetRateLimiter(limiter *rate.Limiter) {
+	r.limiter = limiter
+}
+
 // SetHeaders 设置请求头
 func (r *Requester) SetHeaders(headers map[string]string) {
 	defer func() {
@@ -0,0 +1,651 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+// newTestConfig 创建请求器测试使用的基础配置
+func newTestConfig() *config.Config {
+	return &config.Config{
+		Request: config.RequestConfig{
+			HTTPMethod: "GET",
+		},
+		Connection: config.ConnectionConfig{
+			Timeout: 5,
+		},
+	}
+}
+
+// captured 记录服务端收到的请求
+type captured struct {
+	method        string
+	contentType   string
+	contentLength int64
+	body          string
+	form          map[string][]string
+	files         map[string]string
+}
+
+// newCaptureServer 创建记录请求内容的测试服务器
+func newCaptureServer(t *testing.T, got *captured) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.method = r.Method
+		got.contentType = r.Header.Get("Content-Type")
+		got.contentLength = r.ContentLength
+		if strings.HasPrefix(got.contentType, "multipart/form-data") {
+			if err := r.ParseMultipartForm(1 << 20); err == nil {
+				got.form = r.MultipartForm.Value
+				got.files = make(map[string]string)
+				for name, headers := range r.MultipartForm.File {
+					f, _ := headers[0].Open()
+					content, _ := io.ReadAll(f)
+					f.Close()
+					got.files[name] = string(content)
+				}
+			}
+		} else {
+			body, _ := io.ReadAll(r.Body)
+			got.body = string(body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRequestFormURLEncodedBody(t *testing.T) {
+	var got captured
+	server := newCaptureServer(t, &got)
+
+	cfg := newTestConfig()
+	cfg.Request.HTTPMethod = "POST"
+	cfg.Request.Data = "a=1&b=2"
+
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if _, err := requester.Request(server.URL + "/login"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if got.contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want application/x-www-form-urlencoded", got.contentType)
+	}
+	if got.body != "a=1&b=2" {
+		t.Errorf("body = %q, want %q", got.body, "a=1&b=2")
+	}
+}
+
+func TestRequestMultipartBody(t *testing.T) {
+	var got captured
+	server := newCaptureServer(t, &got)
+
+	uploadFile := filepath.Join(t.TempDir(), "shell.txt")
+	if err := os.WriteFile(uploadFile, []byte("file-content"), 0644); err != nil {
+		t.Fatalf("Failed to write upload file: %v", err)
+	}
+
+	cfg := newTestConfig()
+	cfg.Request.HTTPMethod = "POST"
+	cfg.Request.Data = "a=1&b=hello%20world&upload=@" + uploadFile
+	cfg.Request.Multipart = true
+
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if _, err := requester.Request(server.URL + "/upload"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if got.form == nil {
+		t.Fatalf("Expected multipart form, Content-Type = %q", got.contentType)
+	}
+	if v := got.form["a"]; len(v) != 1 || v[0] != "1" {
+		t.Errorf("field a = %v, want [1]", v)
+	}
+	if v := got.form["b"]; len(v) != 1 || v[0] != "hello world" {
+		t.Errorf("field b = %v, want [hello world]", v)
+	}
+	if got.files["upload"] != "file-content" {
+		t.Errorf("file upload = %q, want %q", got.files["upload"], "file-content")
+	}
+}
+
+func TestRequestCustomContentTypeKept(t *testing.T) {
+	var got captured
+	server := newCaptureServer(t, &got)
+
+	cfg := newTestConfig()
+	cfg.Request.HTTPMethod = "POST"
+	cfg.Request.Data = "a=1"
+	cfg.Request.Headers = []string{"content-type: text/custom"}
+
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if _, err := requester.Request(server.URL + "/"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if got.contentType != "text/custom" {
+		t.Errorf("Content-Type = %q, want text/custom", got.contentType)
+	}
+}
+
+func TestPreflightTokenInjected(t *testing.T) {
+	var scanToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			w.Write([]byte(`<form><input type="hidden" name="csrf" value="tok-123"></form>`))
+			return
+		}
+		scanToken = r.Header.Get("X-CSRF-Token")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Request.PreflightURL = server.URL + "/login"
+	cfg.Request.ExtractTokenRegex = `name="csrf" value="([^"]+)"`
+
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if _, err := requester.Request(server.URL + "/admin"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	if scanToken != "tok-123" {
+		t.Errorf("X-CSRF-Token = %q, want %q", scanToken, "tok-123")
+	}
+}
+
+func TestPreflightTokenMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("no token here"))
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Request.PreflightURL = server.URL + "/login"
+	cfg.Request.ExtractTokenRegex = `csrf=(\w+)`
+
+	if _, err := NewRequester(cfg); err == nil {
+		t.Error("Expected error when token is not found in preflight response")
+	}
+}
+
+func TestExtractTokenHeaderOrder(t *testing.T) {
+	tokenRegex := regexp.MustCompile(`token=(\w+)`)
+	headers := http.Header{}
+	headers.Set("X-Token", "token=second")
+	headers.Set("Set-Cookie", "token=first")
+
+	// 多个响应头匹配时按名称顺序取第一个，不受map遍历顺序影响
+	for i := 0; i < 20; i++ {
+		if got := extractToken(tokenRegex, headers, "token=body"); got != "first" {
+			t.Fatalf("extractToken = %q, want %q from Set-Cookie", got, "first")
+		}
+	}
+}
+
+func TestRequestResolvesRelativeRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/":
+			w.Header().Set("Location", "/login?next=admin")
+			w.WriteHeader(http.StatusFound)
+		case "/admin/panel":
+			w.Header().Set("Location", "settings")
+			w.WriteHeader(http.StatusMovedPermanently)
+		case "/external":
+			w.Header().Set("Location", "https://sso.example.com/auth")
+			w.WriteHeader(http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	requester, err := NewRequester(newTestConfig())
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	tests := []struct {
+		path        string
+		redirect    string
+		rawRedirect string
+	}{
+		{path: "/admin/", redirect: server.URL + "/login?next=admin", rawRedirect: "/login?next=admin"},
+		{path: "/admin/panel", redirect: server.URL + "/admin/settings", rawRedirect: "settings"},
+		{path: "/external", redirect: "https://sso.example.com/auth", rawRedirect: "https://sso.example.com/auth"},
+		{path: "/plain", redirect: "", rawRedirect: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := requester.Request(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if resp.Redirect != tt.redirect {
+				t.Errorf("Redirect = %q, want %q", resp.Redirect, tt.redirect)
+			}
+			if resp.RawRedirect != tt.rawRedirect {
+				t.Errorf("RawRedirect = %q, want %q", resp.RawRedirect, tt.rawRedirect)
+			}
+		})
+	}
+}
+
+func TestMaxBandwidth(t *testing.T) {
+	const bodySize = 24 << 10
+	const limit = 64 << 10
+	limiter := NewBandwidthLimiter(limit)
+
+	// 两个并发读取共享同一带宽限制（直接测试限速Reader，不受慢响应只读取1KB的影响）
+	const readers = 2
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := io.ReadAll(limiter.Reader(context.Background(), strings.NewReader(strings.Repeat("x", bodySize))))
+			if err == nil && len(data) != bodySize {
+				err = fmt.Errorf("read %d bytes, want %d", len(data), bodySize)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+	}
+
+	elapsed := time.Since(start)
+	throughput := float64(readers*bodySize) / elapsed.Seconds()
+	if throughput > limit*1.05 {
+		t.Errorf("Throughput %.0f B/s (%d bytes in %v) exceeds --max-bandwidth %d B/s", throughput, readers*bodySize, elapsed, limit)
+	}
+}
+
+func TestMaxBandwidthSlowResponse(t *testing.T) {
+	const bodySize = 24 << 10
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("x", bodySize))
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.MaxBandwidth = "4kb"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	// 极小的ping延迟使每个响应都被判断为慢响应
+	parsed, _ := url.Parse(server.URL)
+	requester.HostManager.GetOrCreateHostInfo(HostKey(parsed)).SmartDelay.pingDelay = time.Nanosecond
+
+	// 慢响应只读取前1KB，带宽限制只按实际读取的字节等待（完整读取24KB需要约6秒）
+	start := time.Now()
+	resp, err := requester.Request(server.URL + "/large")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if resp.ContentLength != 1024 {
+		t.Errorf("Read %d bytes, want 1024 for a slow response", resp.ContentLength)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Slow response took %v, want only the first 1KB throttled", elapsed)
+	}
+}
+
+func TestDisableKeepAlive(t *testing.T) {
+	tests := []struct {
+		name             string
+		disableKeepAlive bool
+		wantConnections  int
+	}{
+		{name: "默认复用连接", disableKeepAlive: false, wantConnections: 0},
+		{name: "禁用keep-alive", disableKeepAlive: true, wantConnections: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			connections := 0
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "ok")
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					mu.Lock()
+					connections++
+					mu.Unlock()
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			cfg := newTestConfig()
+			cfg.Connection.DisableKeepAlive = tt.disableKeepAlive
+			requester, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			transport, _ := requester.client.Transport.(*http.Transport)
+			if tt.disableKeepAlive && (transport == nil || !transport.DisableKeepAlives) {
+				t.Fatalf("Transport does not disable keep-alives")
+			}
+
+			// 首个请求建立连接（主机检测也会建立一次连接），之后只统计新建的连接
+			request := func() {
+				if _, err := requester.Request(server.URL + "/"); err != nil {
+					t.Fatalf("Request failed: %v", err)
+				}
+			}
+			request()
+			mu.Lock()
+			connections = 0
+			mu.Unlock()
+			for i := 0; i < 3; i++ {
+				request()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if connections != tt.wantConnections {
+				t.Errorf("Server saw %d new connections for 3 requests, want %d", connections, tt.wantConnections)
+			}
+		})
+	}
+}
+
+func TestFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		followRedirects bool
+		wantStatus      int
+		wantRedirect    string
+	}{
+		{name: "默认报告3xx响应", wantStatus: http.StatusFound, wantRedirect: server.URL + "/login"},
+		{name: "启用后跟随重定向", followRedirects: true, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig()
+			cfg.Request.FollowRedirects = tt.followRedirects
+			requester, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			resp, err := requester.Request(server.URL + "/admin")
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus || resp.Redirect != tt.wantRedirect {
+				t.Errorf("Response = %d %q, want %d %q", resp.StatusCode, resp.Redirect, tt.wantStatus, tt.wantRedirect)
+			}
+		})
+	}
+}
+
+func TestStayInScopeRedirect(t *testing.T) {
+	var mu sync.Mutex
+	externalHits := 0
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		externalHits++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer external.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/out":
+			http.Redirect(w, r, external.URL+"/landing", http.StatusFound)
+		case "/in":
+			http.Redirect(w, r, "/landing", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer target.Close()
+	// 通过localhost访问目标，使外部服务器（127.0.0.1）成为不同的主机
+	targetURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+
+	tests := []struct {
+		name        string
+		stayInScope bool
+		path        string
+		wantStatus  int
+		wantHits    int
+	}{
+		{name: "不跟随到外部主机", stayInScope: true, path: "/out", wantStatus: http.StatusFound, wantHits: 0},
+		{name: "跟随同主机重定向", stayInScope: true, path: "/in", wantStatus: http.StatusOK, wantHits: 0},
+		{name: "未启用时跟随外部重定向", stayInScope: false, path: "/out", wantStatus: http.StatusOK, wantHits: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			externalHits = 0
+			mu.Unlock()
+
+			cfg := newTestConfig()
+			cfg.Request.FollowRedirects = true
+			cfg.Request.StayInScope = tt.stayInScope
+			requester, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			resp, err := requester.Request(targetURL + tt.path)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusFound && resp.Redirect != external.URL+"/landing" {
+				t.Errorf("Redirect = %q, want the external location recorded", resp.Redirect)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if externalHits != tt.wantHits {
+				t.Errorf("External host requested %d times, want %d", externalHits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestRequestDataFileBody(t *testing.T) {
+	dir := t.TempDir()
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0xfe, '\n'}
+	binaryFile := filepath.Join(dir, "body.bin")
+	jsonFile := filepath.Join(dir, "body.json")
+	if err := os.WriteFile(binaryFile, binary, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.WriteFile(jsonFile, []byte(`{"user":"admin"}`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		data        string
+		dataFile    string
+		wantBody    string
+		contentType string
+	}{
+		{name: "二进制文件", dataFile: binaryFile, wantBody: string(binary), contentType: "application/octet-stream"},
+		{name: "JSON文件", dataFile: jsonFile, wantBody: `{"user":"admin"}`, contentType: "application/json"},
+		{name: "同时指定时data优先", data: "a=1", dataFile: binaryFile, wantBody: "a=1", contentType: "application/x-www-form-urlencoded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got captured
+			server := newCaptureServer(t, &got)
+
+			cfg := newTestConfig()
+			cfg.Request.HTTPMethod = "PUT"
+			cfg.Request.Data = tt.data
+			cfg.Request.DataFile = tt.dataFile
+			requester, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+			if _, err := requester.Request(server.URL + "/upload"); err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+
+			if got.body != tt.wantBody {
+				t.Errorf("body = %q, want %q", got.body, tt.wantBody)
+			}
+			if got.contentLength != int64(len(tt.wantBody)) {
+				t.Errorf("Content-Length = %d, want %d", got.contentLength, len(tt.wantBody))
+			}
+			if got.contentType != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got.contentType, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestRequestDataFileMissing(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Request.DataFile = filepath.Join(t.TempDir(), "missing.bin")
+	if _, err := NewRequester(cfg); err == nil {
+		t.Error("NewRequester succeeded with a missing data file, want error")
+	}
+}
+
+func TestHeadersFile(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	headersFile := filepath.Join(t.TempDir(), "headers.txt")
+	content := "# shared headers\nX-Team: red\n\nX-Token: from-file\nAccept-Language: de-DE\n"
+	if err := os.WriteFile(headersFile, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	cfg := newTestConfig()
+	cfg.Request.HeadersFile = headersFile
+	cfg.Request.Headers = []string{"x-token: from-flag"}
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if _, err := requester.Request(server.URL + "/"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"X-Team":          "red",
+		"X-Token":         "from-flag", // --header覆盖文件中的同名请求头
+		"Accept-Language": "de-DE",     // 文件中的请求头覆盖默认请求头
+	}
+	for name, want := range tests {
+		if got := received.Values(name); len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %q, want [%q]", name, got, want)
+		}
+	}
+}
+
+func TestHeadersFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "缺少冒号", content: "X-Team red\n"},
+		{name: "空名称", content: ": value\n"},
+		{name: "名称包含空格", content: "X Team: red\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headersFile := filepath.Join(t.TempDir(), "headers.txt")
+			if err := os.WriteFile(headersFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+			cfg := newTestConfig()
+			cfg.Request.HeadersFile = headersFile
+			if _, err := NewRequester(cfg); err == nil {
+				t.Error("NewRequester succeeded with a malformed headers file, want error")
+			}
+		})
+	}
+
+	cfg := newTestConfig()
+	cfg.Request.HeadersFile = filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := NewRequester(cfg); err == nil {
+		t.Error("NewRequester succeeded with a missing headers file, want error")
+	}
+}
+
+func TestRequestHostTimeoutOverrideExceedsGlobalTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 全局超时1秒，慢主机覆盖为5秒
+	cfg := newTestConfig()
+	cfg.Connection.Timeout = 1
+	cfg.Connection.HostTimeout = "127.0.0.1=5"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester() failed: %v", err)
+	}
+
+	resp, err := requester.Request(server.URL + "/slow")
+	if err != nil {
+		t.Fatalf("Request() failed within host timeout override: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,147 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+// retryBaseDelay 第一次重试前的等待时间，之后每次翻倍
+var retryBaseDelay = 200 * time.Millisecond
+
+// retryMaxDelay 重试等待时间上限
+const retryMaxDelay = 5 * time.Second
+
+// retryJitterRatio 重试等待时间的随机抖动比例，实际等待时间在退避时间的±25%内
+const retryJitterRatio = 0.25
+
+// retryRand 重试抖动使用的随机数生成器，所有工作协程共享，测试中可替换为固定种子
+var (
+	retryRandMu sync.Mutex
+	retryRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// retryAfterMaxDelay 按Retry-After响应头等待的上限，避免服务器要求等待过久时卡住工作协程
+const retryAfterMaxDelay = time.Minute
+
+// SetContext 设置扫描上下文，上下文取消后不再重试失败的请求
+func (r *Requester) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// scanContext 返回扫描上下文，未设置时使用context.Background
+func (r *Requester) scanContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// maxAttempts 每个请求最多发送的次数：首次请求加上--retries次重试
+func (r *Requester) maxAttempts() int {
+	return max(r.config.Connection.MaxRetries, 0) + 1
+}
+
+// retryStatusCodes 解析--retry-on-status，未配置时返回nil（使用默认的5xx和429）
+func retryStatusCodes(spec string) map[int]bool {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+	codes, err := config.ParseStatusCodes(spec)
+	if err != nil {
+		return nil
+	}
+	statuses := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		statuses[code] = true
+	}
+	return statuses
+}
+
+// isRetryable 判断请求是否值得重试：网络错误（连接重置、超时、DNS解析失败等）以及需要重试的状态码。
+// statuses为--retry-on-status指定的状态码，为nil时重试5xx和429，4xx不重试
+func isRetryable(resp *http.Response, err error, statuses map[int]bool) bool {
+	if err != nil {
+		return isNetworkError(err)
+	}
+	if statuses != nil {
+		return statuses[resp.StatusCode]
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// isNetworkError 判断是否为网络错误（连接重置、超时、DNS解析失败等）。
+// url.Error本身实现了net.Error，需要检查其包装的底层错误，证书错误、重定向次数过多等不算网络错误
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryDelay 重试前的等待时间：响应带有Retry-After时按其等待，否则使用指数退避
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return min(delay, retryAfterMaxDelay)
+		}
+	}
+	return retryBackoff(attempt)
+}
+
+// parseRetryAfter 解析Retry-After响应头，支持秒数和HTTP日期两种格式
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryBackoff 第attempt次请求失败后的等待时间：base * 2^(attempt-1) ± 25%的随机抖动，不超过retryMaxDelay。
+// 抖动使同时失败的工作协程错开重试时间，避免一起冲击刚恢复的主机
+func retryBackoff(attempt int) time.Duration {
+	delay := retryBaseDelay << min(attempt-1, 10)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	retryRandMu.Lock()
+	jitter := (retryRand.Float64()*2 - 1) * retryJitterRatio
+	retryRandMu.Unlock()
+
+	return min(delay+time.Duration(float64(delay)*jitter), retryMaxDelay)
+}
+
+// sleepContext 等待d，ctx取消时提前返回false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
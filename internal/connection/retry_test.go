@@ -0,0 +1,286 @@
+package connection
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRequestRetries(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	tests := []struct {
+		name         string
+		retries      int
+		failures     int // 返回失败状态码的次数
+		failStatus   int
+		wantStatus   int
+		wantAttempts int32
+	}{
+		{name: "5xx重试后成功", retries: 2, failures: 2, failStatus: http.StatusServiceUnavailable, wantStatus: http.StatusOK, wantAttempts: 3},
+		{name: "429重试", retries: 1, failures: 1, failStatus: http.StatusTooManyRequests, wantStatus: http.StatusOK, wantAttempts: 2},
+		{name: "重试次数用尽返回最后的响应", retries: 1, failures: 5, failStatus: http.StatusBadGateway, wantStatus: http.StatusBadGateway, wantAttempts: 2},
+		{name: "4xx不重试", retries: 3, failures: 5, failStatus: http.StatusForbidden, wantStatus: http.StatusForbidden, wantAttempts: 1},
+		{name: "未配置重试", retries: 0, failures: 1, failStatus: http.StatusInternalServerError, wantStatus: http.StatusInternalServerError, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(atomic.AddInt32(&attempts, 1)) <= tt.failures {
+					w.WriteHeader(tt.failStatus)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			cfg := newTestConfig()
+			cfg.Connection.MaxRetries = tt.retries
+			r, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			resp, err := r.Request(server.URL + "/admin")
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("Got %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRequestRetryNetworkError(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	// 服务器关闭后连接被拒绝，所有重试都失败
+	server := httptest.NewServer(http.NotFoundHandler())
+	serverURL := server.URL
+	server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.MaxRetries = 2
+	r, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	_, err = r.Request(serverURL + "/admin")
+	if err == nil {
+		t.Fatal("Expected request to a closed server to fail")
+	}
+	if want := "after 3 attempts"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Got error %q, expected it to mention %q", err, want)
+	}
+}
+
+func TestRequestRetryStopsOnCancel(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Hour
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.MaxRetries = 3
+	r, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.SetContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Request(server.URL + "/admin")
+	}()
+
+	// 第一次请求到达后取消：取消前进入退避等待时应立即结束，否则直接返回第一次的响应
+	for atomic.LoadInt32(&attempts) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Request kept retrying after the scan context was canceled")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("Got %d attempts, want 1", got)
+	}
+}
+
+func TestRetryOnStatus(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	tests := []struct {
+		name          string
+		retryOnStatus string
+		wantStatus    int
+		wantAttempts  int32
+	}{
+		{name: "503在列表中重试至成功", retryOnStatus: "429,503", wantStatus: http.StatusOK, wantAttempts: 3},
+		{name: "503不在列表中不重试", retryOnStatus: "429", wantStatus: http.StatusServiceUnavailable, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) <= 2 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			cfg := newTestConfig()
+			cfg.Connection.MaxRetries = 3
+			cfg.Connection.RetryOnStatus = tt.retryOnStatus
+			r, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			resp, err := r.Request(server.URL + "/admin")
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Got status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("Got %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "秒数", value: "3", want: 3 * time.Second, wantOK: true},
+		{name: "HTTP日期", value: now.Add(10 * time.Second).Format(http.TimeFormat), want: 10 * time.Second, wantOK: true},
+		{name: "过去的日期", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "空值", value: "", wantOK: false},
+		{name: "无效值", value: "soon", wantOK: false},
+		{name: "负数", value: "-1", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = 100 * time.Millisecond
+	defer func(rng *rand.Rand) { retryRand = rng }(retryRand)
+	retryRand = rand.New(rand.NewSource(1))
+
+	// 退避时间随重试次数增长，每次都在base * 2^(attempt-1)的±25%内，且不超过上限
+	prev := time.Duration(0)
+	for attempt := 1; attempt <= 8; attempt++ {
+		want := min(retryBaseDelay<<(attempt-1), retryMaxDelay)
+		low := time.Duration(float64(want) * (1 - retryJitterRatio))
+		high := min(time.Duration(float64(want)*(1+retryJitterRatio)), retryMaxDelay)
+
+		delay := retryBackoff(attempt)
+		if delay < low || delay > high {
+			t.Errorf("retryBackoff(%d) = %v, want within [%v, %v]", attempt, delay, low, high)
+		}
+		if want < retryMaxDelay && delay <= prev {
+			t.Errorf("retryBackoff(%d) = %v, want it to grow past %v", attempt, delay, prev)
+		}
+		prev = delay
+	}
+
+	// 相同重试次数的等待时间带有随机抖动
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		seen[retryBackoff(2)] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("retryBackoff(2) returned %v every time, want jitter", seen)
+	}
+
+	// 固定种子时结果可复现
+	retryRand = rand.New(rand.NewSource(42))
+	first := []time.Duration{retryBackoff(1), retryBackoff(2), retryBackoff(3)}
+	retryRand = rand.New(rand.NewSource(42))
+	second := []time.Duration{retryBackoff(1), retryBackoff(2), retryBackoff(3)}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Seeded backoff differs at %d: %v != %v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestRetriesWaitForRateLimiter(t *testing.T) {
+	defer func(delay time.Duration) { retryBaseDelay = delay }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.MaxRetries = 4
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	requester.SetRateLimiter(rate.NewLimiter(20, 1))
+
+	start := time.Now()
+	if _, err := requester.Request(server.URL + "/busy"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if got := atomic.LoadInt32(&requests); got != 5 {
+		t.Fatalf("Got %d requests, want 5", got)
+	}
+	// 5次请求、每秒20个：首次请求立即发送，每次重试都等待50ms
+	if want := 200 * time.Millisecond; elapsed < want {
+		t.Errorf("Request with retries took %v, expected at least %v with a 20/s rate limit", elapsed, want)
+	}
+}
@@ -0,0 +1,106 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// newSourceDialer 创建与默认Transport参数一致的拨号器
+func newSourceDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+}
+
+// sourceAddr 解析--ip指定的本地源地址，并检查该地址已分配给本机的某个网络接口
+func sourceAddr(ip string) (*net.TCPAddr, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid source IP %q", ip)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interface addresses: %w", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(parsed) {
+			return &net.TCPAddr{IP: parsed}, nil
+		}
+	}
+	return nil, fmt.Errorf("source IP %s is not assigned to any local network interface", ip)
+}
+
+// interfaceDialer 从指定网络接口的地址发起连接，按目标的地址族选择IPv4或IPv6源地址
+type interfaceDialer struct {
+	ipv4 *net.TCPAddr
+	ipv6 *net.TCPAddr
+}
+
+// newInterfaceDialer 查找网络接口上第一个可用的IPv4和IPv6单播地址（跳过无法直接绑定的链路本地地址）
+func newInterfaceDialer(name string) (*interfaceDialer, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("network interface %q not found: %w", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses of interface %s: %w", name, err)
+	}
+
+	d := &interfaceDialer{}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() || ipNet.IP.IsMulticast() || ipNet.IP.IsUnspecified() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			if d.ipv4 == nil {
+				d.ipv4 = &net.TCPAddr{IP: ipNet.IP}
+			}
+		} else if d.ipv6 == nil {
+			d.ipv6 = &net.TCPAddr{IP: ipNet.IP}
+		}
+	}
+	if d.ipv4 == nil && d.ipv6 == nil {
+		return nil, fmt.Errorf("network interface %s has no usable unicast address", name)
+	}
+	return d, nil
+}
+
+// DialContext 使用与目标地址族匹配的接口地址建立连接
+func (d *interfaceDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := newSourceDialer()
+	dialer.LocalAddr = d.localAddrFor(ctx, address)
+	return dialer.DialContext(ctx, network, address)
+}
+
+// localAddrFor 按目标地址（域名时取解析到的第一个地址）的地址族选择源地址，接口只有一种地址族时直接使用
+func (d *interfaceDialer) localAddrFor(ctx context.Context, address string) *net.TCPAddr {
+	if d.ipv4 == nil {
+		return d.ipv6
+	}
+	if d.ipv6 == nil {
+		return d.ipv4
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return d.ipv4
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+		if err != nil || len(ips) == 0 {
+			return d.ipv4
+		}
+		ip = ips[0]
+	}
+	if ip.To4() != nil {
+		return d.ipv4
+	}
+	return d.ipv6
+}
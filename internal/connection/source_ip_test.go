@@ -0,0 +1,148 @@
+package connection
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSourceIP(t *testing.T) {
+	var mu sync.Mutex
+	var remoteIP string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		mu.Lock()
+		remoteIP = host
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.IP = "127.0.0.1"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if transport, ok := requester.client.Transport.(*http.Transport); !ok || transport.DialContext == nil {
+		t.Fatalf("Transport has no custom dialer")
+	}
+	if _, err := requester.Request(server.URL + "/"); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if remoteIP != "127.0.0.1" {
+		t.Errorf("Server saw source IP %q, want 127.0.0.1", remoteIP)
+	}
+}
+
+func TestDomainCheckerSourceIP(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Connection.IP = "127.0.0.1"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	// 存活检测与扫描请求使用同一个带源地址的拨号器
+	checker := NewDomainChecker(cfg, requester.Transport())
+	transport, ok := checker.client.Transport.(*http.Transport)
+	if !ok || transport != requester.client.Transport || transport.DialContext == nil {
+		t.Errorf("Domain checker transport = %#v, want the requester transport with the --ip dialer", checker.client.Transport)
+	}
+}
+
+func TestSourceIPInvalid(t *testing.T) {
+	tests := []struct {
+		ip      string
+		wantErr string
+	}{
+		{ip: "not-an-ip", wantErr: "invalid source IP"},
+		{ip: "192.0.2.123", wantErr: "not assigned to any local network interface"},
+	}
+	for _, tt := range tests {
+		cfg := newTestConfig()
+		cfg.Connection.IP = tt.ip
+		if _, err := NewRequester(cfg); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewRequester with --ip %s error = %v, want %q", tt.ip, err, tt.wantErr)
+		}
+	}
+}
+
+func TestInterfaceDialer(t *testing.T) {
+	dialer, err := newInterfaceDialer("lo")
+	if err != nil {
+		t.Skipf("Loopback interface not available: %v", err)
+	}
+	if dialer.ipv4 == nil || !dialer.ipv4.IP.IsLoopback() {
+		t.Fatalf("IPv4 source = %v, want a loopback address", dialer.ipv4)
+	}
+
+	// 按目标地址族选择源地址
+	ctx := context.Background()
+	if got := dialer.localAddrFor(ctx, "127.0.0.1:80"); got != dialer.ipv4 {
+		t.Errorf("Source for IPv4 target = %v, want %v", got, dialer.ipv4)
+	}
+	if dialer.ipv6 != nil {
+		if got := dialer.localAddrFor(ctx, "[::1]:80"); got != dialer.ipv6 {
+			t.Errorf("Source for IPv6 target = %v, want %v", got, dialer.ipv6)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.Interface = "lo"
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+	if _, err := requester.Request(server.URL + "/"); err != nil {
+		t.Fatalf("Request through interface lo failed: %v", err)
+	}
+}
+
+func TestDomainCheckerInterface(t *testing.T) {
+	if _, err := newInterfaceDialer("lo"); err != nil {
+		t.Skipf("Loopback interface not available: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.Interface = "lo"
+	cfg.Connection.DomainCheckTimeout = 5
+	cfg.Connection.DomainCheckRetries = 1
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	checker := NewDomainChecker(cfg, requester.Transport())
+	if transport, ok := checker.client.Transport.(*http.Transport); !ok || transport != requester.client.Transport {
+		t.Fatalf("Domain checker transport = %#v, want the requester transport bound to lo", checker.client.Transport)
+	}
+	if alive, dead := checker.CheckMultipleDomains([]string{server.URL}); len(alive) != 1 || len(dead) != 0 {
+		t.Errorf("alive %v, dead %v, want the target alive through interface lo", alive, dead)
+	}
+}
+
+func TestInterfaceNotFound(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Connection.Interface = "no-such-iface0"
+	if _, err := NewRequester(cfg); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("NewRequester error = %v, want interface not found", err)
+	}
+}
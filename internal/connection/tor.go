@@ -0,0 +1,32 @@
+package connection
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultTorAddress Tor SOCKS5代理的默认地址
+const DefaultTorAddress = "127.0.0.1:9050"
+
+// torCheckTimeout 检查Tor是否运行的连接超时
+const torCheckTimeout = 5 * time.Second
+
+// torProxy 检查Tor的SOCKS5端口是否可连接，返回代理URL。
+// 使用net/http内置的SOCKS5支持，域名交给Tor解析，因此可以访问.onion地址
+func torProxy(address string) (string, error) {
+	if address == "" {
+		address = DefaultTorAddress
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return "", fmt.Errorf("invalid Tor address %q: %w", address, err)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, torCheckTimeout)
+	if err != nil {
+		return "", fmt.Errorf("tor is not reachable at %s (is the Tor service running?): %w", address, err)
+	}
+	conn.Close()
+
+	return "socks5://" + address, nil
+}
@@ -0,0 +1,79 @@
+package connection
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTorProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	cfg := newTestConfig()
+	cfg.Connection.Tor = true
+	cfg.Connection.TorAddress = listener.Addr().String()
+	requester, err := NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("NewRequester failed: %v", err)
+	}
+
+	transport, ok := requester.client.Transport.(*http.Transport)
+	if !ok || transport.Proxy == nil {
+		t.Fatalf("Transport has no proxy")
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://exampleonionaddress.onion/", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy callback failed: %v", err)
+	}
+	if want := "socks5://" + listener.Addr().String(); proxyURL == nil || proxyURL.String() != want {
+		t.Errorf("Proxy = %v, want %s", proxyURL, want)
+	}
+}
+
+func TestTorNotRunning(t *testing.T) {
+	// 获取一个没有监听的端口
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.Tor = true
+	cfg.Connection.TorAddress = address
+	if _, err := NewRequester(cfg); err == nil || !strings.Contains(err.Error(), "tor is not reachable") {
+		t.Errorf("NewRequester error = %v, want Tor not reachable error", err)
+	}
+}
+
+func TestTorWithProxyList(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	cfg := newTestConfig()
+	cfg.Connection.Tor = true
+	cfg.Connection.TorAddress = listener.Addr().String()
+	cfg.Connection.Proxies = []string{"http://127.0.0.1:8080", "http://127.0.0.1:8081"}
+	if _, err := NewRequester(cfg); err == nil || !strings.Contains(err.Error(), "--tor cannot be used together with --proxies-file") {
+		t.Errorf("NewRequester error = %v, want --tor and proxy list conflict", err)
+	}
+}
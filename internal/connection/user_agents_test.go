@@ -0,0 +1,70 @@
+package connection
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRandomUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.UserAgent()]++
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		randomAgent bool
+	}{
+		{name: "随机User-Agent", randomAgent: true},
+		{name: "固定User-Agent", randomAgent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = make(map[string]int)
+			cfg := newTestConfig()
+			cfg.General.RandomUserAgents = tt.randomAgent
+			r, err := NewRequester(cfg)
+			if err != nil {
+				t.Fatalf("NewRequester failed: %v", err)
+			}
+
+			// 并发发送请求，随机选择需要并发安全
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := r.Request(server.URL + "/"); err != nil {
+						t.Errorf("Request failed: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !tt.randomAgent {
+				if seen[DefaultUserAgent] != 50 {
+					t.Errorf("Expected every request to use the default User-Agent, got %v", seen)
+				}
+				return
+			}
+			if len(seen) < 2 {
+				t.Errorf("Expected several User-Agents across 50 requests, got %v", seen)
+			}
+			for agent := range seen {
+				if !slices.Contains(userAgents, agent) {
+					t.Errorf("User-Agent %q is not from the built-in pool", agent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package dictionary
+
+import (
+	"regexp"
+	"strings"
+)
+
+// extensionRegex 匹配字典条目末尾的扩展名
+var extensionRegex = regexp.MustCompile(`\.[a-zA-Z0-9]+$`)
+
+// WordlistStats 字典统计信息
+type WordlistStats struct {
+	TotalEntries   int    // 加载的条目总数（含重复）
+	UniqueEntries  int    // 去重后的条目数
+	Duplicates     int    // 重复条目数
+	WithExtensions int    // 带扩展名的条目数
+	Directories    int    // 以斜杠结尾的目录条目数
+	Longest        string // 最长条目
+	Shortest       string // 最短条目
+	GeneratedPaths int    // 按当前扩展名/前缀/后缀配置生成的路径数
+}
+
+// Analyze 统计字典信息，不发送任何请求
+func (dict *Dictionary) Analyze() (*WordlistStats, error) {
+	stats := &WordlistStats{}
+
+	seen := make(map[string]bool)
+	err := dict.streamWords(func(word string) bool {
+		stats.TotalEntries++
+		if seen[word] {
+			stats.Duplicates++
+			return true
+		}
+		seen[word] = true
+
+		if strings.HasSuffix(word, "/") {
+			stats.Directories++
+		} else if extensionRegex.MatchString(word) {
+			stats.WithExtensions++
+		}
+
+		if stats.Longest == "" || len(word) > len(stats.Longest) {
+			stats.Longest = word
+		}
+		if stats.Shortest == "" || len(word) < len(stats.Shortest) {
+			stats.Shortest = word
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats.UniqueEntries = len(seen)
+
+	stats.GeneratedPaths, err = dict.CountPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,186 @@
+package dictionary
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	// defaultDedupMemoryLimit 低内存去重在内存中保存的哈希数量上限，超出后写入磁盘
+	defaultDedupMemoryLimit = 1 << 20
+	// dedupBlockSize 磁盘哈希文件每个块的哈希数量，每次磁盘查找只读取一个块
+	dedupBlockSize = 512
+)
+
+// dedupSet 低内存模式的路径去重集合
+// 内存中最多保存memoryLimit个哈希，超出后与磁盘上已排序的哈希文件归并，
+// 内存中只保留每个块的第一个哈希作为索引
+type dedupSet struct {
+	memoryLimit int
+	memory      map[uint64]struct{}
+
+	file  *os.File // 已排序的哈希文件，为nil时还没有写入磁盘
+	count int64    // 磁盘上的哈希数量
+	index []uint64 // 每个块的第一个哈希
+	block []byte   // 读取块的缓冲区
+}
+
+// newDedupSet 创建去重集合，memoryLimit<=0时使用默认上限
+func newDedupSet(memoryLimit int) *dedupSet {
+	if memoryLimit <= 0 {
+		memoryLimit = defaultDedupMemoryLimit
+	}
+	return &dedupSet{
+		memoryLimit: memoryLimit,
+		memory:      make(map[uint64]struct{}),
+	}
+}
+
+// Add 添加哈希，返回哈希此前是否不存在
+func (s *dedupSet) Add(key uint64) (bool, error) {
+	if _, exists := s.memory[key]; exists {
+		return false, nil
+	}
+	onDisk, err := s.onDisk(key)
+	if err != nil {
+		return false, err
+	}
+	if onDisk {
+		return false, nil
+	}
+
+	s.memory[key] = struct{}{}
+	if len(s.memory) >= s.memoryLimit {
+		if err := s.spill(); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Close 关闭并删除磁盘上的哈希文件
+func (s *dedupSet) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	name := s.file.Name()
+	err := s.file.Close()
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	s.file = nil
+	return err
+}
+
+// onDisk 在磁盘哈希文件中查找哈希
+func (s *dedupSet) onDisk(key uint64) (bool, error) {
+	if s.file == nil || len(s.index) == 0 || key < s.index[0] {
+		return false, nil
+	}
+
+	// 找到第一个哈希不大于key的最后一个块
+	i := sort.Search(len(s.index), func(i int) bool { return s.index[i] > key }) - 1
+	n := int64(dedupBlockSize)
+	if rest := s.count - int64(i)*dedupBlockSize; rest < n {
+		n = rest
+	}
+	block := s.block[:n*8]
+	if _, err := s.file.ReadAt(block, int64(i)*dedupBlockSize*8); err != nil {
+		return false, fmt.Errorf("failed to read dedup file: %w", err)
+	}
+
+	j := sort.Search(int(n), func(j int) bool { return binary.LittleEndian.Uint64(block[j*8:]) >= key })
+	return j < int(n) && binary.LittleEndian.Uint64(block[j*8:]) == key, nil
+}
+
+// spill 把内存中的哈希与磁盘哈希文件归并成新的已排序文件
+func (s *dedupSet) spill() error {
+	keys := make([]uint64, 0, len(s.memory))
+	for key := range s.memory {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	out, err := os.CreateTemp("", "dirsearch-dedup-*")
+	if err != nil {
+		return fmt.Errorf("failed to create dedup file: %w", err)
+	}
+
+	count, index, err := s.merge(out, keys)
+	if err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return err
+	}
+
+	if err := s.Close(); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return fmt.Errorf("failed to remove old dedup file: %w", err)
+	}
+	s.file = out
+	s.count = count
+	s.index = index
+	if s.block == nil {
+		s.block = make([]byte, dedupBlockSize*8)
+	}
+	s.memory = make(map[uint64]struct{})
+	return nil
+}
+
+// merge 把已排序的keys与现有磁盘哈希归并写入out，返回哈希数量和块索引
+func (s *dedupSet) merge(out *os.File, keys []uint64) (int64, []uint64, error) {
+	w := bufio.NewWriter(out)
+	var count int64
+	var index []uint64
+	var buf, readBuf [8]byte
+	write := func(key uint64) error {
+		if count%dedupBlockSize == 0 {
+			index = append(index, key)
+		}
+		count++
+		binary.LittleEndian.PutUint64(buf[:], key)
+		_, err := w.Write(buf[:])
+		return err
+	}
+
+	var r *bufio.Reader
+	if s.file != nil {
+		r = bufio.NewReader(io.NewSectionReader(s.file, 0, s.count*8))
+	}
+	next := func() (uint64, bool, error) {
+		if r == nil {
+			return 0, false, nil
+		}
+		if _, err := io.ReadFull(r, readBuf[:]); err != nil {
+			if err == io.EOF {
+				return 0, false, nil
+			}
+			return 0, false, err
+		}
+		return binary.LittleEndian.Uint64(readBuf[:]), true, nil
+	}
+
+	old, ok, err := next()
+	for err == nil && (ok || len(keys) > 0) {
+		if ok && (len(keys) == 0 || old < keys[0]) {
+			if err = write(old); err == nil {
+				old, ok, err = next()
+			}
+			continue
+		}
+		err = write(keys[0])
+		keys = keys[1:]
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to write dedup file: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return 0, nil, fmt.Errorf("failed to write dedup file: %w", err)
+	}
+	return count, index, nil
+}
@@ -0,0 +1,51 @@
+package dictionary
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dirsearch-go/internal/config"
+)
+
+// EmbeddedWordlist 内置默认字典的名称，作为字典路径使用时加载内置条目
+const EmbeddedWordlist = "embedded:default.txt"
+
+// defaultWordlist 内置的默认字典
+//
+//go:embed wordlists/default.txt
+var defaultWordlist string
+
+// DefaultWordlist 查找默认字典：优先使用$DIRSEARCH_HOME/wordlists/common.txt，否则使用内置字典
+func DefaultWordlist() string {
+	if home := os.Getenv("DIRSEARCH_HOME"); home != "" {
+		path := filepath.Join(home, "wordlists", "common.txt")
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return EmbeddedWordlist
+}
+
+// NeedsDefaultWordlist 判断是否既没有字典文件也没有配置其他wordlist源
+func NeedsDefaultWordlist(cfg *config.Config) bool {
+	if len(cfg.Dictionary.Wordlists) > 0 {
+		return false
+	}
+	source := cfg.Dictionary.Source
+	return source.Type == "" || source.Type == string(SourceFile) && source.Path == ""
+}
+
+// embeddedWords 读取内置默认字典，跳过空行和注释并应用大小写转换
+func (dict *Dictionary) embeddedWords() []string {
+	var words []string
+	for _, line := range strings.Split(defaultWordlist, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, dict.transformCase(word))
+	}
+	return words
+}
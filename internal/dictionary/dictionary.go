@@ -2,18 +2,26 @@ package dictionary
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
 
 	"dirsearch-go/internal/config"
 	"dirsearch-go/internal/utils"
 )
 
+// ErrEmptyWordlist 字典没有任何有效条目
+var ErrEmptyWordlist = errors.New("wordlist produced 0 entries")
+
 // Dictionary 字典结构
 type Dictionary struct {
 	config        *config.Config
@@ -22,7 +30,11 @@ type Dictionary struct {
 	prefixes      []string
 	suffixes      []string
 	words         []string
+	priority      []string // --priority-file中的高价值条目，排在所有字典条目之前
+	files         []string // 低内存模式下按需流式读取的字典文件
 	sourceFactory *SourceFactory
+	warnings      int // --ignore-wordlist-errors忽略的加载失败的字典来源数量
+	dedupLimit    int // 低内存模式去重在内存中保存的哈希数量上限，为0时使用默认值
 }
 
 // NewDictionary 创建新的字典
@@ -30,24 +42,127 @@ func NewDictionary(cfg *config.Config) (*Dictionary, error) {
 	dict := &Dictionary{
 		config:        cfg,
 		wordlists:     cfg.Dictionary.Wordlists,
-		extensions:    cfg.Dictionary.DefaultExtensions,
+		extensions:    append([]string(nil), cfg.Dictionary.DefaultExtensions...),
 		prefixes:      cfg.Dictionary.Prefixes,
 		suffixes:      cfg.Dictionary.Suffixes,
 		words:         make([]string, 0),
 		sourceFactory: NewSourceFactory(),
 	}
 
+	// 追加--tech指定的技术栈对应的扩展名
+	techExtensions, err := TechExtensions(cfg.Dictionary.Tech)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tech: %w", err)
+	}
+	dict.AddExtensions(techExtensions)
+
 	// 加载字典文件
 	if err := dict.loadWordlists(); err != nil {
 		return nil, fmt.Errorf("failed to load wordlists: %w", err)
 	}
 
+	if dict.warnings > 0 {
+		log.Printf("Warning: %d wordlist source(s) failed to load, continuing with the rest", dict.warnings)
+	}
+
+	// 字典为空（空文件或只有注释）时直接报错，避免扫描静默结束
+	if !dict.hasWords() {
+		return nil, fmt.Errorf("%w, check that the wordlist is not empty or comment-only", ErrEmptyWordlist)
+	}
+
 	return dict, nil
 }
 
 // loadWordlists 加载字典文件
 func (dict *Dictionary) loadWordlists() error {
+	// 高价值条目通常很少，始终加载到内存中
+	if dict.config.Dictionary.PriorityFile != "" {
+		words, err := dict.readWordlistFile(dict.config.Dictionary.PriorityFile)
+		if err != nil {
+			err = fmt.Errorf("failed to load priority file %s: %w", dict.config.Dictionary.PriorityFile, err)
+			if err := dict.wordlistError(err); err != nil {
+				return err
+			}
+		}
+		dict.priority = words
+	}
+
+	files, err := dict.collectWordlistFiles()
+	if err != nil {
+		return err
+	}
+
+	if dict.config.Dictionary.LowMemory {
+		// 低内存模式：只记录文件，扫描时流式读取
+		dict.files = dict.readableWordlistFiles(files)
+	} else if err := dict.loadWordlistFiles(files, wordlistWorkers(len(files))); err != nil {
+		// 并发加载所有字典文件
+		return err
+	}
+
+	// 加载内置默认字典
 	for _, wordlistPath := range dict.wordlists {
+		if wordlistPath == EmbeddedWordlist {
+			dict.words = append(dict.words, dict.embeddedWords()...)
+			break
+		}
+	}
+
+	// 尝试从配置的源加载wordlist
+	if err := dict.loadFromSources(); err != nil {
+		if err := dict.wordlistError(fmt.Errorf("failed to load from sources: %w", err)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// wordlistError 处理单个字典来源的加载错误：启用--ignore-wordlist-errors时记录警告并返回nil，继续使用其他来源
+func (dict *Dictionary) wordlistError(err error) error {
+	if !dict.config.Dictionary.IgnoreWordlistErrors {
+		return err
+	}
+	log.Printf("Warning: %v", err)
+	dict.warnings++
+	return nil
+}
+
+// readableWordlistFiles 低内存模式下启用--ignore-wordlist-errors时提前去掉无法打开的字典文件，
+// 避免扫描途中流式读取失败
+func (dict *Dictionary) readableWordlistFiles(files []string) []string {
+	if !dict.config.Dictionary.IgnoreWordlistErrors {
+		return files
+	}
+
+	readable := files[:0:0]
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			dict.wordlistError(fmt.Errorf("failed to open wordlist file %s: %w", file, err))
+			continue
+		}
+		f.Close()
+		readable = append(readable, file)
+	}
+	return readable
+}
+
+// Warnings 返回--ignore-wordlist-errors忽略的加载失败的字典来源数量
+func (dict *Dictionary) Warnings() int {
+	return dict.warnings
+}
+
+// collectWordlistFiles 收集需要加载的字典文件，目录会被递归展开
+func (dict *Dictionary) collectWordlistFiles() ([]string, error) {
+	var files []string
+
+	for _, wordlistPath := range dict.wordlists {
+		// 内置默认字典不从文件加载
+		if wordlistPath == EmbeddedWordlist {
+			continue
+		}
+
 		// 检查是否为URL，如果是URL则跳过文件加载
 		if utils.IsURL(wordlistPath) {
 			log.Printf("Debug: Skipping URL wordlist in file loading: %s", wordlistPath)
@@ -56,40 +171,118 @@ func (dict *Dictionary) loadWordlists() error {
 
 		// 检查是否为目录
 		if info, err := os.Stat(wordlistPath); err == nil && info.IsDir() {
-			// 如果是目录，加载目录下的所有文件
-			files, err := filepath.Glob(filepath.Join(wordlistPath, "*"))
+			// 如果是目录，收集目录树下的所有字典文件
+			dirFiles, err := dict.collectWordlistDir(wordlistPath)
 			if err != nil {
-				return fmt.Errorf("failed to glob directory %s: %w", wordlistPath, err)
-			}
-			for _, file := range files {
-				if err := dict.loadWordlistFile(file); err != nil {
-					return fmt.Errorf("failed to load wordlist file %s: %w", file, err)
-				}
+				return nil, err
 			}
+			files = append(files, dirFiles...)
 		} else {
-			// 如果是文件，直接加载
-			if err := dict.loadWordlistFile(wordlistPath); err != nil {
-				return fmt.Errorf("failed to load wordlist file %s: %w", wordlistPath, err)
+			files = append(files, wordlistPath)
+		}
+	}
+
+	return files, nil
+}
+
+// collectWordlistDir 递归收集目录下匹配扩展名的字典文件
+func (dict *Dictionary) collectWordlistDir(dir string) ([]string, error) {
+	allowed := wordlistFileExtensions(dict.config.Dictionary.WordlistExtensions)
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk directory %s: %w", dir, err)
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if allowed[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// wordlistFileExtensions 解析目录加载时允许的文件扩展名（逗号分隔），默认只加载.txt
+func wordlistFileExtensions(extensions []string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, entry := range extensions {
+		for _, ext := range strings.Split(entry, ",") {
+			ext = strings.ToLower(strings.TrimSpace(ext))
+			if ext == "" {
+				continue
+			}
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
 			}
+			allowed[ext] = true
 		}
 	}
+	if len(allowed) == 0 {
+		allowed[".txt"] = true
+	}
+	return allowed
+}
 
-	// 尝试从配置的源加载wordlist
-	if err := dict.loadFromSources(); err != nil {
-		return fmt.Errorf("failed to load from sources: %w", err)
+// wordlistWorkers 计算加载字典文件的并发数
+func wordlistWorkers(fileCount int) int {
+	return max(1, min(runtime.NumCPU(), fileCount))
+}
+
+// loadWordlistFiles 使用有限的工作池并发读取字典文件，按文件顺序合并结果以保证顺序确定
+func (dict *Dictionary) loadWordlistFiles(files []string, workers int) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	type loadResult struct {
+		words []string
+		err   error
+	}
+	results := make([]loadResult, len(files))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < max(1, workers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				words, err := dict.readWordlistFile(files[index])
+				results[index] = loadResult{words: words, err: err}
+			}
+		}()
+	}
+	for index := range files {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	for index, result := range results {
+		if result.err != nil {
+			if err := dict.wordlistError(fmt.Errorf("failed to load wordlist file %s: %w", files[index], result.err)); err != nil {
+				return err
+			}
+			continue
+		}
+		dict.words = append(dict.words, result.words...)
 	}
 
 	return nil
 }
 
-// loadWordlistFile 加载单个字典文件
-func (dict *Dictionary) loadWordlistFile(filepath string) error {
+// readWordlistFile 读取单个字典文件，跳过空行和注释并应用大小写转换
+func (dict *Dictionary) readWordlistFile(filepath string) ([]string, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
-		return fmt.Errorf("failed to open wordlist file: %w", err)
+		return nil, fmt.Errorf("failed to open wordlist file: %w", err)
 	}
 	defer file.Close()
 
+	var words []string
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		word := strings.TrimSpace(scanner.Text())
@@ -99,19 +292,22 @@ func (dict *Dictionary) loadWordlistFile(filepath string) error {
 			continue
 		}
 
-		// 应用大小写转换
-		if dict.config.Dictionary.Lowercase {
-			word = strings.ToLower(word)
-		} else if dict.config.Dictionary.Uppercase {
-			word = strings.ToUpper(word)
-		} else if dict.config.Dictionary.Capitalization {
-			word = strings.Title(strings.ToLower(word))
-		}
-
-		dict.words = append(dict.words, word)
+		words = append(words, dict.transformCase(word))
 	}
 
-	return scanner.Err()
+	return words, scanner.Err()
+}
+
+// transformCase 应用大小写转换
+func (dict *Dictionary) transformCase(word string) string {
+	if dict.config.Dictionary.Lowercase {
+		return strings.ToLower(word)
+	} else if dict.config.Dictionary.Uppercase {
+		return strings.ToUpper(word)
+	} else if dict.config.Dictionary.Capitalization {
+		return strings.Title(strings.ToLower(word))
+	}
+	return word
 }
 
 // loadFromSources 从配置的源加载wordlist
@@ -175,13 +371,7 @@ func (dict *Dictionary) loadFromSources() error {
 		}
 
 		// 应用大小写转换
-		if dict.config.Dictionary.Lowercase {
-			word = strings.ToLower(word)
-		} else if dict.config.Dictionary.Uppercase {
-			word = strings.ToUpper(word)
-		} else if dict.config.Dictionary.Capitalization {
-			word = strings.Title(strings.ToLower(word))
-		}
+		word = dict.transformCase(word)
 
 		dict.words = append(dict.words, word)
 	}
@@ -193,58 +383,112 @@ func (dict *Dictionary) loadFromSources() error {
 
 // GeneratePaths 生成扫描路径
 func (dict *Dictionary) GeneratePaths() ([]string, error) {
+	// 低内存模式下通过流式生成收集路径
+	if dict.config.Dictionary.LowMemory {
+		var paths []string
+		err := dict.StreamPaths(func(path string) bool {
+			paths = append(paths, path)
+			return true
+		})
+		return paths, err
+	}
+
 	var paths []string
 
-	for _, word := range dict.words {
-		// 跳过被排除的扩展名
+	// 高价值条目在前，去重时保留首次出现的位置，使其排在字典中的同名条目之前
+	for _, words := range [][]string{dict.priority, dict.words} {
+		for _, word := range words {
+			// 跳过被排除的扩展名
+			if dict.shouldExcludeWord(word) {
+				continue
+			}
+			paths = dict.appendWordPaths(paths, word)
+		}
+	}
+
+	// 去重
+	paths = dict.deduplicate(paths)
+
+	return paths, nil
+}
+
+// PriorityPaths 返回--priority-file中的条目生成的扫描路径（已去重，保持文件中的顺序）
+func (dict *Dictionary) PriorityPaths() []string {
+	var paths []string
+	for _, word := range dict.priority {
 		if dict.shouldExcludeWord(word) {
 			continue
 		}
+		paths = dict.appendWordPaths(paths, word)
+	}
+	return dict.deduplicate(paths)
+}
 
-		// 处理扩展名
-		if dict.config.Dictionary.ForceExtensions {
-			// 强制添加扩展名
-			paths = append(paths, word)
+// appendWordPaths 根据扩展名、前缀和后缀配置生成单个条目的路径
+func (dict *Dictionary) appendWordPaths(paths []string, word string) []string {
+	// 以斜杠结尾的目录条目不追加或替换扩展名，避免生成 admin/.php 之类的路径
+	isDirectory := strings.HasSuffix(word, "/")
+
+	// 移除条目原有的扩展名，之后为没有扩展名的条目追加配置的扩展名
+	removeExtensions := dict.config.Dictionary.RemoveExtensions && !isDirectory
+	if removeExtensions {
+		word = removeExtension(word)
+	}
+
+	// 处理扩展名
+	if dict.config.Dictionary.ForceExtensions {
+		// 强制添加扩展名
+		paths = append(paths, word)
+		if !isDirectory {
 			for _, ext := range dict.extensions {
 				paths = append(paths, word+"."+ext)
 			}
 			paths = append(paths, word+"/")
-		} else if dict.config.Dictionary.OverwriteExtensions {
-			// 覆盖扩展名
-			paths = append(paths, word)
+		}
+	} else if dict.config.Dictionary.OverwriteExtensions {
+		// 覆盖扩展名
+		paths = append(paths, word)
+		if !isDirectory {
 			for _, ext := range dict.extensions {
 				paths = append(paths, dict.replaceExtension(word, ext))
 			}
+		}
+	} else {
+		// 替换 %EXT% 关键字
+		if strings.Contains(word, "%EXT%") {
+			for _, ext := range dict.extensions {
+				newWord := strings.ReplaceAll(word, "%EXT%", ext)
+				paths = append(paths, newWord)
+			}
 		} else {
-			// 替换 %EXT% 关键字
-			if strings.Contains(word, "%EXT%") {
+			paths = append(paths, word)
+			if removeExtensions && !extensionRegex.MatchString(word) {
 				for _, ext := range dict.extensions {
-					newWord := strings.ReplaceAll(word, "%EXT%", ext)
-					paths = append(paths, newWord)
+					paths = append(paths, word+"."+ext)
 				}
-			} else {
-				paths = append(paths, word)
 			}
 		}
+	}
 
-		// 添加前缀
-		for _, prefix := range dict.prefixes {
-			paths = append(paths, prefix+word)
-		}
+	// 对没有扩展名的条目追加斜杠变体，发现 /admin 返回404而 /admin/ 返回200的目录
+	if dict.config.Dictionary.AppendSlash && !isDirectory && !strings.Contains(word, "%EXT%") && path.Ext(word) == "" {
+		paths = append(paths, word+"/")
+	}
 
-		// 添加后缀
-		for _, suffix := range dict.suffixes {
-			// 跳过目录的后缀
-			if !strings.HasSuffix(word, "/") {
-				paths = append(paths, word+suffix)
-			}
-		}
+	// 添加前缀
+	for _, prefix := range dict.prefixes {
+		paths = append(paths, prefix+word)
 	}
 
-	// 去重
-	paths = dict.deduplicate(paths)
+	// 添加后缀
+	for _, suffix := range dict.suffixes {
+		// 跳过目录的后缀
+		if !isDirectory {
+			paths = append(paths, word+suffix)
+		}
+	}
 
-	return paths, nil
+	return paths
 }
 
 // shouldExcludeWord 判断是否应该排除单词
@@ -257,6 +501,16 @@ func (dict *Dictionary) shouldExcludeWord(word string) bool {
 	return false
 }
 
+// removeExtension 移除末尾的扩展名（多个点时只移除最后一个），
+// 移除后为空或只剩目录部分的条目（如 .htaccess、config/.env）保持不变
+func removeExtension(word string) string {
+	stripped := extensionRegex.ReplaceAllString(word, "")
+	if stripped == "" || strings.HasSuffix(stripped, "/") {
+		return word
+	}
+	return stripped
+}
+
 // replaceExtension 替换扩展名
 func (dict *Dictionary) replaceExtension(word, newExt string) string {
 	// 定义不应该被覆盖的扩展名
@@ -278,14 +532,15 @@ func (dict *Dictionary) replaceExtension(word, newExt string) string {
 	return word + "." + newExt
 }
 
-// deduplicate 去重
+// deduplicate 去重，保留首次出现的路径
 func (dict *Dictionary) deduplicate(paths []string) []string {
 	seen := make(map[string]bool)
 	var result []string
 
 	for _, path := range paths {
-		if !seen[path] {
-			seen[path] = true
+		key := dict.dedupKey(path)
+		if !seen[key] {
+			seen[key] = true
 			result = append(result, path)
 		}
 	}
@@ -293,13 +548,26 @@ func (dict *Dictionary) deduplicate(paths []string) []string {
 	return result
 }
 
+// dedupKey 路径去重使用的键，启用大小写不敏感去重时忽略大小写（适用于Windows/IIS目标）
+func (dict *Dictionary) dedupKey(path string) string {
+	if dict.config.Dictionary.CaseInsensitiveDedup {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
 // GetWordCount 获取单词数量
 func (dict *Dictionary) GetWordCount() int {
-	return len(dict.words)
+	count := 0
+	dict.streamWords(func(string) bool {
+		count++
+		return true
+	})
+	return count
 }
 
 // GetPathCount 获取路径数量
 func (dict *Dictionary) GetPathCount() int {
-	paths, _ := dict.GeneratePaths()
-	return len(paths)
+	count, _ := dict.CountPaths()
+	return count
 }
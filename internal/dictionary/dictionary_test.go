@@ -0,0 +1,822 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+// writeWordlist 写入测试字典文件
+func writeWordlist(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+	return path
+}
+
+func TestAnalyze(t *testing.T) {
+	wordlist := writeWordlist(t, t.TempDir(), "words.txt", "# comment\nadmin\nlogin.php\nbackup/\nadmin\nconfig.inc.php\na\n")
+
+	cfg := &config.Config{
+		Dictionary: config.DictionaryConfig{
+			Wordlists:         []string{wordlist},
+			DefaultExtensions: []string{"php", "html"},
+			ForceExtensions:   true,
+		},
+	}
+
+	dict, err := NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+
+	stats, err := dict.Analyze()
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	expected := WordlistStats{
+		TotalEntries:   6,
+		UniqueEntries:  5,
+		Duplicates:     1,
+		WithExtensions: 2,
+		Directories:    1,
+		Longest:        "config.inc.php",
+		Shortest:       "a",
+		GeneratedPaths: dict.GetPathCount(),
+	}
+	if *stats != expected {
+		t.Errorf("Analyze() = %+v, want %+v", *stats, expected)
+	}
+	if stats.GeneratedPaths <= stats.UniqueEntries {
+		t.Errorf("Expected generated paths (%d) to exceed unique entries (%d) with force extensions", stats.GeneratedPaths, stats.UniqueEntries)
+	}
+}
+
+func TestSaveMerged(t *testing.T) {
+	dir := t.TempDir()
+	first := writeWordlist(t, dir, "first.txt", "admin\nLogin\nbackup\n")
+	second := writeWordlist(t, dir, "second.txt", "login\nADMIN\nuploads\n")
+
+	cfg := &config.Config{
+		Dictionary: config.DictionaryConfig{
+			Wordlists: []string{first, second},
+			Lowercase: true,
+		},
+	}
+
+	dict, err := NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+
+	output := filepath.Join(dir, "merged.txt")
+	count, err := dict.SaveMerged(output)
+	if err != nil {
+		t.Fatalf("SaveMerged failed: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("SaveMerged() wrote %d entries, want 4", count)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read merged wordlist: %v", err)
+	}
+	expected := "admin\nlogin\nbackup\nuploads\n"
+	if string(data) != expected {
+		t.Errorf("Merged wordlist = %q, want %q", string(data), expected)
+	}
+}
+
+func TestSaveMergedInPlace(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := writeWordlist(t, dir, "words.txt", "admin\nLogin\nadmin\nuploads\n")
+
+	// 低内存模式下保存时才读取字典文件，输出到输入文件本身不能先截断它
+	cfg := &config.Config{
+		Dictionary: config.DictionaryConfig{
+			Wordlists: []string{wordlist},
+			Lowercase: true,
+			LowMemory: true,
+		},
+	}
+	dict, err := NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+
+	count, err := dict.SaveMerged(wordlist)
+	if err != nil {
+		t.Fatalf("SaveMerged failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("SaveMerged() wrote %d entries, want 3", count)
+	}
+	data, err := os.ReadFile(wordlist)
+	if err != nil {
+		t.Fatalf("Failed to read merged wordlist: %v", err)
+	}
+	if expected := "admin\nlogin\nuploads\n"; string(data) != expected {
+		t.Errorf("Merged wordlist = %q, want %q", string(data), expected)
+	}
+
+	// 读取字典失败时不创建或修改输出文件
+	os.Remove(wordlist)
+	output := writeWordlist(t, dir, "merged.txt", "existing\n")
+	if _, err := dict.SaveMerged(output); err == nil {
+		t.Fatal("Expected error when the wordlist cannot be read")
+	}
+	if data, err := os.ReadFile(output); err != nil || string(data) != "existing\n" {
+		t.Errorf("Output file should be left untouched on error, got %q (%v)", string(data), err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Temporary files should not be left behind, found %d entries", len(entries))
+	}
+}
+
+func TestNewDictionaryEmptyWordlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "空文件", content: ""},
+		{name: "只有注释和空行", content: "# comment\n\n   \n# another\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wordlist := writeWordlist(t, t.TempDir(), "words.txt", tt.content)
+			cfg := &config.Config{
+				Dictionary: config.DictionaryConfig{Wordlists: []string{wordlist}},
+			}
+
+			dict, err := NewDictionary(cfg)
+			if !errors.Is(err, ErrEmptyWordlist) {
+				t.Fatalf("NewDictionary() error = %v, want %v", err, ErrEmptyWordlist)
+			}
+			if dict != nil {
+				t.Error("Expected nil dictionary for empty wordlist")
+			}
+		})
+	}
+}
+
+func TestLoadWordlistDirRecursive(t *testing.T) {
+	dir := t.TempDir()
+	writeWordlist(t, dir, "top.txt", "admin\n")
+	writeWordlist(t, dir, "web/php.txt", "index.php\n")
+	writeWordlist(t, dir, "web/deep/api.TXT", "api/v1\n")
+	writeWordlist(t, dir, "web/notes.md", "should-not-load\n")
+	// 名称带.txt的子目录不能被当作文件打开
+	if err := os.MkdirAll(filepath.Join(dir, "empty.txt"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		extensions []string
+		expected   []string
+	}{
+		{
+			name:     "默认只加载txt",
+			expected: []string{"admin", "api/v1", "index.php"},
+		},
+		{
+			name:       "按扩展名过滤",
+			extensions: []string{"md, .TXT"},
+			expected:   []string{"admin", "api/v1", "index.php", "should-not-load"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Dictionary: config.DictionaryConfig{
+					Wordlists:          []string{dir},
+					WordlistExtensions: tt.extensions,
+				},
+			}
+
+			dict, err := NewDictionary(cfg)
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+
+			words := append([]string(nil), dict.words...)
+			sort.Strings(words)
+			if strings.Join(words, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Loaded words = %v, want %v", words, tt.expected)
+			}
+		})
+	}
+}
+
+// writeWordlists 写入多个测试字典文件，每个文件包含lines个条目
+func writeWordlists(t testing.TB, dir string, count, lines int) []string {
+	t.Helper()
+	files := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		var b strings.Builder
+		for j := 0; j < lines; j++ {
+			fmt.Fprintf(&b, "file%d-word%d\n", i, j)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("list%02d.txt", i))
+		if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+			t.Fatalf("Failed to write wordlist: %v", err)
+		}
+		files = append(files, path)
+	}
+	return files
+}
+
+func TestLoadWordlistFilesConcurrent(t *testing.T) {
+	files := writeWordlists(t, t.TempDir(), 8, 100)
+	cfg := &config.Config{
+		Dictionary: config.DictionaryConfig{Wordlists: files, Uppercase: true},
+	}
+
+	sequential := &Dictionary{config: cfg}
+	if err := sequential.loadWordlistFiles(files, 1); err != nil {
+		t.Fatalf("Sequential load failed: %v", err)
+	}
+
+	concurrent, err := NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+
+	if len(concurrent.words) != 800 {
+		t.Fatalf("Loaded %d words, want 800", len(concurrent.words))
+	}
+	if strings.Join(concurrent.words, ",") != strings.Join(sequential.words, ",") {
+		t.Error("Concurrent load should keep the same order as sequential load")
+	}
+	if concurrent.words[0] != "FILE0-WORD0" || concurrent.words[799] != "FILE7-WORD99" {
+		t.Errorf("Unexpected first/last words: %q, %q", concurrent.words[0], concurrent.words[799])
+	}
+}
+
+func TestLoadWordlistFilesMissingFile(t *testing.T) {
+	files := writeWordlists(t, t.TempDir(), 2, 1)
+	files = append(files, filepath.Join(t.TempDir(), "missing.txt"))
+
+	dict := &Dictionary{config: &config.Config{}}
+	if err := dict.loadWordlistFiles(files, 4); err == nil {
+		t.Fatal("Expected error for missing wordlist file")
+	}
+}
+
+func TestIgnoreWordlistErrors(t *testing.T) {
+	dir := t.TempDir()
+	wordlists := []string{
+		writeWordlist(t, dir, "first.txt", "admin\nlogin\n"),
+		filepath.Join(dir, "missing.txt"),
+		writeWordlist(t, dir, "second.txt", "backup\n"),
+	}
+
+	tests := []struct {
+		name      string
+		lowMemory bool
+	}{
+		{name: "内存模式", lowMemory: false},
+		{name: "低内存模式", lowMemory: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Dictionary.Wordlists = wordlists
+			cfg.Dictionary.LowMemory = tt.lowMemory
+
+			// 低内存模式在扫描时才读取文件
+			if _, err := NewDictionary(cfg); err == nil && !tt.lowMemory {
+				t.Fatal("Expected missing wordlist to abort without --ignore-wordlist-errors")
+			}
+
+			cfg.Dictionary.IgnoreWordlistErrors = true
+			dict, err := NewDictionary(cfg)
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+			if got := dict.Warnings(); got != 1 {
+				t.Errorf("Got %d warnings, want 1", got)
+			}
+
+			paths, err := dict.GeneratePaths()
+			if err != nil {
+				t.Fatalf("GeneratePaths failed: %v", err)
+			}
+			if want := []string{"admin", "login", "backup"}; !slices.Equal(paths, want) {
+				t.Errorf("Got paths %v, want %v", paths, want)
+			}
+		})
+	}
+}
+
+func BenchmarkLoadWordlistFiles(b *testing.B) {
+	files := writeWordlists(b, b.TempDir(), 16, 50000)
+	cfg := &config.Config{}
+
+	benchmarks := []struct {
+		name    string
+		workers int
+	}{
+		{name: "sequential", workers: 1},
+		{name: "concurrent", workers: wordlistWorkers(len(files))},
+	}
+
+	for _, bm := range benchmarks {
+		workers := bm.workers
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				dict := &Dictionary{config: cfg}
+				if err := dict.loadWordlistFiles(files, workers); err != nil {
+					b.Fatalf("Load failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestLowMemoryMatchesInMemory(t *testing.T) {
+	dir := t.TempDir()
+	first := writeWordlist(t, dir, "first.txt", "# comment\nAdmin\nlogin.php\nbackup/\nadmin\nindex.%EXT%\n")
+	second := writeWordlist(t, dir, "second.txt", "LOGIN.php\nuploads\nold.bak\n")
+
+	newConfig := func(lowMemory bool) *config.Config {
+		return &config.Config{
+			Dictionary: config.DictionaryConfig{
+				Wordlists:         []string{first, second},
+				DefaultExtensions: []string{"php", "html"},
+				ExcludeExtensions: []string{"bak"},
+				Prefixes:          []string{"_"},
+				Suffixes:          []string{"~"},
+				Lowercase:         true,
+				LowMemory:         lowMemory,
+			},
+		}
+	}
+
+	inMemory, err := NewDictionary(newConfig(false))
+	if err != nil {
+		t.Fatalf("NewDictionary (in-memory) failed: %v", err)
+	}
+	lowMemory, err := NewDictionary(newConfig(true))
+	if err != nil {
+		t.Fatalf("NewDictionary (low-memory) failed: %v", err)
+	}
+	if len(lowMemory.words) != 0 {
+		t.Errorf("Low-memory dictionary should not materialize words, got %d", len(lowMemory.words))
+	}
+
+	expected, err := inMemory.GeneratePaths()
+	if err != nil {
+		t.Fatalf("GeneratePaths failed: %v", err)
+	}
+
+	var streamed []string
+	if err := lowMemory.StreamPaths(func(path string) bool {
+		streamed = append(streamed, path)
+		return true
+	}); err != nil {
+		t.Fatalf("StreamPaths failed: %v", err)
+	}
+
+	if strings.Join(streamed, ",") != strings.Join(expected, ",") {
+		t.Errorf("Streamed paths = %v, want %v", streamed, expected)
+	}
+	if count := lowMemory.GetPathCount(); count != len(expected) {
+		t.Errorf("GetPathCount() = %d, want %d", count, len(expected))
+	}
+	if count := lowMemory.GetWordCount(); count != inMemory.GetWordCount() {
+		t.Errorf("GetWordCount() = %d, want %d", count, inMemory.GetWordCount())
+	}
+
+	// 回调返回false时停止
+	seen := 0
+	lowMemory.StreamPaths(func(string) bool {
+		seen++
+		return seen < 2
+	})
+	if seen != 2 {
+		t.Errorf("StreamPaths should stop when callback returns false, got %d callbacks", seen)
+	}
+}
+
+func TestLowMemoryDedupSpillsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	var words strings.Builder
+	for i := 0; i < 3000; i++ {
+		// 每个条目出现两次，大小写不同
+		fmt.Fprintf(&words, "dir%d\nDIR%d\n", i, i)
+	}
+	file := writeWordlist(t, dir, "words.txt", words.String())
+
+	newConfig := func(lowMemory bool) *config.Config {
+		return &config.Config{
+			Dictionary: config.DictionaryConfig{
+				Wordlists:         []string{file},
+				DefaultExtensions: []string{"php"},
+				ForceExtensions:   true,
+				Lowercase:         true,
+				LowMemory:         lowMemory,
+			},
+		}
+	}
+
+	inMemory, err := NewDictionary(newConfig(false))
+	if err != nil {
+		t.Fatalf("NewDictionary (in-memory) failed: %v", err)
+	}
+	expected, err := inMemory.GeneratePaths()
+	if err != nil {
+		t.Fatalf("GeneratePaths failed: %v", err)
+	}
+
+	lowMemory, err := NewDictionary(newConfig(true))
+	if err != nil {
+		t.Fatalf("NewDictionary (low-memory) failed: %v", err)
+	}
+	// 内存上限远小于路径数量，去重哈希会多次写入磁盘并归并
+	lowMemory.dedupLimit = 100
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	var streamed []string
+	if err := lowMemory.StreamPaths(func(path string) bool {
+		streamed = append(streamed, path)
+		return true
+	}); err != nil {
+		t.Fatalf("StreamPaths failed: %v", err)
+	}
+	if strings.Join(streamed, ",") != strings.Join(expected, ",") {
+		t.Errorf("Streamed %d paths, want %d matching the in-memory path", len(streamed), len(expected))
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Dedup files should be removed after streaming, found %d", len(entries))
+	}
+}
+
+func BenchmarkPathsMemory(b *testing.B) {
+	files := writeWordlists(b, b.TempDir(), 4, 50000)
+
+	for _, lowMemory := range []bool{false, true} {
+		name := "in-memory"
+		if lowMemory {
+			name = "low-memory"
+		}
+		cfg := &config.Config{
+			Dictionary: config.DictionaryConfig{
+				Wordlists:         files,
+				DefaultExtensions: []string{"php"},
+				ForceExtensions:   true,
+				LowMemory:         lowMemory,
+			},
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				dict, err := NewDictionary(cfg)
+				if err != nil {
+					b.Fatalf("NewDictionary failed: %v", err)
+				}
+				if err := dict.StreamPaths(func(string) bool { return true }); err != nil {
+					b.Fatalf("StreamPaths failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCaseInsensitiveDedup(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := writeWordlist(t, dir, "iis.txt", "Admin\nadmin\nADMIN\nLogin.aspx\nlogin.ASPX\nbackup\n")
+
+	for _, lowMemory := range []bool{false, true} {
+		t.Run(fmt.Sprintf("low-memory=%v", lowMemory), func(t *testing.T) {
+			dict, err := NewDictionary(&config.Config{
+				Dictionary: config.DictionaryConfig{
+					Wordlists:            []string{wordlist},
+					CaseInsensitiveDedup: true,
+					LowMemory:            lowMemory,
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+
+			var paths []string
+			if err := dict.StreamPaths(func(path string) bool {
+				paths = append(paths, path)
+				return true
+			}); err != nil {
+				t.Fatalf("StreamPaths failed: %v", err)
+			}
+
+			// 大小写变体折叠为首次出现的条目，并保留其原有大小写
+			if got, want := strings.Join(paths, ","), "Admin,Login.aspx,backup"; got != want {
+				t.Errorf("Paths = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNoExtensionsOnDirectories(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := writeWordlist(t, dir, "dirs.txt", "backup/\nindex\n")
+
+	tests := []struct {
+		name      string
+		force     bool
+		overwrite bool
+		expected  string
+	}{
+		{name: "force", force: true, expected: "backup/,index,index.php,index.html,index/"},
+		{name: "overwrite", overwrite: true, expected: "backup/,index,index.php,index.html"},
+		{name: "default", expected: "backup/,index"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dict, err := NewDictionary(&config.Config{
+				Dictionary: config.DictionaryConfig{
+					Wordlists:           []string{wordlist},
+					DefaultExtensions:   []string{"php", "html"},
+					ForceExtensions:     tt.force,
+					OverwriteExtensions: tt.overwrite,
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+
+			paths, err := dict.GeneratePaths()
+			if err != nil {
+				t.Fatalf("GeneratePaths failed: %v", err)
+			}
+			for _, path := range paths {
+				if strings.HasPrefix(path, "backup/") && path != "backup/" {
+					t.Errorf("Directory entry produced extension variant %q", path)
+				}
+			}
+			if got := strings.Join(paths, ","); got != tt.expected {
+				t.Errorf("Paths = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAppendSlash(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := writeWordlist(t, dir, "words.txt", "admin\nindex.php\nbackup/\nlogin.%EXT%\n")
+
+	tests := []struct {
+		name        string
+		appendSlash bool
+		expected    string
+	}{
+		{name: "enabled", appendSlash: true, expected: "admin,admin/,index.php,backup/,login.php"},
+		{name: "disabled", expected: "admin,index.php,backup/,login.php"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dict, err := NewDictionary(&config.Config{
+				Dictionary: config.DictionaryConfig{
+					Wordlists:         []string{wordlist},
+					DefaultExtensions: []string{"php"},
+					AppendSlash:       tt.appendSlash,
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+
+			paths, err := dict.GeneratePaths()
+			if err != nil {
+				t.Fatalf("GeneratePaths failed: %v", err)
+			}
+			if got := strings.Join(paths, ","); got != tt.expected {
+				t.Errorf("Paths = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRemoveExtensions(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		words    string
+		expected string
+	}{
+		{name: "没有扩展名", words: "admin\n", expected: "admin,admin.php,admin.html"},
+		{name: "移除后追加扩展名", words: "login.asp\nlogin\n", expected: "login,login.php,login.html"},
+		{name: "多个点只移除最后一个", words: "backup.tar.gz\n", expected: "backup.tar"},
+		{name: "目录条目", words: "uploads/\n", expected: "uploads/"},
+		{name: "隐藏文件", words: ".htaccess\nconfig/.env\n", expected: ".htaccess,config/.env"},
+		{name: "EXT关键字", words: "index.%EXT%\n", expected: "index.php,index.html"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wordlist := writeWordlist(t, dir, fmt.Sprintf("words%d.txt", i), tt.words)
+			dict, err := NewDictionary(&config.Config{
+				Dictionary: config.DictionaryConfig{
+					Wordlists:         []string{wordlist},
+					DefaultExtensions: []string{"php", "html"},
+					RemoveExtensions:  true,
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+
+			paths, err := dict.GeneratePaths()
+			if err != nil {
+				t.Fatalf("GeneratePaths failed: %v", err)
+			}
+			if got := strings.Join(paths, ","); got != tt.expected {
+				t.Errorf("Paths = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDefaultWordlist(t *testing.T) {
+	t.Run("内置字典", func(t *testing.T) {
+		t.Setenv("DIRSEARCH_HOME", "")
+		cfg := &config.Config{}
+		if !NeedsDefaultWordlist(cfg) {
+			t.Fatalf("Expected default wordlist to be needed without -w")
+		}
+		if got := DefaultWordlist(); got != EmbeddedWordlist {
+			t.Fatalf("DefaultWordlist() = %q, want %q", got, EmbeddedWordlist)
+		}
+
+		cfg.Dictionary.Wordlists = []string{DefaultWordlist()}
+		dict, err := NewDictionary(cfg)
+		if err != nil {
+			t.Fatalf("NewDictionary failed: %v", err)
+		}
+		paths, err := dict.GeneratePaths()
+		if err != nil {
+			t.Fatalf("GeneratePaths failed: %v", err)
+		}
+		if len(paths) == 0 || paths[0] != "admin" {
+			t.Errorf("Expected embedded default wordlist entries, got %v", paths)
+		}
+		for _, path := range paths {
+			if strings.HasPrefix(path, "#") {
+				t.Errorf("Comment line loaded as path: %q", path)
+			}
+		}
+	})
+
+	t.Run("DIRSEARCH_HOME", func(t *testing.T) {
+		home := t.TempDir()
+		expected := writeWordlist(t, filepath.Join(home, "wordlists"), "common.txt", "custom\n")
+		t.Setenv("DIRSEARCH_HOME", home)
+		if got := DefaultWordlist(); got != expected {
+			t.Errorf("DefaultWordlist() = %q, want %q", got, expected)
+		}
+	})
+
+	t.Run("已指定字典或源", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Dictionary.Wordlists = []string{"words.txt"}
+		if NeedsDefaultWordlist(cfg) {
+			t.Errorf("Expected -w to disable the default wordlist")
+		}
+		cfg = &config.Config{}
+		cfg.Dictionary.Source.Type = "url"
+		cfg.Dictionary.Source.URL = "https://example.com/words.txt"
+		if NeedsDefaultWordlist(cfg) {
+			t.Errorf("Expected a URL source to disable the default wordlist")
+		}
+	})
+}
+
+func TestTechExtensions(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := writeWordlist(t, dir, "words.txt", "index\n")
+
+	dict, err := NewDictionary(&config.Config{
+		Dictionary: config.DictionaryConfig{
+			Wordlists:         []string{wordlist},
+			DefaultExtensions: []string{"html", "php"},
+			ForceExtensions:   true,
+			Tech:              []string{"php"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+
+	paths, err := dict.GeneratePaths()
+	if err != nil {
+		t.Fatalf("GeneratePaths failed: %v", err)
+	}
+	for _, expected := range []string{"index.html", "index.php", "index.php3", "index.phtml"} {
+		if !slices.Contains(paths, expected) {
+			t.Errorf("Expected %q with --tech php, got %v", expected, paths)
+		}
+	}
+	if got := strings.Count(strings.Join(paths, ","), "index.php,"); got != 1 {
+		t.Errorf("Expected existing php extension not to be duplicated, got %v", paths)
+	}
+
+	if _, err := NewDictionary(&config.Config{
+		Dictionary: config.DictionaryConfig{Wordlists: []string{wordlist}, Tech: []string{"cobol"}},
+	}); err == nil {
+		t.Errorf("Expected error for unknown technology")
+	}
+}
+
+func TestDetectTech(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  http.Header
+		expected string
+	}{
+		{name: "PHP", headers: http.Header{"X-Powered-By": {"PHP/8.2.1"}}, expected: "php"},
+		{name: "IIS", headers: http.Header{"Server": {"Microsoft-IIS/10.0"}, "X-Aspnet-Version": {"4.0.30319"}}, expected: "asp"},
+		{name: "Java会话", headers: http.Header{"Set-Cookie": {"JSESSIONID=abc; Path=/"}}, expected: "java"},
+		{name: "未知", headers: http.Header{"Server": {"nginx"}}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(DetectTech(tt.headers), ","); got != tt.expected {
+				t.Errorf("DetectTech() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPriorityFile(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := writeWordlist(t, dir, "words.txt", "index\nimages/\nadmin\nabout\nconfig\n")
+	priority := writeWordlist(t, dir, "priority.txt", "# high value\nconfig\nadmin\nbackup\n")
+
+	for _, lowMemory := range []bool{false, true} {
+		dict, err := NewDictionary(&config.Config{
+			Dictionary: config.DictionaryConfig{
+				Wordlists:    []string{wordlist},
+				PriorityFile: priority,
+				LowMemory:    lowMemory,
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewDictionary failed: %v", err)
+		}
+
+		var paths []string
+		if err := dict.StreamPaths(func(path string) bool {
+			paths = append(paths, path)
+			return true
+		}); err != nil {
+			t.Fatalf("StreamPaths failed: %v", err)
+		}
+
+		// 高价值条目按文件顺序排在最前，字典中的同名条目不重复出现，其余条目保持字典顺序
+		want := "config,admin,backup,index,images/,about"
+		if got := strings.Join(paths, ","); got != want {
+			t.Errorf("low-memory=%v: paths = %s, want %s", lowMemory, got, want)
+		}
+		if got := strings.Join(dict.PriorityPaths(), ","); got != "config,admin,backup" {
+			t.Errorf("PriorityPaths() = %s, want config,admin,backup", got)
+		}
+	}
+
+	if _, err := NewDictionary(&config.Config{
+		Dictionary: config.DictionaryConfig{Wordlists: []string{wordlist}, PriorityFile: filepath.Join(dir, "missing.txt")},
+	}); err == nil {
+		t.Error("NewDictionary with a missing priority file succeeded, want error")
+	}
+}
@@ -0,0 +1,65 @@
+package dictionary
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MergedWords 获取合并去重后的字典条目（大小写转换已在加载时应用）
+func (dict *Dictionary) MergedWords() ([]string, error) {
+	var words []string
+	if err := dict.streamWords(func(word string) bool {
+		words = append(words, word)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	return dict.deduplicate(words), nil
+}
+
+// SaveMerged 将合并去重后的字典写入文件，返回写入的条目数
+// 先读取全部条目再写入同目录下的临时文件并重命名，输出文件同时是输入字典或读取失败时不会被截断
+func (dict *Dictionary) SaveMerged(path string) (int, error) {
+	words, err := dict.MergedWords()
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create merged wordlist: %w", err)
+	}
+	tmpName := file.Name()
+
+	writer := bufio.NewWriter(file)
+	for _, word := range words {
+		if _, err := writer.WriteString(word + "\n"); err != nil {
+			file.Close()
+			os.Remove(tmpName)
+			return 0, fmt.Errorf("failed to write merged wordlist: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(tmpName)
+		return 0, fmt.Errorf("failed to write merged wordlist: %w", err)
+	}
+	// 临时文件默认只有所有者可读，与普通创建的文件保持一致
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		os.Remove(tmpName)
+		return 0, fmt.Errorf("failed to write merged wordlist: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpName)
+		return 0, fmt.Errorf("failed to close merged wordlist: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return 0, fmt.Errorf("failed to replace merged wordlist: %w", err)
+	}
+	return len(words), nil
+}
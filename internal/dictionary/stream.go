@@ -0,0 +1,143 @@
+package dictionary
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+)
+
+// streamWords 按顺序回调每个字典条目，回调返回false时停止
+// 低内存模式下逐行读取字典文件，不在内存中保存全部条目
+func (dict *Dictionary) streamWords(fn func(word string) bool) error {
+	// 高价值条目排在所有字典条目之前
+	for _, word := range dict.priority {
+		if !fn(word) {
+			return nil
+		}
+	}
+
+	for _, path := range dict.files {
+		stopped, err := dict.streamWordlistFile(path, fn)
+		if err != nil {
+			return fmt.Errorf("failed to stream wordlist file %s: %w", path, err)
+		}
+		if stopped {
+			return nil
+		}
+	}
+
+	for _, word := range dict.words {
+		if !fn(word) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// streamWordlistFile 逐行读取单个字典文件，返回是否被回调中止
+func (dict *Dictionary) streamWordlistFile(path string, fn func(word string) bool) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open wordlist file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+
+		// 跳过空行和注释
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		if !fn(dict.transformCase(word)) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// StreamPaths 按顺序回调每个去重后的扫描路径，回调返回false时停止
+// 低内存模式下边读取边生成路径，用64位哈希去重（不保存路径本身，哈希碰撞时后一个路径会被跳过），
+// 哈希数量超过内存上限后写入临时文件
+func (dict *Dictionary) StreamPaths(fn func(path string) bool) (err error) {
+	if !dict.config.Dictionary.LowMemory {
+		paths, err := dict.GeneratePaths()
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if !fn(path) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	seen := newDedupSet(dict.dedupLimit)
+	defer func() {
+		if closeErr := seen.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to remove dedup file: %w", closeErr)
+		}
+	}()
+
+	var dedupErr error
+	var buf []string
+	err = dict.streamWords(func(word string) bool {
+		// 跳过被排除的扩展名
+		if dict.shouldExcludeWord(word) {
+			return true
+		}
+
+		buf = dict.appendWordPaths(buf[:0], word)
+		for _, path := range buf {
+			added, err := seen.Add(hashPath(dict.dedupKey(path)))
+			if err != nil {
+				dedupErr = err
+				return false
+			}
+			if !added {
+				continue
+			}
+			if !fn(path) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return dedupErr
+}
+
+// CountPaths 统计去重后的扫描路径数量
+func (dict *Dictionary) CountPaths() (int, error) {
+	count := 0
+	err := dict.StreamPaths(func(string) bool {
+		count++
+		return true
+	})
+	return count, err
+}
+
+// hasWords 字典是否至少包含一个条目
+func (dict *Dictionary) hasWords() bool {
+	found := false
+	dict.streamWords(func(string) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// hashPath 计算路径的64位哈希，用于低内存去重
+func hashPath(path string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(path))
+	return h.Sum64()
+}
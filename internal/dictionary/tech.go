@@ -0,0 +1,120 @@
+package dictionary
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// techExtensions 技术栈对应的常用扩展名
+var techExtensions = map[string][]string{
+	"php":        {"php", "php3", "php4", "php5", "phtml", "inc"},
+	"asp":        {"asp", "aspx", "ashx", "asmx", "axd", "config"},
+	"java":       {"jsp", "jspx", "do", "action"},
+	"python":     {"py"},
+	"ruby":       {"rb", "erb"},
+	"perl":       {"pl", "cgi"},
+	"coldfusion": {"cfm", "cfc"},
+	"node":       {"js", "json"},
+}
+
+// techAliases 技术栈别名
+var techAliases = map[string]string{
+	"iis":     "asp",
+	"aspx":    "asp",
+	"asp.net": "asp",
+	"dotnet":  "asp",
+	"jsp":     "java",
+	"tomcat":  "java",
+	"cfm":     "coldfusion",
+	"nodejs":  "node",
+	"express": "node",
+}
+
+// TechNames 返回支持的技术栈名称
+func TechNames() []string {
+	names := make([]string, 0, len(techExtensions))
+	for name := range techExtensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TechExtensions 返回技术栈对应的扩展名，支持逗号分隔的多个技术栈
+func TechExtensions(techs []string) ([]string, error) {
+	var extensions []string
+	for _, entry := range techs {
+		for _, tech := range strings.Split(entry, ",") {
+			tech = strings.ToLower(strings.TrimSpace(tech))
+			if tech == "" {
+				continue
+			}
+			if alias, ok := techAliases[tech]; ok {
+				tech = alias
+			}
+			exts, ok := techExtensions[tech]
+			if !ok {
+				return nil, fmt.Errorf("unknown technology %q (supported: %s)", tech, strings.Join(TechNames(), ", "))
+			}
+			extensions = append(extensions, exts...)
+		}
+	}
+	return extensions, nil
+}
+
+// DetectTech 根据响应头（Server、X-Powered-By、会话Cookie等）推断技术栈
+func DetectTech(headers http.Header) []string {
+	var signals []string
+	for _, name := range []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version", "Set-Cookie"} {
+		for _, value := range headers.Values(name) {
+			signals = append(signals, strings.ToLower(name+": "+value))
+		}
+	}
+	signal := strings.Join(signals, "\n")
+
+	rules := []struct {
+		tech    string
+		markers []string
+	}{
+		{tech: "php", markers: []string{"php", "phpsessid"}},
+		{tech: "asp", markers: []string{"microsoft-iis", "asp.net", "x-aspnet", "aspsessionid"}},
+		{tech: "java", markers: []string{"jsessionid", "servlet", "jsp", "tomcat", "jetty", "jboss", "glassfish"}},
+		{tech: "coldfusion", markers: []string{"coldfusion", "cfid", "cftoken"}},
+		{tech: "python", markers: []string{"python", "werkzeug", "gunicorn", "django"}},
+		{tech: "ruby", markers: []string{"phusion passenger", "ruby", "_rails"}},
+		{tech: "node", markers: []string{"express"}},
+	}
+
+	var techs []string
+	for _, rule := range rules {
+		for _, marker := range rule.markers {
+			if strings.Contains(signal, marker) {
+				techs = append(techs, rule.tech)
+				break
+			}
+		}
+	}
+	return techs
+}
+
+// AddExtensions 向字典追加扩展名（忽略已存在的），返回新增的扩展名
+func (dict *Dictionary) AddExtensions(extensions []string) []string {
+	seen := make(map[string]bool, len(dict.extensions))
+	for _, ext := range dict.extensions {
+		seen[strings.ToLower(ext)] = true
+	}
+
+	var added []string
+	for _, ext := range extensions {
+		ext = strings.TrimPrefix(strings.TrimSpace(ext), ".")
+		if ext == "" || seen[strings.ToLower(ext)] {
+			continue
+		}
+		seen[strings.ToLower(ext)] = true
+		dict.extensions = append(dict.extensions, ext)
+		added = append(added, ext)
+	}
+	return added
+}
@@ -0,0 +1,303 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestSaveCSVBOM(t *testing.T) {
+	results := []ScanResult{
+		{URL: "http://example.com/", Path: "admin", StatusCode: 200, Title: "管理后台"},
+	}
+
+	tests := []struct {
+		name    string
+		bom     bool
+		wantBOM bool
+	}{
+		{name: "默认不写入BOM", bom: false, wantBOM: false},
+		{name: "启用csv-bom", bom: true, wantBOM: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, _ := NewReporter(&config.Config{
+				Output: config.OutputConfig{ReportFormat: "csv", CSVBOM: tt.bom},
+			})
+			filename := filepath.Join(t.TempDir(), "report.csv")
+			if err := reporter.SaveResults(results, filename); err != nil {
+				t.Fatalf("SaveResults failed: %v", err)
+			}
+
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			if got := bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}); got != tt.wantBOM {
+				t.Errorf("BOM present = %v, want %v", got, tt.wantBOM)
+			}
+
+			records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM)))).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to parse CSV: %v", err)
+			}
+			if len(records) != 2 || records[0][0] != "URL" || records[1][4] != "管理后台" {
+				t.Errorf("Unexpected CSV content: %v", records)
+			}
+		})
+	}
+}
+
+func TestSaveResultsMultipleFormats(t *testing.T) {
+	results := []ScanResult{
+		{URL: "http://example.com/", Path: "admin", StatusCode: 200, Title: "Admin"},
+	}
+
+	reporter, _ := NewReporter(&config.Config{
+		Output: config.OutputConfig{ReportFormat: "json, html"},
+	})
+	dir := t.TempDir()
+	if err := reporter.SaveResults(results, filepath.Join(dir, "report.json")); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "report.html,report.json" {
+		t.Fatalf("Got files %v, want report.html and report.json", names)
+	}
+
+	loaded, err := LoadJSONReport(filepath.Join(dir, "report.json"))
+	if err != nil || len(loaded) != 1 || loaded[0].Path != "admin" {
+		t.Errorf("Unexpected JSON report: %+v, %v", loaded, err)
+	}
+	html, err := os.ReadFile(filepath.Join(dir, "report.html"))
+	if err != nil || !strings.Contains(string(html), "admin") {
+		t.Errorf("Unexpected HTML report: %v", err)
+	}
+}
+
+func TestReportFilenames(t *testing.T) {
+	names := reportFilenames("out/scan.txt", []string{"plain", "simple", "csv"})
+	expected := map[string]string{
+		"plain":  "out/scan.txt",
+		"simple": "out/scan-simple.txt",
+		"csv":    "out/scan.csv",
+	}
+	for format, want := range expected {
+		if names[format] != want {
+			t.Errorf("reportFilenames()[%s] = %q, want %q", format, names[format], want)
+		}
+	}
+
+	if _, err := ParseReportFormats("json,xlsx"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestSaveGrepable(t *testing.T) {
+	results := []ScanResult{
+		{URL: "http://example.com/", Path: "admin", StatusCode: 200, Size: 1234, Title: "Admin | Panel"},
+		{URL: "http://example.com/", Path: "login", StatusCode: 302, Size: 0},
+		{URL: "http://example.com/", Path: "api/", StatusCode: 403, Size: 56, Title: "Forbidden\nAccess"},
+	}
+
+	reporter, _ := NewReporter(&config.Config{
+		Output: config.OutputConfig{ReportFormat: "grepable"},
+	})
+	filename := filepath.Join(t.TempDir(), "report.txt")
+	if err := reporter.SaveResults(results, filename); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	want := []string{
+		"200|1234|http://example.com/admin|Admin   Panel",
+		"302|0|http://example.com/login|",
+		"403|56|http://example.com/api/|Forbidden Access",
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Got grepable report:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	for _, line := range lines {
+		if fields := strings.Split(line, "|"); len(fields) != 4 {
+			t.Errorf("Line %q has %d fields, want 4", line, len(fields))
+		}
+	}
+}
+
+func TestMaxTitleLength(t *testing.T) {
+	longTitle := strings.Repeat("管理后台", 10)
+	results := []ScanResult{
+		{URL: "http://example.com/", Path: "admin", StatusCode: 200, Title: longTitle},
+		{URL: "http://example.com/", Path: "login", StatusCode: 200, Title: "Login"},
+	}
+
+	reporter, _ := NewReporter(&config.Config{
+		Output: config.OutputConfig{ReportFormat: "csv,json", MaxTitleLength: 8},
+	})
+	dir := t.TempDir()
+	if err := reporter.SaveResults(results, filepath.Join(dir, "report")); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "report.csv"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if got, want := records[1][4], "管理后台管理后台..."; got != want {
+		t.Errorf("CSV title = %q, want %q", got, want)
+	}
+	if got := records[2][4]; got != "Login" {
+		t.Errorf("Short CSV title = %q, want unchanged", got)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "report.json"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var decoded []ScanResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].Title != longTitle {
+		t.Errorf("JSON report should keep the full title, got %+v", decoded)
+	}
+}
+
+func TestTruncateTitle(t *testing.T) {
+	tests := []struct {
+		title     string
+		maxLength int
+		want      string
+	}{
+		{title: "Dashboard", maxLength: 0, want: "Dashboard"},
+		{title: "Dashboard", maxLength: 9, want: "Dashboard"},
+		{title: "Dashboard", maxLength: 4, want: "Dash..."},
+		{title: "管理后台", maxLength: 2, want: "管理..."},
+	}
+	for _, tt := range tests {
+		if got := TruncateTitle(tt.title, tt.maxLength); got != tt.want {
+			t.Errorf("TruncateTitle(%q, %d) = %q, want %q", tt.title, tt.maxLength, got, tt.want)
+		}
+	}
+}
+
+func TestScanIDInReportAndWebhook(t *testing.T) {
+	const scanID = "3f2a9c1e-7b4d-4e8f-9a6b-0c1d2e3f4a5b"
+
+	payloads := make(chan WebhookPayload, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+		payloads <- payload
+	}))
+	defer hook.Close()
+
+	cfg := &config.Config{}
+	cfg.General.ScanID = scanID
+	cfg.Output.ReportFormat = "plain,xml,json,jsonl"
+	cfg.Output.Webhook = hook.URL
+
+	result := ScanResult{URL: "http://example.com/", Path: "admin", StatusCode: 200}
+	reporter, _ := NewReporter(cfg)
+	dir := t.TempDir()
+	if err := reporter.SaveResults([]ScanResult{result}, filepath.Join(dir, "report")); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+
+	plain, err := os.ReadFile(filepath.Join(dir, "report.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(plain), "Scan ID: "+scanID+"\n") {
+		t.Errorf("Plain report header does not contain the scan ID:\n%s", plain)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "report.xml"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var doc xmlResults
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to parse XML report: %v", err)
+	}
+	if doc.ScanID != scanID {
+		t.Errorf("XML report scan_id = %q, want %q", doc.ScanID, scanID)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "report.json"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("Failed to parse JSON report: %v", err)
+	}
+	if len(records) != 1 || records[0]["scan_id"] != scanID {
+		t.Errorf("JSON report records = %v, want scan_id %q", records, scanID)
+	}
+	// --retry-errors和merge读取JSON报告时忽略scan_id字段
+	loaded, err := LoadJSONReport(filepath.Join(dir, "report.json"))
+	if err != nil {
+		t.Fatalf("LoadJSONReport failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].URL != result.URL {
+		t.Errorf("LoadJSONReport = %+v, want %+v", loaded, result)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "report.jsonl"))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Failed to parse JSONL report: %v", err)
+	}
+	if record["scan_id"] != scanID {
+		t.Errorf("JSONL report scan_id = %v, want %q", record["scan_id"], scanID)
+	}
+
+	webhook, err := NewWebhookSender(cfg)
+	if err != nil {
+		t.Fatalf("NewWebhookSender failed: %v", err)
+	}
+	webhook.Send(result)
+	webhook.Flush(5 * time.Second)
+
+	select {
+	case payload := <-payloads:
+		if payload.ScanID != scanID {
+			t.Errorf("Webhook payload scan_id = %q, want %q", payload.ScanID, scanID)
+		}
+	default:
+		t.Fatal("Webhook payload was not delivered")
+	}
+}
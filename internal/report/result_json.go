@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// scanResultJSON ScanResult的JSON表示，错误以字符串保存，JSON报告中的每条记录附带扫描ID
+type scanResultJSON struct {
+	scanResultAlias
+	Error  string `json:"Error,omitempty"`
+	ScanID string `json:"scan_id,omitempty"`
+}
+
+// scanResultAlias 避免MarshalJSON递归调用
+type scanResultAlias ScanResult
+
+// MarshalJSON 将错误序列化为字符串（error接口默认会被序列化为空对象）
+func (r ScanResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.jsonRecord(""))
+}
+
+// jsonRecord 返回写入JSON报告的记录，scanID为空时省略scan_id字段
+func (r ScanResult) jsonRecord(scanID string) scanResultJSON {
+	out := scanResultJSON{scanResultAlias: scanResultAlias(r), ScanID: scanID}
+	out.scanResultAlias.Error = nil
+	if r.Error != nil {
+		out.Error = r.Error.Error()
+	}
+	return out
+}
+
+// jsonRecords 返回写入JSON报告的记录列表，每条记录附带扫描ID
+func jsonRecords(results []ScanResult, scanID string) []scanResultJSON {
+	records := make([]scanResultJSON, 0, len(results))
+	for _, result := range results {
+		records = append(records, result.jsonRecord(scanID))
+	}
+	return records
+}
+
+// UnmarshalJSON 从字符串恢复错误
+func (r *ScanResult) UnmarshalJSON(data []byte) error {
+	var in scanResultJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = ScanResult(in.scanResultAlias)
+	r.Error = nil
+	if in.Error != "" {
+		r.Error = errors.New(in.Error)
+	}
+	return nil
+}
+
+// LoadJSONReport 读取JSON格式的扫描报告
+func LoadJSONReport(filename string) ([]ScanResult, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var results []ScanResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report %s: %w", filename, err)
+	}
+	return results, nil
+}
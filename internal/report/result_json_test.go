@@ -0,0 +1,36 @@
+package report
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestJSONReportKeepsErrors(t *testing.T) {
+	results := []ScanResult{
+		{URL: "http://example.com/", Path: "admin", StatusCode: 200, Title: "Admin"},
+		{URL: "http://example.com/", Path: "slow", Error: errors.New("request failed: timeout")},
+	}
+
+	reporter, _ := NewReporter(&config.Config{Output: config.OutputConfig{ReportFormat: "json"}})
+	filename := filepath.Join(t.TempDir(), "report.json")
+	if err := reporter.SaveResults(results, filename); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+
+	loaded, err := LoadJSONReport(filename)
+	if err != nil {
+		t.Fatalf("LoadJSONReport failed: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Loaded %d results, want 2", len(loaded))
+	}
+	if loaded[0].Error != nil || loaded[0].Title != "Admin" || loaded[0].StatusCode != 200 {
+		t.Errorf("loaded[0] = %+v, want admin result without error", loaded[0])
+	}
+	if loaded[1].Error == nil || loaded[1].Error.Error() != "request failed: timeout" {
+		t.Errorf("loaded[1].Error = %v, want %q", loaded[1].Error, "request failed: timeout")
+	}
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StreamWriter 流式报告写入器：结果按行写入缓冲区，每隔interval刷新到磁盘，关闭时刷新剩余内容。
+// interval不大于0时每条结果写入后立即刷新
+type StreamWriter struct {
+	mu       sync.Mutex
+	file     *os.File
+	writer   *bufio.Writer
+	interval time.Duration
+	done     chan struct{}
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+// NewStreamWriter 创建流式报告写入器并启动定时刷新协程
+func NewStreamWriter(filename string, interval time.Duration) (*StreamWriter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+
+	w := &StreamWriter{
+		file:     file,
+		writer:   bufio.NewWriter(file),
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	if interval > 0 {
+		w.wg.Add(1)
+		go w.flushLoop()
+	}
+	return w, nil
+}
+
+// flushLoop 定时刷新缓冲区直到写入器关闭
+func (w *StreamWriter) flushLoop() {
+	defer w.wg.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("StreamWriter flushLoop panic recovered: %v", r)
+		}
+	}()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				log.Printf("Warning: Failed to flush stream report: %v", err)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// WriteResult 以JSON Lines格式写入一条结果
+func (w *StreamWriter) WriteResult(result ScanResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return fmt.Errorf("stream writer is closed")
+	}
+	if _, err := w.writer.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if w.interval <= 0 {
+		return w.writer.Flush()
+	}
+	return nil
+}
+
+// Flush 将缓冲区内容写入磁盘
+func (w *StreamWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	return w.writer.Flush()
+}
+
+// Close 停止定时刷新，写入剩余内容并关闭文件
+func (w *StreamWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	err := w.writer.Flush()
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// StreamFilename 返回报告格式列表中format对应的文件名，列表不包含该格式时返回false
+func StreamFilename(filename, spec, format string) (string, bool) {
+	formats, err := ParseReportFormats(spec)
+	if err != nil {
+		return "", false
+	}
+	for _, f := range formats {
+		if f != format {
+			continue
+		}
+		if len(formats) == 1 {
+			if ext := reportExtensions[format]; !strings.HasSuffix(filename, ext) {
+				filename += ext
+			}
+			return filename, true
+		}
+		return reportFilenames(filename, formats)[format], true
+	}
+	return "", false
+}
+
+// saveJSONL 保存JSON Lines格式报告，每行一个结果
+func (r *Reporter) saveJSONL(results []ScanResult, filename string) error {
+	if !strings.HasSuffix(filename, ".jsonl") {
+		filename += ".jsonl"
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range jsonRecords(results, r.scanID()) {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+	}
+	return writer.Flush()
+}
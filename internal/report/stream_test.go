@@ -0,0 +1,81 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamWriterFlush(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "results.jsonl")
+	countLines := func() int {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Failed to read stream report: %v", err)
+		}
+		return strings.Count(string(data), "\n")
+	}
+
+	interval := 200 * time.Millisecond
+	writer, err := NewStreamWriter(filename, interval)
+	if err != nil {
+		t.Fatalf("NewStreamWriter failed: %v", err)
+	}
+	defer writer.Close()
+
+	if err := writer.WriteResult(ScanResult{URL: "http://example.com/", Path: "admin", StatusCode: 200}); err != nil {
+		t.Fatalf("WriteResult failed: %v", err)
+	}
+	if got := countLines(); got != 0 {
+		t.Errorf("Got %d lines before the flush interval, expected writes to stay buffered", got)
+	}
+
+	// 间隔到期后缓冲区被刷新
+	deadline := time.Now().Add(5 * interval)
+	for countLines() != 1 && time.Now().Before(deadline) {
+		time.Sleep(interval / 10)
+	}
+	if got := countLines(); got != 1 {
+		t.Fatalf("Got %d lines after the flush interval, want 1", got)
+	}
+
+	// 关闭时写入剩余的结果
+	if err := writer.WriteResult(ScanResult{URL: "http://example.com/", Path: "login", StatusCode: 403}); err != nil {
+		t.Fatalf("WriteResult failed: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := countLines(); got != 2 {
+		t.Errorf("Got %d lines after Close, want 2", got)
+	}
+	if err := writer.WriteResult(ScanResult{Path: "late"}); err == nil {
+		t.Error("Expected WriteResult after Close to fail")
+	}
+}
+
+func TestStreamFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		spec     string
+		want     string
+		wantOK   bool
+	}{
+		{name: "单一格式追加扩展名", filename: "out", spec: "jsonl", want: "out.jsonl", wantOK: true},
+		{name: "单一格式保留文件名", filename: "out.jsonl", spec: "jsonl", want: "out.jsonl", wantOK: true},
+		{name: "多个格式派生文件名", filename: "out.json", spec: "json,jsonl", want: "out.jsonl", wantOK: true},
+		{name: "不包含jsonl", filename: "out", spec: "json,html", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := StreamFilename(tt.filename, tt.spec, "jsonl")
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("StreamFilename(%q, %q) = %q, %v, want %q, %v", tt.filename, tt.spec, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
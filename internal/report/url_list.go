@@ -0,0 +1,25 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// SaveURLList 将URL逐行写入文件，不带任何其他信息，便于传给其他工具
+func SaveURLList(urls []string, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, u := range urls {
+		fmt.Fprintln(w, u)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write URL list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+const (
+	webhookQueueSize = 256              // 待发送结果的缓冲数量，队列满时丢弃新结果
+	webhookRetries   = 3                // 每个结果的最大发送次数
+	webhookTimeout   = 10 * time.Second // 单次发送超时
+)
+
+// WebhookPayload 发送到webhook的结果
+type WebhookPayload struct {
+	ScanID         string    `json:"scan_id,omitempty"`
+	URL            string    `json:"url"`
+	Path           string    `json:"path"`
+	StatusCode     int       `json:"status_code"`
+	Size           int64     `json:"size"`
+	Title          string    `json:"title,omitempty"`
+	Redirect       string    `json:"redirect,omitempty"`
+	IsDirectory    bool      `json:"is_directory"`
+	AllowedMethods []string  `json:"allowed_methods,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// WebhookSender 扫描过程中将发现的结果以JSON POST到webhook，通过带缓冲的队列异步发送，不阻塞扫描
+type WebhookSender struct {
+	url     string
+	scanID  string
+	headers http.Header
+	client  *http.Client
+	backoff time.Duration // 重试间隔，按重试次数递增
+	queue   chan ScanResult
+	pending sync.WaitGroup
+	dropped atomic.Int64
+	failed  atomic.Int64
+}
+
+// NewWebhookSender 根据--webhook和--webhook-header创建发送器并启动发送协程
+func NewWebhookSender(cfg *config.Config) (*WebhookSender, error) {
+	parsed, err := url.Parse(cfg.Output.Webhook)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", cfg.Output.Webhook)
+	}
+
+	headers := make(http.Header)
+	for _, line := range cfg.Output.WebhookHeaders {
+		name, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid webhook header %q, expected \"Name: value\"", line)
+		}
+		headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	w := &WebhookSender{
+		url:     cfg.Output.Webhook,
+		scanID:  cfg.General.ScanID,
+		headers: headers,
+		client:  &http.Client{Timeout: webhookTimeout},
+		backoff: 500 * time.Millisecond,
+		queue:   make(chan ScanResult, webhookQueueSize),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Send 将结果加入发送队列，队列已满时丢弃并计数，不阻塞调用方
+func (w *WebhookSender) Send(result ScanResult) {
+	w.pending.Add(1)
+	select {
+	case w.queue <- result:
+	default:
+		w.pending.Done()
+		if w.dropped.Add(1) == 1 {
+			log.Printf("Warning: Webhook queue is full, dropping results")
+		}
+	}
+}
+
+// Flush 等待队列中的结果发送完毕，超时后返回，并报告未送达的结果数量
+func (w *WebhookSender) Flush(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		w.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Printf("Warning: Timed out waiting for webhook deliveries")
+	}
+
+	if failed, dropped := w.failed.Load(), w.dropped.Load(); failed > 0 || dropped > 0 {
+		fmt.Printf("Webhook: %d 个结果发送失败，%d 个结果因队列已满被丢弃\n", failed, dropped)
+	}
+}
+
+// run 发送协程，依次发送队列中的结果
+func (w *WebhookSender) run() {
+	for result := range w.queue {
+		if err := w.post(result); err != nil {
+			w.failed.Add(1)
+			log.Printf("Warning: Failed to send %s%s to webhook: %v", result.URL, result.Path, err)
+		}
+		w.pending.Done()
+	}
+}
+
+// post 发送单个结果，失败时重试
+func (w *WebhookSender) post(result ScanResult) error {
+	body, err := json.Marshal(WebhookPayload{
+		ScanID:         w.scanID,
+		URL:            result.URL,
+		Path:           result.Path,
+		StatusCode:     result.StatusCode,
+		Size:           result.Size,
+		Title:          result.Title,
+		Redirect:       result.Redirect,
+		IsDirectory:    result.IsDirectory,
+		AllowedMethods: result.AllowedMethods,
+		Timestamp:      result.Timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = w.postOnce(body)
+		if err == nil || attempt >= webhookRetries {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * w.backoff)
+	}
+}
+
+// postOnce 发送一次请求，非2xx响应视为失败
+func (w *WebhookSender) postOnce(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, values := range w.headers {
+		req.Header[name] = values
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
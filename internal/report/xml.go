@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// xmlResults XML报告的根元素
+type xmlResults struct {
+	XMLName xml.Name    `xml:"results"`
+	ScanID  string      `xml:"scan_id,attr,omitempty"`
+	Results []xmlResult `xml:"result"`
+}
+
+// xmlResult XML报告中的单个结果，错误以字符串保存
+type xmlResult struct {
+	URL        string `xml:"URL"`
+	Path       string `xml:"Path"`
+	StatusCode int    `xml:"StatusCode"`
+	Size       int64  `xml:"Size"`
+	Title      string `xml:"Title"`
+	Redirect   string `xml:"Redirect"`
+	Error      string `xml:"Error,omitempty"`
+}
+
+// saveXML 保存XML格式报告，文本字段由encoding/xml转义，非法的XML字符替换为U+FFFD
+func (r *Reporter) saveXML(results []ScanResult, filename string) error {
+	if !strings.HasSuffix(filename, ".xml") {
+		filename += ".xml"
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	doc := xmlResults{ScanID: r.scanID(), Results: make([]xmlResult, 0, len(results))}
+	for _, result := range results {
+		item := xmlResult{
+			URL:        result.URL,
+			Path:       result.Path,
+			StatusCode: result.StatusCode,
+			Size:       result.Size,
+			Title:      result.Title,
+			Redirect:   result.Redirect,
+		}
+		if result.Error != nil {
+			item.Error = result.Error.Error()
+		}
+		doc.Results = append(doc.Results, item)
+	}
+
+	writer := bufio.NewWriter(file)
+	if _, err := writer.WriteString(xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	if _, err := writer.WriteString("\n"); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestSaveXML(t *testing.T) {
+	results := []ScanResult{
+		{URL: "http://example.com/", Path: "admin", StatusCode: 200, Size: 1234, Title: "Admin <Panel> & \"Login\"\x00"},
+		{URL: "http://example.com/", Path: "old", StatusCode: 301, Redirect: "http://example.com/new?a=1&b=2"},
+		{URL: "http://example.com/", Path: "down", Error: errors.New("connection refused")},
+	}
+
+	reporter, _ := NewReporter(&config.Config{
+		Output: config.OutputConfig{ReportFormat: "xml"},
+	})
+	filename := filepath.Join(t.TempDir(), "report")
+	if err := reporter.SaveResults(results, filename); err != nil {
+		t.Fatalf("SaveResults failed: %v", err)
+	}
+
+	// 未带扩展名时追加.xml
+	data, err := os.ReadFile(filename + ".xml")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	var doc xmlResults
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Report is not well-formed XML: %v\n%s", err, data)
+	}
+	if len(doc.Results) != 3 {
+		t.Fatalf("Got %d results, want 3", len(doc.Results))
+	}
+
+	if got, want := doc.Results[0].Title, "Admin <Panel> & \"Login\"\uFFFD"; got != want {
+		t.Errorf("Title = %q, want %q", got, want)
+	}
+	if doc.Results[0].StatusCode != 200 || doc.Results[0].Size != 1234 || doc.Results[0].Error != "" {
+		t.Errorf("Unexpected first result: %+v", doc.Results[0])
+	}
+	if got := doc.Results[1].Redirect; got != "http://example.com/new?a=1&b=2" {
+		t.Errorf("Redirect = %q", got)
+	}
+	if got := doc.Results[2].Error; got != "connection refused" {
+		t.Errorf("Error = %q, want connection refused", got)
+	}
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+
+	"dirsearch-go/internal/utils"
+)
+
+// backupVariants 生成已发现文件的常见备份/临时文件路径：内置的.bak、~、.old和Vim交换文件.name.swp，以及自定义后缀
+func backupVariants(path string, suffixes []string) []string {
+	dir, base := "", path
+	if idx := strings.LastIndex(path, "/"); idx != -1 {
+		dir, base = path[:idx+1], path[idx+1:]
+	}
+
+	variants := []string{
+		path + ".bak",
+		path + "~",
+		path + ".old",
+		dir + "." + base + ".swp",
+	}
+	for _, suffix := range suffixes {
+		suffix = strings.TrimSpace(suffix)
+		if suffix != "" {
+			variants = append(variants, path+suffix)
+		}
+	}
+	return variants
+}
+
+// isBackupCandidate 判断结果是否为发现的文件（2xx、未被过滤、路径末段带扩展名且不是目录）
+func (s *Scanner) isBackupCandidate(result ScanResult) bool {
+	if result.Error != nil || result.StatusCode < 200 || result.StatusCode >= 300 {
+		return false
+	}
+	if result.Path == "" || strings.HasSuffix(result.Path, "/") {
+		return false
+	}
+
+	base := result.Path[strings.LastIndex(result.Path, "/")+1:]
+	if !strings.Contains(strings.TrimPrefix(base, "."), ".") {
+		return false
+	}
+	return s.shouldIncludeResult(result)
+}
+
+// backupTasks 为发现的文件安排备份变体的扫描任务，跳过已扫描过的路径
+func (s *Scanner) backupTasks(results []ScanResult) []ScanTask {
+	scanned := make(map[string]bool, len(results))
+	for _, result := range results {
+		scanned[resultKey(result.URL, result.Path)] = true
+	}
+
+	var tasks []ScanTask
+	for _, result := range results {
+		if !s.isBackupCandidate(result) {
+			continue
+		}
+		for _, variant := range backupVariants(result.Path, s.config.General.BackupSuffixes) {
+			key := resultKey(result.URL, variant)
+			if scanned[key] {
+				continue
+			}
+			scanned[key] = true
+			tasks = append(tasks, ScanTask{Target: result.URL, Path: variant})
+		}
+	}
+	return tasks
+}
+
+// runBackupChecks 对发现的文件扫描常见的备份/临时文件变体，备份结果本身不再展开
+func (s *Scanner) runBackupChecks(results []ScanResult, totalPaths, recursionLevel int) (_ []ScanResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("runBackupChecks panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
+		}
+	}()
+
+	tasks := s.backupTasks(results)
+	if len(tasks) == 0 || s.ctx.Err() != nil {
+		return nil, nil
+	}
+
+	fmt.Printf("\n检查 %d 个备份文件变体...\n", len(tasks))
+	s.statusDisplay.SetTotalPaths(totalPaths + len(tasks))
+	source := func(send func(task ScanTask) bool) error {
+		for _, task := range tasks {
+			if !send(task) {
+				return nil
+			}
+		}
+		return nil
+	}
+	return s.runTasks(source, recursionLevel, nil), nil
+}
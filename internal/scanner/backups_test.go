@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/utils"
+)
+
+func TestCheckBackupsSchedulesVariants(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, strings.TrimPrefix(r.URL.Path, "/"))
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/config.php", "/admin/", "/config.php.bak":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.CheckBackups = true
+	cfg.General.BackupSuffixes = []string{".zip"}
+	s := newTestScanner(t, cfg)
+
+	results, err := s.executeScan([]string{server.URL + "/"}, []string{"config.php", "admin/", "missing.php"}, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(requested)
+	expected := []string{
+		".config.php.swp",
+		"admin/",
+		"config.php",
+		"config.php.bak",
+		"config.php.old",
+		"config.php.zip",
+		"config.php~",
+		"missing.php",
+	}
+	if strings.Join(requested, ",") != strings.Join(expected, ",") {
+		t.Errorf("Requested %v, want %v", requested, expected)
+	}
+
+	found := false
+	for _, result := range results {
+		if result.Path == "config.php.bak" && result.StatusCode == http.StatusOK {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected config.php.bak in results, got %+v", results)
+	}
+}
+
+func TestBackupChecksPanicReturnsError(t *testing.T) {
+	s := newTestScanner(t, &config.Config{})
+	// 没有状态显示器时更新总路径数会panic
+	s.statusDisplay = nil
+
+	found := []ScanResult{{URL: "http://127.0.0.1/", Path: "index.php", StatusCode: http.StatusOK}}
+	if _, err := s.runBackupChecks(found, 1, 0); !errors.Is(err, utils.ErrPanic) {
+		t.Errorf("runBackupChecks error = %v, want a recovered panic", err)
+	}
+}
+
+func TestBackupVariants(t *testing.T) {
+	got := backupVariants("inc/db.inc.php", nil)
+	expected := "inc/db.inc.php.bak,inc/db.inc.php~,inc/db.inc.php.old,inc/.db.inc.php.swp"
+	if strings.Join(got, ",") != expected {
+		t.Errorf("backupVariants() = %v, want %s", got, expected)
+	}
+}
@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// defaultCacheBustParam 默认的缓存破坏参数名
+const defaultCacheBustParam = "cb"
+
+// addCacheBuster 在URL末尾附加随机查询参数，避免CDN返回缓存的响应
+func addCacheBuster(fullURL, param string) string {
+	if param == "" {
+		param = defaultCacheBustParam
+	}
+
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fullURL
+	}
+
+	// 保留片段标识在最后
+	fragment := ""
+	if i := strings.Index(fullURL, "#"); i >= 0 {
+		fullURL, fragment = fullURL[:i], fullURL[i:]
+	}
+
+	separator := "?"
+	if strings.Contains(fullURL, "?") {
+		separator = "&"
+	}
+	return fullURL + separator + url.QueryEscape(param) + "=" + hex.EncodeToString(buf) + fragment
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestCacheBustOnlyOnWire(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query().Get("nocache"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Request.CacheBust = true
+	cfg.Request.CacheBustParam = "nocache"
+	s := newTestScanner(t, cfg)
+
+	first := s.scanPath(server.URL+"/", "admin")
+	second := s.scanPath(server.URL+"/", "admin")
+	if first.Error != nil || second.Error != nil {
+		t.Fatalf("scanPath failed: %v / %v", first.Error, second.Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queries) != 2 || queries[0] == "" || queries[1] == "" {
+		t.Fatalf("Expected cache-bust param on both wire requests, got %q", queries)
+	}
+	if queries[0] == queries[1] {
+		t.Errorf("Expected a different cache-bust value per request, got %q twice", queries[0])
+	}
+
+	for _, result := range []ScanResult{first, second} {
+		if strings.Contains(result.URL+result.Path, "nocache") {
+			t.Errorf("Result URL %q should not contain the cache-bust param", result.URL+result.Path)
+		}
+	}
+}
+
+func TestAddCacheBuster(t *testing.T) {
+	tests := []struct {
+		url    string
+		prefix string
+		suffix string
+	}{
+		{url: "http://example.com/admin", prefix: "http://example.com/admin?cb="},
+		{url: "http://example.com/search?q=1", prefix: "http://example.com/search?q=1&cb="},
+		{url: "http://example.com/page#top", prefix: "http://example.com/page?cb=", suffix: "#top"},
+	}
+
+	for _, tt := range tests {
+		got := addCacheBuster(tt.url, "")
+		if !strings.HasPrefix(got, tt.prefix) || !strings.HasSuffix(got, tt.suffix) || len(got) != len(tt.prefix)+12+len(tt.suffix) {
+			t.Errorf("addCacheBuster(%q) = %q, want %q<12 hex chars>%s", tt.url, got, tt.prefix, tt.suffix)
+		}
+	}
+}
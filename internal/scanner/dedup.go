@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// resultDeduper 按--dedup-by选择的键对发现的结果去重
+type resultDeduper struct {
+	mode string
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// newResultDeduper 创建结果去重器，mode为空时返回nil（不去重）
+func newResultDeduper(mode string) *resultDeduper {
+	if mode == "" {
+		return nil
+	}
+	return &resultDeduper{mode: mode, seen: make(map[string]bool)}
+}
+
+// Seen 记录结果的去重键，任一键已出现过时返回true。fullURL为结果的完整URL
+func (d *resultDeduper) Seen(fullURL string, result ScanResult) bool {
+	keys := d.keys(fullURL, result)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	duplicate := false
+	for _, key := range keys {
+		if d.seen[key] {
+			duplicate = true
+		}
+		d.seen[key] = true
+	}
+	return duplicate
+}
+
+// keys 返回结果的去重键。hash模式下重定向以其目标URL作为键，其他结果同时以自身URL和响应内容作为键，
+// 因此/admin（301到/admin/）与/admin/视为同一资源。没有响应内容（如无头模式）时只使用URL，
+// 否则同一状态码的结果都会被当作重复
+func (d *resultDeduper) keys(fullURL string, result ScanResult) []string {
+	switch d.mode {
+	case "url":
+		return []string{"url:" + normalizeResultURL(fullURL)}
+	case "path":
+		return []string{"path:" + strings.Trim(result.Path, "/")}
+	case "status-size":
+		return []string{fmt.Sprintf("status-size:%d:%d", result.StatusCode, result.Size)}
+	case "hash":
+		if result.Redirect != "" && result.StatusCode >= 300 && result.StatusCode < 400 {
+			return []string{"url:" + normalizeResultURL(result.Redirect)}
+		}
+		keys := []string{"url:" + normalizeResultURL(fullURL)}
+		if result.Body != "" {
+			sum := sha256.Sum256([]byte(result.Body))
+			keys = append(keys, fmt.Sprintf("hash:%d:%s", result.StatusCode, hex.EncodeToString(sum[:])))
+		}
+		return keys
+	}
+	return nil
+}
+
+// normalizeResultURL 标准化URL用于比较：主机名转小写，去掉片段和路径末尾的斜杠
+func normalizeResultURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.TrimSuffix(rawURL, "/")
+	}
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Fragment = ""
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+	parsed.RawPath = ""
+	return parsed.String()
+}
@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestDedupByHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin":
+			http.Redirect(w, r, "/admin/", http.StatusMovedPermanently)
+		case "/admin/":
+			io.WriteString(w, "<html><title>Admin</title></html>")
+		case "/login":
+			io.WriteString(w, "<html><title>Login</title></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		dedupBy   string
+		wantFound int
+	}{
+		{name: "按内容去重", dedupBy: "hash", wantFound: 2},
+		{name: "不去重", dedupBy: "", wantFound: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.General.DedupBy = tt.dedupBy
+			s := newTestScanner(t, cfg)
+			s.dedup = newResultDeduper(tt.dedupBy)
+
+			results, err := s.executeScan([]string{server.URL + "/"}, []string{"admin", "admin/", "login"}, 0)
+			if err != nil {
+				t.Fatalf("executeScan failed: %v", err)
+			}
+
+			found := make(map[string]bool)
+			for _, result := range results {
+				if s.isFound(result) {
+					found[result.Path] = true
+				}
+			}
+			if len(found) != tt.wantFound {
+				t.Errorf("Found %v, want %d results", found, tt.wantFound)
+			}
+			if !found["login"] {
+				t.Errorf("login should not be a duplicate: %v", found)
+			}
+			if tt.dedupBy == "hash" && found["admin"] == found["admin/"] {
+				t.Errorf("admin (301 to admin/) and admin/ should collapse into one result: %v", found)
+			}
+			if len(s.GetResults()) != tt.wantFound {
+				t.Errorf("Stored %d results, want %d", len(s.GetResults()), tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestResultDeduperKeys(t *testing.T) {
+	tests := []struct {
+		mode      string
+		first     ScanResult
+		second    ScanResult
+		duplicate bool
+	}{
+		{
+			mode:      "url",
+			first:     ScanResult{URL: "http://Example.com/", Path: "admin"},
+			second:    ScanResult{URL: "http://example.com/", Path: "admin/"},
+			duplicate: true,
+		},
+		{
+			mode:      "path",
+			first:     ScanResult{URL: "http://a.example.com/", Path: "admin"},
+			second:    ScanResult{URL: "http://b.example.com/", Path: "admin"},
+			duplicate: true,
+		},
+		{
+			mode:      "hash",
+			first:     ScanResult{URL: "http://example.com/", Path: "a", StatusCode: 200},
+			second:    ScanResult{URL: "http://example.com/", Path: "b", StatusCode: 200},
+			duplicate: false,
+		},
+		{
+			mode:      "status-size",
+			first:     ScanResult{URL: "http://example.com/", Path: "a", StatusCode: 200, Size: 10},
+			second:    ScanResult{URL: "http://example.com/", Path: "b", StatusCode: 200, Size: 11},
+			duplicate: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			d := newResultDeduper(tt.mode)
+			if d.Seen(tt.first.URL+tt.first.Path, tt.first) {
+				t.Fatalf("First result reported as duplicate")
+			}
+			if got := d.Seen(tt.second.URL+tt.second.Path, tt.second); got != tt.duplicate {
+				t.Errorf("Second result duplicate = %v, want %v", got, tt.duplicate)
+			}
+		})
+	}
+}
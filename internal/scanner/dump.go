@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"runtime/debug"
+
+	"dirsearch-go/internal/utils"
+)
+
+// DumpRequests 打印扫描将要发送的原始HTTP请求而不发送，最多打印--dump-requests个
+func (s *Scanner) DumpRequests(targets []string, w io.Writer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("DumpRequests panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
+		}
+	}()
+
+	paths, _, err := s.scanPaths()
+	if err != nil {
+		return fmt.Errorf("failed to generate paths: %w", err)
+	}
+	return s.dumpRequests(s.normalizeTargets(targets), paths, w)
+}
+
+// dumpRequests 按扫描任务顺序构建请求并写出序列化结果，达到数量上限后停止
+func (s *Scanner) dumpRequests(targets []string, paths pathSource, w io.Writer) error {
+	limit := s.config.General.DumpRequests
+	count := 0
+
+	var dumpErr error
+	err := s.scanTasks(targets, paths, 0)(func(task ScanTask) bool {
+		if count >= limit {
+			return false
+		}
+		fullURL, err := s.buildURL(task.Target, task.Path)
+		if err != nil {
+			dumpErr = fmt.Errorf("failed to build URL for %s: %w", task.Path, err)
+			return false
+		}
+		dump, err := s.requester.DumpRequest(fullURL)
+		if err != nil {
+			dumpErr = err
+			return false
+		}
+		fmt.Fprintf(w, "%s\n", dump)
+		count++
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate scan tasks: %w", err)
+	}
+	if dumpErr != nil {
+		return dumpErr
+	}
+
+	fmt.Fprintf(w, "已打印 %d 个请求（上限 %d），未发送任何扫描请求\n", count, limit)
+	return nil
+}
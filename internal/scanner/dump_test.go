@@ -0,0 +1,34 @@
+package scanner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestDumpRequests(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.General.DumpRequests = 2
+	cfg.Request.HTTPMethod = "POST"
+	cfg.Request.Data = "user=admin"
+	cfg.Request.Headers = []string{"X-Custom: payload"}
+	s := newTestScanner(t, cfg)
+
+	var out bytes.Buffer
+	targets := []string{"http://example.invalid/"}
+	if err := s.dumpRequests(targets, slicePaths([]string{"admin", "login", "backup"}), &out); err != nil {
+		t.Fatalf("dumpRequests failed: %v", err)
+	}
+
+	dump := out.String()
+	for _, expected := range []string{"POST /admin HTTP/1.1", "POST /login HTTP/1.1", "Host: example.invalid", "X-Custom: payload", "user=admin"} {
+		if !strings.Contains(dump, expected) {
+			t.Errorf("Dump missing %q:\n%s", expected, dump)
+		}
+	}
+	if strings.Contains(dump, "/backup") {
+		t.Errorf("Expected dump to stop after %d requests:\n%s", cfg.General.DumpRequests, dump)
+	}
+}
@@ -0,0 +1,31 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrExitOnError 启用--exit-on-error时请求出错，扫描已中止
+var ErrExitOnError = errors.New("scan aborted on request error (--exit-on-error)")
+
+// abortOnError 启用--exit-on-error时，第一个请求错误（连接失败、超时等，不含HTTP错误状态码）记录为中止原因并停止扫描
+func (s *Scanner) abortOnError(result ScanResult) {
+	if !s.config.General.ExitOnError || result.Error == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.abortErr != nil {
+		return
+	}
+	s.abortErr = fmt.Errorf("%w: %s: %v", ErrExitOnError, s.smartPathJoin(result.URL, result.Path), result.Error)
+	s.cancel()
+}
+
+// abortError 返回扫描中止的原因，扫描未因--exit-on-error中止时返回nil
+func (s *Scanner) abortError() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.abortErr
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestExitOnError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/broken":
+			// 关闭连接而不返回响应，产生请求错误
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		case "/error":
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		exitOnError bool
+		paths       []string
+		wantAbort   bool
+	}{
+		{name: "请求出错时中止", exitOnError: true, paths: []string{"broken"}, wantAbort: true},
+		{name: "HTTP错误状态码不中止", exitOnError: true, paths: []string{"error"}, wantAbort: false},
+		{name: "未启用时不中止", exitOnError: false, paths: []string{"broken"}, wantAbort: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			cfg := &config.Config{}
+			cfg.General.Threads = 1
+			cfg.General.ExitOnError = tt.exitOnError
+			s := newTestScanner(t, cfg)
+			s.controller = NewScanController(1, 1)
+
+			paths := append([]string(nil), tt.paths...)
+			for i := 0; i < 200; i++ {
+				paths = append(paths, fmt.Sprintf("missing%d", i))
+			}
+			if _, err := s.executeScan([]string{server.URL + "/"}, paths, 0); err != nil {
+				t.Fatalf("executeScan failed: %v", err)
+			}
+
+			err := s.abortError()
+			if got := errors.Is(err, ErrExitOnError); got != tt.wantAbort {
+				t.Fatalf("abortError() = %v, want abort %v", err, tt.wantAbort)
+			}
+			if tt.wantAbort && int(requests.Load()) >= len(paths) {
+				t.Errorf("Scan sent all %d requests, want it to stop after the first error", requests.Load())
+			}
+			if !tt.wantAbort && int(requests.Load()) < len(paths) {
+				t.Errorf("Scan sent %d requests, want at least %d", requests.Load(), len(paths))
+			}
+		})
+	}
+}
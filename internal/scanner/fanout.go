@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// resultFanout 将扫描结果分发给多个消费者，每个消费者拥有独立的缓冲通道和协程，
+// 慢的消费者（如终端显示）在缓冲区未满时不会阻塞结果收集和其他消费者；缓冲区满后Publish会等待，
+// 对结果收集形成背压（结果切片和报告需要完整的结果，因此不丢弃）
+type resultFanout struct {
+	channels []chan ScanResult
+	wg       sync.WaitGroup
+}
+
+// newResultFanout 创建结果分发器并为每个消费者启动协程
+func newResultFanout(buffer int, consumers ...func(result ScanResult)) *resultFanout {
+	f := &resultFanout{}
+	for i, consume := range consumers {
+		ch := make(chan ScanResult, buffer)
+		f.channels = append(f.channels, ch)
+		f.wg.Add(1)
+		go func(id int, consume func(result ScanResult)) {
+			defer f.wg.Done()
+			for result := range ch {
+				deliverResult(id, consume, result)
+			}
+		}(i, consume)
+	}
+	return f
+}
+
+// deliverResult 将结果交给单个消费者，消费者panic时只丢弃这一条结果
+func deliverResult(id int, consume func(result ScanResult), result ScanResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Result consumer %d panic recovered: %v", id, r)
+		}
+	}()
+	consume(result)
+}
+
+// Publish 将结果发送给所有消费者，某个消费者的缓冲区已满时阻塞到它取走结果
+func (f *resultFanout) Publish(result ScanResult) {
+	for _, ch := range f.channels {
+		ch <- result
+	}
+}
+
+// Close 关闭所有消费者通道并等待剩余结果处理完毕
+func (f *resultFanout) Close() {
+	for _, ch := range f.channels {
+		close(ch)
+	}
+	f.wg.Wait()
+}
+
+// Subscribe 注册扫描结果消费者，每个最终结果（不含两阶段扫描中待确认的HEAD探测结果）都会发送给它。
+// 需要在Scan之前调用，回调在独立的协程中执行
+func (s *Scanner) Subscribe(consume func(result ScanResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, consume)
+}
+
+// resultConsumers 返回结果消费者：结果切片、状态显示、报告存储、webhook、流式报告以及外部订阅者。
+// provisional返回true的结果只收集到切片中，不发布给其他消费者
+func (s *Scanner) resultConsumers(results *[]ScanResult, provisional func(result ScanResult) bool) []func(result ScanResult) {
+	final := func(consume func(result ScanResult)) func(result ScanResult) {
+		return func(result ScanResult) {
+			if provisional == nil || !provisional(result) {
+				consume(result)
+			}
+		}
+	}
+
+	consumers := []func(result ScanResult){
+		func(result ScanResult) { *results = append(*results, result) },
+		final(s.statusDisplay.UpdateProgress),
+		final(s.addResult),
+	}
+
+	// 发现的结果实时发送到webhook
+	if s.webhook != nil {
+		consumers = append(consumers, final(func(result ScanResult) {
+			if s.isFound(result) {
+				s.webhook.Send(result)
+			}
+		}))
+	}
+
+	// 保留的结果实时写入流式报告
+	if s.stream != nil {
+		consumers = append(consumers, final(s.writeStream))
+	}
+
+	s.mu.RLock()
+	for _, consume := range s.subscribers {
+		consumers = append(consumers, final(consume))
+	}
+	s.mu.RUnlock()
+	return consumers
+}
+
+// flushWebhook 等待webhook发送完队列中的结果
+func (s *Scanner) flushWebhook() {
+	if s.webhook != nil {
+		s.webhook.Flush(30 * time.Second)
+	}
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestResultFanoutConsumers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin", "/login":
+			w.WriteHeader(http.StatusOK)
+		case "/secret":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	s := newTestScanner(t, cfg)
+
+	var mu sync.Mutex
+	var subscribed []ScanResult
+	s.Subscribe(func(result ScanResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		subscribed = append(subscribed, result)
+	})
+
+	paths := []string{"admin", "login", "secret", "missing", "other"}
+	results, err := s.executeScan([]string{server.URL + "/"}, paths, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	resultPaths := func(results []ScanResult) string {
+		var paths []string
+		for _, result := range results {
+			paths = append(paths, result.Path)
+		}
+		sort.Strings(paths)
+		return strings.Join(paths, ",")
+	}
+
+	want := "admin,login,missing,other,secret"
+	mu.Lock()
+	defer mu.Unlock()
+	tests := []struct {
+		name    string
+		results []ScanResult
+	}{
+		{name: "结果切片", results: results},
+		{name: "报告存储", results: s.GetResults()},
+		{name: "外部订阅者", results: subscribed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultPaths(tt.results); got != want {
+				t.Errorf("Got results %q, want %q", got, want)
+			}
+		})
+	}
+
+	t.Run("状态显示", func(t *testing.T) {
+		if got := s.statusDisplay.Scanned(); got != len(paths) {
+			t.Errorf("Status display scanned %d results, want %d", got, len(paths))
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestResponseTimeFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.MinResponseTime = 200
+	s := newTestScanner(t, cfg)
+
+	slow := s.scanPath(server.URL, "slow")
+	if slow.Error != nil {
+		t.Fatalf("scanPath(slow) failed: %v", slow.Error)
+	}
+	if slow.ResponseTime < 300*time.Millisecond {
+		t.Errorf("Expected slow response time >= 300ms, got %v", slow.ResponseTime)
+	}
+	if !s.shouldIncludeResult(slow) {
+		t.Errorf("Expected slow result (%v) to be kept under --min-response-time", slow.ResponseTime)
+	}
+
+	fast := s.scanPath(server.URL, "fast")
+	if fast.Error != nil {
+		t.Fatalf("scanPath(fast) failed: %v", fast.Error)
+	}
+	if s.shouldIncludeResult(fast) {
+		t.Errorf("Expected fast result (%v) to be dropped under --min-response-time", fast.ResponseTime)
+	}
+
+	cfg.General.MinResponseTime = 0
+	cfg.General.MaxResponseTime = 200
+	if s.shouldIncludeResult(slow) {
+		t.Errorf("Expected slow result (%v) to be dropped under --max-response-time", slow.ResponseTime)
+	}
+	if !s.shouldIncludeResult(fast) {
+		t.Errorf("Expected fast result (%v) to be kept under --max-response-time", fast.ResponseTime)
+	}
+}
+
+func TestExcludeLengthSimilar(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.General.ExcludeLengthSimilar = []string{"1234±20"}
+	s := newTestScanner(t, cfg)
+
+	tests := []struct {
+		size     int64
+		expected bool
+	}{
+		{size: 1234, expected: false},
+		{size: 1214, expected: false},
+		{size: 1254, expected: false},
+		{size: 1240, expected: false},
+		{size: 1213, expected: true},
+		{size: 1255, expected: true},
+		{size: 42, expected: true},
+	}
+
+	for _, tt := range tests {
+		result := ScanResult{StatusCode: 200, Size: tt.size}
+		if got := s.shouldIncludeResult(result); got != tt.expected {
+			t.Errorf("shouldIncludeResult(size=%d) = %v, want %v", tt.size, got, tt.expected)
+		}
+	}
+}
+
+func TestKnown404(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.General.Known404 = []string{"size=1234", "text=Custom Not Found"}
+	s := newTestScanner(t, cfg)
+
+	tests := []struct {
+		name     string
+		result   ScanResult
+		expected bool
+	}{
+		{name: "大小匹配", result: ScanResult{StatusCode: 200, Size: 1234}, expected: false},
+		{name: "文本匹配", result: ScanResult{StatusCode: 200, Size: 80, Body: "<h1>Custom Not Found</h1>"}, expected: false},
+		{name: "大小不同", result: ScanResult{StatusCode: 200, Size: 1235}, expected: true},
+		{name: "正常页面", result: ScanResult{StatusCode: 200, Size: 80, Body: "<h1>Admin</h1>"}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.shouldIncludeResult(tt.result); got != tt.expected {
+				t.Errorf("shouldIncludeResult() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+)
+
+// displayFingerprintSummary 显示主机指纹摘要
+func (s *Scanner) displayFingerprintSummary() {
+	fingerprints := s.requester.HostManager.GetFingerprints()
+	if len(fingerprints) == 0 {
+		return
+	}
+
+	hosts := make([]string, 0, len(fingerprints))
+	for host := range fingerprints {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	fmt.Println("\n主机指纹:")
+	fmt.Println("==================")
+	for _, host := range hosts {
+		fmt.Printf("  %s: %s\n", host, fingerprints[host])
+	}
+}
@@ -0,0 +1,50 @@
+//go:build headless
+
+package scanner
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/connection"
+)
+
+func TestHeadlessScreenshotForFoundPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><head><title>Admin</title></head><body><h1>Admin</h1></body></html>"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Connection: config.ConnectionConfig{Timeout: 15},
+		View:       config.ViewConfig{Headless: true},
+		Output:     config.OutputConfig{ScreenshotDir: dir},
+	}
+	s := newTestScanner(t, cfg)
+
+	browser, err := connection.NewHeadlessBrowser(cfg)
+	if err != nil {
+		t.Fatalf("NewHeadlessBrowser failed: %v", err)
+	}
+	defer browser.Close()
+	s.headlessBrowser = browser
+
+	result := s.scanPath(server.URL+"/", "admin")
+	if result.Error != nil {
+		t.Fatalf("scanPath failed: %v", result.Error)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, screenshotFilename(server.URL+"/admin")))
+	if err != nil {
+		t.Fatalf("Expected screenshot file: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+		t.Error("Screenshot is not a PNG file")
+	}
+}
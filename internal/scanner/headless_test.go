@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/connection"
+)
+
+func TestNewScannerHeadlessUnavailable(t *testing.T) {
+	original := newHeadlessBrowser
+	defer func() { newHeadlessBrowser = original }()
+	newHeadlessBrowser = func(cfg *config.Config) (*connection.HeadlessBrowser, error) {
+		return nil, errors.New("chrome executable not found")
+	}
+
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("admin\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	cfg := &config.Config{
+		General:    config.GeneralConfig{Threads: 1},
+		Dictionary: config.DictionaryConfig{Wordlists: []string{wordlist}},
+		Request:    config.RequestConfig{HTTPMethod: "GET"},
+		Connection: config.ConnectionConfig{Timeout: 5},
+		View:       config.ViewConfig{Headless: true},
+	}
+
+	s, err := NewScanner(cfg)
+	if err == nil {
+		t.Fatal("Expected error when headless browser cannot start")
+	}
+	if s != nil {
+		t.Error("Expected nil scanner when headless browser cannot start")
+	}
+	if !strings.Contains(err.Error(), "headless") {
+		t.Errorf("Error should mention headless mode, got: %v", err)
+	}
+}
+
+func TestScreenshotFilename(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://example.com/admin/login.php", "example.com_admin_login.php_089edbfb.png"},
+		{"https://example.com:8443/a b/?x=1", "example.com_8443_a_b_12b6730b.png"},
+		{"https://example.com/", "example.com_0f115db0.png"},
+		// 只有查询参数不同的URL写入不同的文件
+		{"https://example.com/a?x=1", "example.com_a_4c70119c.png"},
+		{"https://example.com/a?x=2", "example.com_a_815cc72b.png"},
+	}
+
+	for _, tt := range tests {
+		if got := screenshotFilename(tt.url); got != tt.expected {
+			t.Errorf("screenshotFilename(%q) = %q, want %q", tt.url, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"fmt"
+	"net/url"
+
+	"dirsearch-go/internal/connection"
+)
+
+// targetHostKey 返回目标URL对应的主机键，解析失败时返回空字符串
+func targetHostKey(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return connection.HostKey(parsed)
+}
+
+// hostSkipped 检查目标主机是否因连续出错被跳过（--max-consecutive-errors）
+func (s *Scanner) hostSkipped(target string) bool {
+	if s.config.Connection.MaxConsecutiveErrors <= 0 || s.requester == nil {
+		return false
+	}
+	return s.requester.HostManager.IsSkipped(targetHostKey(target))
+}
+
+// recordHostResult 统计目标主机的连续请求错误，扫描被取消导致的错误不计入
+func (s *Scanner) recordHostResult(result ScanResult) {
+	if s.config.Connection.MaxConsecutiveErrors <= 0 || s.requester == nil || s.ctx.Err() != nil {
+		return
+	}
+
+	host := targetHostKey(result.URL)
+	if s.requester.HostManager.RecordRequest(host, result.Error != nil) {
+		fmt.Printf("\n主机 %s 连续 %d 个请求出错，跳过该主机剩余的扫描\n", host, s.config.Connection.MaxConsecutiveErrors)
+	}
+}
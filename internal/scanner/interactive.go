@@ -0,0 +1,165 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ScanController 扫描控制器，支持暂停/恢复及实时调整并发数
+type ScanController struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	paused bool
+	limit  int
+	max    int
+	active int
+}
+
+// NewScanController 创建扫描控制器，limit为初始并发数，maxLimit为允许调整到的最大并发数
+func NewScanController(limit, maxLimit int) *ScanController {
+	if limit <= 0 {
+		limit = 1
+	}
+	if maxLimit < limit {
+		maxLimit = limit
+	}
+
+	c := &ScanController{
+		limit: limit,
+		max:   maxLimit,
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Acquire 获取一个请求槽位，暂停或达到并发上限时阻塞；上下文取消时返回false
+func (c *ScanController) Acquire(ctx context.Context) bool {
+	stop := context.AfterFunc(ctx, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.cond.Broadcast()
+	})
+	defer stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.paused || c.active >= c.limit {
+		if ctx.Err() != nil {
+			return false
+		}
+		c.cond.Wait()
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+
+	c.active++
+	return true
+}
+
+// Release 释放请求槽位
+func (c *ScanController) Release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.active > 0 {
+		c.active--
+	}
+	c.cond.Broadcast()
+}
+
+// Pause 暂停扫描，已发出的请求不受影响
+func (c *ScanController) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = true
+}
+
+// Resume 恢复扫描
+func (c *ScanController) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused = false
+	c.cond.Broadcast()
+}
+
+// IsPaused 是否处于暂停状态
+func (c *ScanController) IsPaused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// SetLimit 调整并发数，范围限制在1到max之间，返回调整后的值
+func (c *ScanController) SetLimit(limit int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > c.max {
+		limit = c.max
+	}
+	c.limit = limit
+	c.cond.Broadcast()
+	return c.limit
+}
+
+// Limit 获取当前并发数
+func (c *ScanController) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+// MaxLimit 获取允许的最大并发数
+func (c *ScanController) MaxLimit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.max
+}
+
+// RunKeyListener 读取交互命令：p暂停、r恢复、+增加线程、-减少线程、q停止扫描
+func RunKeyListener(ctx context.Context, r io.Reader, c *ScanController, cancel context.CancelFunc) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "p":
+			c.Pause()
+			fmt.Println("\n[交互] 扫描已暂停，输入 r 恢复")
+		case "r":
+			c.Resume()
+			fmt.Println("\n[交互] 扫描已恢复")
+		case "+":
+			fmt.Printf("\n[交互] 线程数: %d\n", c.SetLimit(c.Limit()+1))
+		case "-":
+			fmt.Printf("\n[交互] 线程数: %d\n", c.SetLimit(c.Limit()-1))
+		case "q":
+			fmt.Println("\n[交互] 正在停止扫描...")
+			c.Resume()
+			if cancel != nil {
+				cancel()
+			}
+			return
+		}
+	}
+}
+
+// isTerminal 判断标准输入是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
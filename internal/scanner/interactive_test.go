@@ -0,0 +1,89 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestInteractivePauseResume(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	s := newTestScanner(t, &config.Config{})
+
+	input, writer := io.Pipe()
+	defer writer.Close()
+	go RunKeyListener(s.ctx, input, s.controller, s.cancel)
+
+	// 暂停后开始扫描，不应发出任何请求
+	fmt.Fprintln(writer, "p")
+	waitFor(t, s.controller.IsPaused)
+
+	paths := []string{"a", "b", "c", "d", "e"}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.executeScan([]string{server.URL + "/"}, paths, 0)
+	}()
+
+	time.Sleep(300 * time.Millisecond)
+	if got := atomic.LoadInt32(&hits); got != 0 {
+		t.Fatalf("Expected no requests while paused, got %d", got)
+	}
+
+	// 恢复后所有路径都应被请求
+	fmt.Fprintln(writer, "r")
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Scan did not finish after resume")
+	}
+
+	if got := atomic.LoadInt32(&hits); got != int32(len(paths)) {
+		t.Errorf("Expected %d requests after resume, got %d", len(paths), got)
+	}
+}
+
+func TestInteractiveAdjustThreads(t *testing.T) {
+	c := NewScanController(2, 4)
+
+	input, writer := io.Pipe()
+	defer writer.Close()
+	go RunKeyListener(context.Background(), input, c, nil)
+
+	fmt.Fprintln(writer, "+")
+	waitFor(t, func() bool { return c.Limit() == 3 })
+
+	fmt.Fprintln(writer, "+")
+	fmt.Fprintln(writer, "+")
+	waitFor(t, func() bool { return c.Limit() == 4 })
+
+	for i := 0; i < 5; i++ {
+		fmt.Fprintln(writer, "-")
+	}
+	waitFor(t, func() bool { return c.Limit() == 1 })
+}
+
+// waitFor 等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
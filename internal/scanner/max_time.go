@@ -0,0 +1,16 @@
+package scanner
+
+import (
+	"fmt"
+	"time"
+)
+
+// stopAfter 在limit后停止扫描（--max-time），已发现的结果照常返回和保存，返回的函数用于取消计时
+func (s *Scanner) stopAfter(limit time.Duration) (stop func()) {
+	timer := time.AfterFunc(limit, func() {
+		fmt.Printf("\n已达到最长扫描时间 %s，停止扫描...\n", limit)
+		s.statusDisplay.SetTimeLimited(limit)
+		s.cancel()
+	})
+	return func() { timer.Stop() }
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestStopAfterReturnsPartialResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 1
+	s := newTestScanner(t, cfg)
+
+	var paths []string
+	for i := 0; i < 200; i++ {
+		paths = append(paths, fmt.Sprintf("path%d", i))
+	}
+
+	stop := s.stopAfter(150 * time.Millisecond)
+	defer stop()
+
+	start := time.Now()
+	results, err := s.executeScan([]string{server.URL + "/"}, paths, 0)
+	if err != nil {
+		t.Fatalf("executeScan returned an error after the deadline: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Scan took %v, expected it to stop shortly after the deadline", elapsed)
+	}
+	if len(results) == 0 || len(results) >= len(paths) {
+		t.Errorf("Got %d results, want partial results (between 1 and %d)", len(results), len(paths)-1)
+	}
+	if !s.statusDisplay.TimeLimited() {
+		t.Error("Expected the final summary to be marked as time-limited")
+	}
+}
+
+func TestScanMaxTimeReturnsPartialResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 单线程每个请求约20ms，完整扫描需要数秒，远超--max-time
+	var words []string
+	for i := 0; i < 300; i++ {
+		words = append(words, fmt.Sprintf("path%d", i))
+	}
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte(strings.Join(words, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	cfg := &config.Config{
+		General:    config.GeneralConfig{Threads: 1, MaxTime: 1},
+		Dictionary: config.DictionaryConfig{Wordlists: []string{wordlist}},
+		Request:    config.RequestConfig{HTTPMethod: "GET"},
+		Connection: config.ConnectionConfig{Timeout: 5, DomainCheckTimeout: 5, DomainCheckRetries: 1},
+	}
+	s, err := NewScanner(cfg)
+	if err != nil {
+		t.Fatalf("NewScanner failed: %v", err)
+	}
+	defer s.Stop()
+
+	start := time.Now()
+	results, err := s.Scan([]string{server.URL + "/"})
+	if err != nil {
+		t.Fatalf("Scan returned an error after --max-time: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("Scan took %v, expected it to stop shortly after --max-time", elapsed)
+	}
+	if len(results) == 0 || len(results) >= len(words) {
+		t.Errorf("Got %d results, want partial results (between 1 and %d)", len(results), len(words)-1)
+	}
+	if !s.statusDisplay.TimeLimited() {
+		t.Error("Expected the final summary to be marked as time-limited")
+	}
+}
@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// shouldProbeMethods 判断是否需要探测结果允许的HTTP方法
+func (s *Scanner) shouldProbeMethods(result ScanResult) bool {
+	return s.config.General.ProbeMethods && s.isFound(result)
+}
+
+// probeAllowedMethods 对发现的路径发送OPTIONS请求，从Allow头（IIS使用Public头）解析允许的方法
+func (s *Scanner) probeAllowedMethods(fullURL string) []string {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("probeAllowedMethods panic recovered: %v", r)
+		}
+	}()
+
+	resp, err := s.requester.RequestMethod(fullURL, http.MethodOptions)
+	if err != nil {
+		log.Printf("Warning: Failed to probe methods for %s: %v", fullURL, err)
+		return nil
+	}
+
+	allow := resp.Headers.Values("Allow")
+	if len(allow) == 0 {
+		allow = resp.Headers.Values("Public")
+	}
+	return parseAllowedMethods(allow)
+}
+
+// parseAllowedMethods 解析逗号分隔的方法列表，统一为大写并去重
+func parseAllowedMethods(values []string) []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, value := range values {
+		for _, method := range strings.Split(value, ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if method == "" || seen[method] {
+				continue
+			}
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestProbeMethods(t *testing.T) {
+	var mu sync.Mutex
+	var options []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			mu.Lock()
+			options = append(options, r.URL.Path)
+			mu.Unlock()
+			w.Header().Set("Allow", "GET, head,POST, OPTIONS, GET")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.URL.Path == "/api" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.ProbeMethods = true
+	s := newTestScanner(t, cfg)
+
+	results, err := s.executeScan([]string{server.URL + "/"}, []string{"api", "missing"}, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	for _, result := range results {
+		switch result.Path {
+		case "api":
+			if got := strings.Join(result.AllowedMethods, ","); got != "GET,HEAD,POST,OPTIONS" {
+				t.Errorf("AllowedMethods = %q, want %q", got, "GET,HEAD,POST,OPTIONS")
+			}
+		case "missing":
+			if len(result.AllowedMethods) != 0 {
+				t.Errorf("Expected no method probe for 404 result, got %v", result.AllowedMethods)
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(options, ",") != "/api" {
+		t.Errorf("OPTIONS requests = %v, want only /api", options)
+	}
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"sync"
+)
+
+// notFoundKey 404统计的键，同一路径在不同主机上分别统计
+type notFoundKey struct {
+	host string
+	path string
+}
+
+// notFoundTracker 统计每个主机上的每个路径在多少个不同目录下返回404，达到上限后递归扫描该主机时不再调度该路径（--skip-404-after）
+type notFoundTracker struct {
+	limit int
+	dirs  map[notFoundKey]map[string]bool // 主机和路径 -> 返回404的目录
+	mu    sync.Mutex
+}
+
+// newNotFoundTracker 创建404统计器，limit不大于0时返回nil
+func newNotFoundTracker(limit int) *notFoundTracker {
+	if limit <= 0 {
+		return nil
+	}
+	return &notFoundTracker{
+		limit: limit,
+		dirs:  make(map[notFoundKey]map[string]bool),
+	}
+}
+
+// Record 记录路径在目录target下返回了404
+func (t *notFoundTracker) Record(target, path string) {
+	key := notFoundKey{host: targetHostKey(target), path: path}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dirs := t.dirs[key]
+	if dirs == nil {
+		dirs = make(map[string]bool)
+		t.dirs[key] = dirs
+	}
+	dirs[target] = true
+}
+
+// Skip 判断路径是否已在目标所在主机的足够多的目录下返回404
+func (t *notFoundTracker) Skip(target, path string) bool {
+	key := notFoundKey{host: targetHostKey(target), path: path}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.dirs[key]) >= t.limit
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestSkip404After(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(strings.TrimSuffix(r.URL.Path, "/"), "/")+1:]
+		mu.Lock()
+		requested[name]++
+		mu.Unlock()
+		if name == "admin" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 1
+	cfg.General.Skip404After = 2
+	s := newTestScanner(t, cfg)
+	s.notFound = newNotFoundTracker(cfg.General.Skip404After)
+
+	// 与递归扫描一样依次扫描每个目录
+	for _, dir := range []string{"a/", "b/", "c/", "d/"} {
+		if _, err := s.executeScan([]string{server.URL + "/" + dir}, []string{"images/", "admin"}, 1); err != nil {
+			t.Fatalf("executeScan failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requested["images/"] != cfg.General.Skip404After {
+		t.Errorf("images/ requested %d times, want it skipped after %d directories returned 404", requested["images/"], cfg.General.Skip404After)
+	}
+	if requested["admin"] != 4 {
+		t.Errorf("admin requested %d times, want 4", requested["admin"])
+	}
+}
+
+func TestNotFoundTracker(t *testing.T) {
+	if newNotFoundTracker(0) != nil {
+		t.Error("newNotFoundTracker(0) should disable tracking")
+	}
+
+	tracker := newNotFoundTracker(2)
+	tracker.Record("http://example.com/a/", "images/")
+	// 同一目录重复返回404只计一次
+	tracker.Record("http://example.com/a/", "images/")
+	if tracker.Skip("http://example.com/c/", "images/") {
+		t.Error("Skip() = true after 404 in one directory")
+	}
+	tracker.Record("http://example.com/b/", "images/")
+	if !tracker.Skip("http://example.com/c/", "images/") {
+		t.Error("Skip() = false after 404 in two directories")
+	}
+	if tracker.Skip("http://example.com/c/", "admin") {
+		t.Error("Skip() = true for a path that never returned 404")
+	}
+	// 其他主机上的404不影响该主机
+	if tracker.Skip("http://other.example.com/c/", "images/") {
+		t.Error("Skip() = true on a host where the path never returned 404")
+	}
+}
+
+func TestSkip404AfterOnlyDuringRecursion(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]int)
+	newServer := func() *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requested[r.Host+r.URL.Path]++
+			mu.Unlock()
+			http.NotFound(w, r)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	servers := []*httptest.Server{newServer(), newServer(), newServer()}
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 1
+	cfg.General.Skip404After = 2
+	s := newTestScanner(t, cfg)
+	s.notFound = newNotFoundTracker(cfg.General.Skip404After)
+
+	// 首轮扫描多个目标时，每个目标都请求所有路径
+	var targets []string
+	for _, server := range servers {
+		targets = append(targets, server.URL+"/")
+	}
+	if _, err := s.executeScan(targets, []string{"images/"}, 0); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+	// 递归扫描另一个主机的目录时不受其他主机上的404影响
+	last := servers[len(servers)-1]
+	if _, err := s.executeScan([]string{last.URL + "/a/"}, []string{"images/"}, 1); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, server := range servers {
+		host := strings.TrimPrefix(server.URL, "http://")
+		if requested[host+"/images/"] != 1 {
+			t.Errorf("%s/images/ requested %d times, want 1", host, requested[host+"/images/"])
+		}
+	}
+	if host := strings.TrimPrefix(last.URL, "http://"); requested[host+"/a/images/"] != 1 {
+		t.Errorf("%s/a/images/ requested %d times, want 1", host, requested[host+"/a/images/"])
+	}
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// paramFuzzValue --param-fuzz中每个参数名使用的参数值
+const paramFuzzValue = "test"
+
+// paramBaseline 不带模糊参数时端点的基线响应
+type paramBaseline struct {
+	StatusCode int
+	Size       int64
+}
+
+// paramFuzzQuery 返回将word作为参数名附加到目标URL上的查询串，目标已有查询参数时使用&连接
+func paramFuzzQuery(target, word string) string {
+	separator := "?"
+	if strings.Contains(target, "?") {
+		separator = "&"
+	}
+	return separator + url.QueryEscape(word) + "=" + paramFuzzValue
+}
+
+// paramFuzzTasks 按目标依次生成参数模糊测试任务，端点URL保持不变，字典中的每个词作为查询参数名
+func (s *Scanner) paramFuzzTasks(targets []string, words pathSource) taskSource {
+	return func(send func(task ScanTask) bool) error {
+		for _, target := range targets {
+			err := words(func(word string) bool {
+				return send(ScanTask{Target: target, Path: paramFuzzQuery(target, word)})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read words for %s: %w", target, err)
+			}
+			if s.ctx.Err() != nil {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// fetchParamBaselines 在启动工作协程前请求每个不带模糊参数的端点作为基线，请求失败的端点不做基线过滤
+func (s *Scanner) fetchParamBaselines(targets []string) {
+	baselines := make(map[string]paramBaseline, len(targets))
+	for _, target := range targets {
+		resp, err := s.requester.Request(target)
+		if err != nil {
+			log.Printf("Warning: Failed to fetch baseline for %s: %v", target, err)
+			continue
+		}
+		baselines[target] = paramBaseline{StatusCode: resp.StatusCode, Size: resp.ContentLength}
+	}
+	s.paramBaselines = baselines
+}
+
+// matchesParamBaseline 判断参数模糊测试结果是否与基线相同（状态码和长度都未变化），相同的结果说明参数不影响响应
+func (s *Scanner) matchesParamBaseline(result ScanResult) bool {
+	if !s.config.General.ParamFuzz || result.Error != nil {
+		return false
+	}
+	baseline, ok := s.paramBaselines[result.URL]
+	return ok && baseline.StatusCode == result.StatusCode && baseline.Size == result.Size
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestParamFuzz(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+
+		if r.URL.Path != "/api/search" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("debug") == "test" {
+			io.WriteString(w, "<html><title>Search</title>debug enabled</html>")
+			return
+		}
+		io.WriteString(w, "<html><title>Search</title></html>")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		target string
+		query  string
+	}{
+		{name: "不带查询参数的端点", target: server.URL + "/api/search", query: "debug=test"},
+		{name: "已有查询参数的端点", target: server.URL + "/api/search?q=1", query: "q=1&debug=test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries = nil
+			cfg := &config.Config{}
+			cfg.General.ParamFuzz = true
+			s := newTestScanner(t, cfg)
+
+			results, err := s.executeScan([]string{tt.target}, []string{"id", "debug", "page"}, 0)
+			if err != nil {
+				t.Fatalf("executeScan failed: %v", err)
+			}
+
+			mu.Lock()
+			if !slices.Contains(queries, tt.query) {
+				t.Errorf("Expected a request with query %q, got %v", tt.query, queries)
+			}
+			mu.Unlock()
+
+			var found []string
+			for _, result := range results {
+				if result.URL != tt.target {
+					t.Errorf("Result URL = %q, want the fixed endpoint %q", result.URL, tt.target)
+				}
+				if s.isFound(result) {
+					found = append(found, result.Path)
+				}
+			}
+			// 只有改变响应的debug参数通过基线过滤
+			if len(found) != 1 || found[0] != paramFuzzQuery(tt.target, "debug") {
+				t.Errorf("Found %v, want only the debug parameter", found)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package scanner
+
+import (
+	"golang.org/x/time/rate"
+)
+
+// newRateLimiter 根据--max-rate创建整个扫描共享的请求速率限制器（不区分主机），不大于0时不限制。
+// 令牌桶容量为1，请求均匀发送而不是每秒开始时集中发送
+func newRateLimiter(maxRate int) *rate.Limiter {
+	if maxRate <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(maxRate), 1)
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestMaxRateLimitsRequests(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 5
+	cfg.Connection.MaxRate = 20
+	s := newTestScanner(t, cfg)
+	s.limiter = newRateLimiter(cfg.Connection.MaxRate)
+	s.requester.SetRateLimiter(s.limiter)
+
+	var paths []string
+	for i := 0; i < 10; i++ {
+		paths = append(paths, fmt.Sprintf("path%d", i))
+	}
+
+	start := time.Now()
+	if _, err := s.executeScan([]string{server.URL + "/"}, paths, 0); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if got := atomic.LoadInt32(&requests); got != int32(len(paths)) {
+		t.Fatalf("Got %d requests, want %d", got, len(paths))
+	}
+	// 10个请求、每秒20个：第一个请求立即发送，其余每隔50ms发送一个
+	if want := 400 * time.Millisecond; elapsed < want {
+		t.Errorf("Scan took %v, expected at least %v with --max-rate %d across %d threads", elapsed, want, cfg.Connection.MaxRate, cfg.General.Threads)
+	}
+}
+
+func TestMaxRateUnblocksOnStop(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.Connection.MaxRate = 1
+	s := newTestScanner(t, cfg)
+	s.limiter = newRateLimiter(cfg.Connection.MaxRate)
+	s.requester.SetRateLimiter(s.limiter)
+
+	paths := []string{"a", "b", "c", "d", "e"}
+	time.AfterFunc(200*time.Millisecond, s.cancel)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.executeScan([]string{server.URL + "/"}, paths, 0)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Workers waiting on --max-rate were not released when the scan stopped")
+	}
+}
+
+func TestNewRateLimiterUnlimited(t *testing.T) {
+	if limiter := newRateLimiter(0); limiter != nil {
+		t.Errorf("Expected no limiter for --max-rate 0, got %v", limiter.Limit())
+	}
+}
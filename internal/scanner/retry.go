@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"dirsearch-go/internal/utils"
+)
+
+// resultKey 结果的唯一标识（目标+路径）
+func resultKey(target, path string) string {
+	return target + "\x00" + path
+}
+
+// errorTasks 收集报告中出错的目标/路径组合（去重）
+func errorTasks(previous []ScanResult) []ScanTask {
+	seen := make(map[string]bool)
+	var tasks []ScanTask
+	for _, result := range previous {
+		if result.Error == nil {
+			continue
+		}
+		key := resultKey(result.URL, result.Path)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		tasks = append(tasks, ScanTask{Target: result.URL, Path: result.Path})
+	}
+	return tasks
+}
+
+// mergeRetryResults 用重试结果替换之前出错的条目，保持原有顺序
+func mergeRetryResults(previous, retried []ScanResult) []ScanResult {
+	replacements := make(map[string]ScanResult, len(retried))
+	for _, result := range retried {
+		replacements[resultKey(result.URL, result.Path)] = result
+	}
+
+	merged := make([]ScanResult, 0, len(previous))
+	used := make(map[string]bool)
+	for _, result := range previous {
+		key := resultKey(result.URL, result.Path)
+		if result.Error == nil {
+			merged = append(merged, result)
+			continue
+		}
+		if used[key] {
+			continue
+		}
+		if replacement, ok := replacements[key]; ok {
+			merged = append(merged, replacement)
+			used[key] = true
+		} else {
+			merged = append(merged, result)
+		}
+	}
+	return merged
+}
+
+// RetryErrors 只重新扫描之前报告中出错的条目，并与之前的结果合并
+func (s *Scanner) RetryErrors(previous []ScanResult) (_ []ScanResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("RetryErrors panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
+		}
+	}()
+
+	tasks := errorTasks(previous)
+	if len(tasks) == 0 {
+		fmt.Println("报告中没有出错的条目，无需重试")
+	} else {
+		fmt.Printf("从报告中重新扫描 %d 个出错的条目...\n", len(tasks))
+	}
+
+	var retried []ScanResult
+	if len(tasks) > 0 {
+		source := func(send func(task ScanTask) bool) error {
+			for _, task := range tasks {
+				if !send(task) {
+					return nil
+				}
+			}
+			return nil
+		}
+
+		var err error
+		retried, err = s.executeTasks(source, len(tasks), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retry errors: %w", err)
+		}
+		s.flushWebhook()
+		s.statusDisplay.DisplayFinalResults(retried)
+	}
+
+	merged := mergeRetryResults(previous, retried)
+
+	// 保存合并后的结果用于生成报告
+	s.mu.Lock()
+	s.results = nil
+	s.mu.Unlock()
+	for _, result := range merged {
+		s.addResult(result)
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestRetryErrorsOnlyRescansErroredEntries(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := server.URL + "/"
+	previous := []ScanResult{
+		{URL: target, Path: "ok", StatusCode: 200},
+		{URL: target, Path: "timeout", Error: errors.New("request failed: context deadline exceeded")},
+		{URL: target, Path: "proxy", Error: errors.New("request failed: proxyconnect tcp: connection refused")},
+		{URL: target, Path: "timeout", Error: errors.New("request failed: context deadline exceeded")},
+	}
+
+	s := newTestScanner(t, &config.Config{})
+	merged, err := s.RetryErrors(previous)
+	if err != nil {
+		t.Fatalf("RetryErrors failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expectedHits := map[string]int{"/timeout": 1, "/proxy": 1}
+	if len(hits) != len(expectedHits) {
+		t.Errorf("Server hits = %v, want %v", hits, expectedHits)
+	}
+	for path, count := range expectedHits {
+		if hits[path] != count {
+			t.Errorf("Path %s hit %d times, want %d", path, hits[path], count)
+		}
+	}
+
+	if len(merged) != 3 {
+		t.Fatalf("Merged %d results, want 3: %+v", len(merged), merged)
+	}
+	for i, path := range []string{"ok", "timeout", "proxy"} {
+		if merged[i].Path != path {
+			t.Errorf("merged[%d].Path = %q, want %q", i, merged[i].Path, path)
+		}
+		if merged[i].Error != nil || merged[i].StatusCode != 200 {
+			t.Errorf("merged[%d] = status %d, error %v; want 200 without error", i, merged[i].StatusCode, merged[i].Error)
+		}
+	}
+	if got := len(s.GetResults()); got != 3 {
+		t.Errorf("Stored %d results for the report, want 3", got)
+	}
+}
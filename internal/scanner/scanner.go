@@ -2,9 +2,14 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"net/http"
 	"net/url"
+	"os"
+	"path"
 	"runtime/debug"
 	"strings"
 	"sync"
@@ -14,12 +19,21 @@ import (
 	"dirsearch-go/internal/connection"
 	"dirsearch-go/internal/dictionary"
 	"dirsearch-go/internal/report"
+	"dirsearch-go/internal/utils"
 	"dirsearch-go/internal/view"
+
+	"golang.org/x/time/rate"
 )
 
 // ScanResult 扫描结果类型别名
 type ScanResult = report.ScanResult
 
+// ErrNoAliveTargets 域名存活检测后没有可扫描的目标
+var ErrNoAliveTargets = errors.New("没有存活的域名可以扫描")
+
+// newHeadlessBrowser 创建无头浏览器，测试中可替换
+var newHeadlessBrowser = connection.NewHeadlessBrowser
+
 // Scanner 扫描器
 type Scanner struct {
 	config          *config.Config
@@ -29,17 +43,29 @@ type Scanner struct {
 	domainChecker   *connection.DomainChecker
 	headlessBrowser *connection.HeadlessBrowser
 	statusDisplay   *view.StatusDisplay
+	controller      *ScanController
 	results         []ScanResult
+	subscribers     []func(result ScanResult) // Subscribe注册的外部结果消费者
+	webhook         *report.WebhookSender     // --webhook实时发送发现的结果
+	stream          *report.StreamWriter      // jsonl格式的流式报告，为nil时只在扫描结束后保存
+	dedup           *resultDeduper            // --dedup-by结果去重，为nil时不去重
+	notFound        *notFoundTracker          // --skip-404-after跳过多个目录下都返回404的路径，为nil时不跳过
+	session         *scanSession              // --session扫描会话，为nil时不保存进度
+	rng             *rand.Rand                // --shuffle使用的随机数生成器，只在任务发送协程中使用
+	limiter         *rate.Limiter             // --max-rate所有工作协程和请求器共享的请求速率限制，为nil时不限制
+	paramBaselines  map[string]paramBaseline  // --param-fuzz各端点不带模糊参数的基线响应
+	abortErr        error                     // --exit-on-error中止扫描的第一个请求错误
 	mu              sync.RWMutex
 	ctx             context.Context
 	cancel          context.CancelFunc
 }
 
 // NewScanner 创建新的扫描器
-func NewScanner(cfg *config.Config) (*Scanner, error) {
+func NewScanner(cfg *config.Config) (_ *Scanner, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("NewScanner panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
 		}
 	}()
 
@@ -47,6 +73,11 @@ func NewScanner(cfg *config.Config) (*Scanner, error) {
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
+	// 未指定--scan-id时生成扫描ID，报告、webhook和日志使用同一个ID
+	if cfg.General.ScanID == "" {
+		cfg.General.ScanID = utils.NewScanID()
+	}
+
 	// 创建上下文
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -56,12 +87,18 @@ func NewScanner(cfg *config.Config) (*Scanner, error) {
 		cancel()
 		return nil, fmt.Errorf("failed to create requester: %w", err)
 	}
-
-	// 创建字典
-	dict, err := dictionary.NewDictionary(cfg)
-	if err != nil {
-		cancel()
-		return nil, fmt.Errorf("failed to create dictionary: %w", err)
+	requester.SetContext(ctx)
+	limiter := newRateLimiter(cfg.Connection.MaxRate)
+	requester.SetRateLimiter(limiter)
+
+	// 创建字典（只重试报告中出错的条目且未指定字典时不需要）
+	var dict *dictionary.Dictionary
+	if cfg.General.RetryErrors == "" || len(cfg.Dictionary.Wordlists) > 0 {
+		dict, err = dictionary.NewDictionary(cfg)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create dictionary: %w", err)
+		}
 	}
 
 	// 创建报告器
@@ -71,19 +108,41 @@ func NewScanner(cfg *config.Config) (*Scanner, error) {
 		return nil, fmt.Errorf("failed to create reporter: %w", err)
 	}
 
-	// 创建域名检查器
-	domainChecker := connection.NewDomainChecker(cfg)
+	// 创建域名检查器，与扫描请求使用相同的传输层
+	domainChecker := connection.NewDomainChecker(cfg, requester.Transport())
 
 	// 创建状态显示器
 	statusDisplay := view.NewStatusDisplay(cfg)
 
-	// 创建无头浏览器（如果启用）
+	// 创建扫描控制器（交互模式下允许将线程数调整到初始值的两倍）
+	threads := cfg.General.Threads
+	if threads <= 0 {
+		threads = 25
+	}
+	maxThreads := threads
+	if cfg.General.Interactive {
+		maxThreads = threads * 2
+	}
+	controller := NewScanController(threads, maxThreads)
+
+	// 创建无头浏览器（如果启用），浏览器不可用时直接报错而不是静默回退到HTTP
 	var headlessBrowser *connection.HeadlessBrowser
 	if cfg.View.Headless {
 		var err error
-		headlessBrowser, err = connection.NewHeadlessBrowser(cfg)
+		headlessBrowser, err = newHeadlessBrowser(cfg)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("headless mode requested but the browser could not be started (install Chrome/Chromium or run without --headless): %w", err)
+		}
+	}
+
+	// 创建webhook发送器（如果启用）
+	var webhook *report.WebhookSender
+	if cfg.Output.Webhook != "" {
+		webhook, err = report.NewWebhookSender(cfg)
 		if err != nil {
-			log.Printf("Warning: Failed to create headless browser: %v", err)
+			cancel()
+			return nil, fmt.Errorf("failed to create webhook sender: %w", err)
 		}
 	}
 
@@ -95,17 +154,33 @@ func NewScanner(cfg *config.Config) (*Scanner, error) {
 		domainChecker:   domainChecker,
 		headlessBrowser: headlessBrowser,
 		statusDisplay:   statusDisplay,
+		controller:      controller,
 		results:         make([]ScanResult, 0),
+		webhook:         webhook,
+		dedup:           newResultDeduper(cfg.General.DedupBy),
+		notFound:        newNotFoundTracker(cfg.General.Skip404After),
+		limiter:         limiter,
 		ctx:             ctx,
 		cancel:          cancel,
 	}, nil
 }
 
+// ScanID 返回本次扫描的ID
+func (s *Scanner) ScanID() string {
+	return s.config.General.ScanID
+}
+
+// CISummary 返回供CI解析的单行扫描摘要（--ci-summary）
+func (s *Scanner) CISummary() string {
+	return s.statusDisplay.CISummary()
+}
+
 // Scan 执行扫描
-func (s *Scanner) Scan(targets []string) ([]ScanResult, error) {
+func (s *Scanner) Scan(targets []string) (_ []ScanResult, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("Scan panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
 		}
 	}()
 
@@ -113,6 +188,31 @@ func (s *Scanner) Scan(targets []string) ([]ScanResult, error) {
 		return nil, fmt.Errorf("no targets specified")
 	}
 
+	// 扫描结束或中断时写入流式报告中剩余的结果
+	defer s.closeStream()
+
+	// 超过--max-time时停止扫描，返回已扫描部分的结果
+	if s.config.General.MaxTime > 0 {
+		stop := s.stopAfter(time.Duration(s.config.General.MaxTime) * time.Second)
+		defer stop()
+	}
+
+	// 会话文件存在时恢复之前的目标和进度，扫描过程中定期保存
+	finished := false
+	if s.config.General.SessionFile != "" {
+		sess, err := openSession(s.config.General.SessionFile, targets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open session: %w", err)
+		}
+		s.session = sess
+		targets = sess.targets
+		stopAutosave := sess.autosave(sessionSaveInterval)
+		defer func() {
+			stopAutosave()
+			sess.Close(finished && s.ctx.Err() == nil)
+		}()
+	}
+
 	// 域名存活检测
 	fmt.Println("正在检测域名存活状态...")
 	aliveTargets, deadTargets := s.domainChecker.CheckMultipleDomains(targets)
@@ -127,40 +227,110 @@ func (s *Scanner) Scan(targets []string) ([]ScanResult, error) {
 	}
 
 	if len(aliveTargets) == 0 {
-		return nil, fmt.Errorf("没有存活的域名可以扫描")
+		return nil, ErrNoAliveTargets
 	}
 
 	fmt.Printf("发现 %d 个存活域名，开始扫描...\n", len(aliveTargets))
 
-	// 标准化URL，确保末尾有斜杠
-	aliveTargets = s.normalizeTargets(aliveTargets)
+	// 交互模式：终端下监听暂停/恢复/线程调整命令
+	if s.config.General.Interactive && s.controller != nil && isTerminal(os.Stdin) {
+		fmt.Println("交互模式已启用: p=暂停 r=恢复 +=增加线程 -=减少线程 q=停止（输入后回车）")
+		go RunKeyListener(s.ctx, os.Stdin, s.controller, s.cancel)
+	}
+
+	// 标准化URL，确保末尾有斜杠（参数模糊测试时端点URL保持不变）
+	if !s.config.General.ParamFuzz {
+		aliveTargets = s.normalizeTargets(aliveTargets)
+	}
+
+	// 根据首页响应头识别技术栈并追加扩展名
+	s.detectTech(aliveTargets)
 
 	// 生成扫描路径
-	paths, err := s.dictionary.GeneratePaths()
+	paths, pathCount, err := s.scanPaths()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate paths: %w", err)
 	}
+	if pathCount == 0 {
+		return nil, fmt.Errorf("%w after applying extension filters", dictionary.ErrEmptyWordlist)
+	}
 
 	// 执行扫描
-	results, err := s.executeScan(aliveTargets, paths, 0)
+	results, err := s.executeScanPaths(aliveTargets, paths, pathCount, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute scan: %w", err)
 	}
 
+	// 等待webhook发送完剩余结果
+	s.flushWebhook()
+
 	// 显示最终结果
 	s.statusDisplay.DisplayFinalResults(results)
 
+	// --exit-on-error：请求出错时扫描已中止，返回已扫描部分的结果和中止原因
+	if err := s.abortError(); err != nil {
+		return results, err
+	}
+
 	// 如果是无头模式，显示摘要
 	s.statusDisplay.DisplayHeadlessSummary(results)
 
+	// 显示主机指纹摘要
+	if s.config.General.Fingerprint {
+		s.displayFingerprintSummary()
+	}
+
+	finished = true
 	return results, nil
 }
 
+// pathSource 扫描路径来源，按顺序回调每个路径，回调返回false时停止
+type pathSource func(fn func(path string) bool) error
+
+// slicePaths 将路径切片包装为路径来源
+func slicePaths(paths []string) pathSource {
+	return func(fn func(path string) bool) error {
+		for _, path := range paths {
+			if !fn(path) {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// scanPaths 获取扫描路径来源及路径数量，低内存模式下从字典流式读取
+func (s *Scanner) scanPaths() (pathSource, int, error) {
+	if s.dictionary == nil {
+		return nil, 0, fmt.Errorf("no wordlist loaded")
+	}
+
+	if s.config.Dictionary.LowMemory {
+		count, err := s.dictionary.CountPaths()
+		if err != nil {
+			return nil, 0, err
+		}
+		return s.dictionary.StreamPaths, count, nil
+	}
+
+	paths, err := s.dictionary.GeneratePaths()
+	if err != nil {
+		return nil, 0, err
+	}
+	return slicePaths(paths), len(paths), nil
+}
+
 // executeScan 执行扫描（支持递归）
 func (s *Scanner) executeScan(targets []string, paths []string, recursionLevel int) ([]ScanResult, error) {
+	return s.executeScanPaths(targets, slicePaths(paths), len(paths), recursionLevel)
+}
+
+// executeScanPaths 按路径来源执行扫描，pathCount用于显示进度
+func (s *Scanner) executeScanPaths(targets []string, paths pathSource, pathCount int, recursionLevel int) (_ []ScanResult, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("executeScan panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
 		}
 	}()
 
@@ -168,19 +338,123 @@ func (s *Scanner) executeScan(targets []string, paths []string, recursionLevel i
 		return []ScanResult{}, nil
 	}
 
-	if paths == nil || len(paths) == 0 {
+	if paths == nil || pathCount == 0 {
 		return []ScanResult{}, nil
 	}
 
+	// 首轮扫描前预热连接，递归扫描复用已有连接
+	if recursionLevel == 0 {
+		s.warmup(targets)
+	}
+
+	// 参数模糊测试：字典中的词作为查询参数名，与不带参数的基线响应比较
+	if s.config.General.ParamFuzz {
+		s.fetchParamBaselines(targets)
+		return s.executeTasks(s.paramFuzzTasks(targets, paths), len(targets)*pathCount, recursionLevel)
+	}
+
+	// 首轮扫描优先发送--start-paths和--priority-file中的路径
+	if start := s.priorityPaths(); recursionLevel == 0 && len(start) > 0 {
+		tasks, count := s.startPathTasks(targets, start, paths)
+		return s.executeTasks(tasks, count, recursionLevel)
+	}
+
+	return s.executeTasks(s.scanTasks(targets, paths, recursionLevel), len(targets)*pathCount, recursionLevel)
+}
+
+// scanTasks 按目标依次生成扫描任务，启用--shuffle时每个目标的路径顺序单独打乱
+func (s *Scanner) scanTasks(targets []string, paths pathSource, recursionLevel int) taskSource {
+	return func(send func(task ScanTask) bool) error {
+		for _, target := range targets {
+			targetPaths := paths
+			if s.config.General.Shuffle {
+				window := 0
+				if s.config.Dictionary.LowMemory {
+					window = shuffleWindow
+				}
+				targetPaths = shufflePaths(paths, s.shuffleRand(), window)
+			}
+
+			err := targetPaths(func(path string) bool {
+				// 递归扫描时，在同一主机足够多的目录下都返回404的路径不再发送（首轮扫描的每个目标都完整扫描）
+				if s.notFound != nil && recursionLevel > 0 && s.notFound.Skip(target, path) {
+					return true
+				}
+				return send(ScanTask{Target: target, Path: path})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to read paths for %s: %w", target, err)
+			}
+			if s.ctx.Err() != nil {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+// taskSource 扫描任务来源，按顺序回调每个任务，回调返回false时停止
+type taskSource func(send func(task ScanTask) bool) error
+
+// executeTasks 执行扫描任务（支持两阶段扫描和递归），totalPaths用于显示进度
+func (s *Scanner) executeTasks(tasks taskSource, totalPaths int, recursionLevel int) (_ []ScanResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("executeTasks panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
+		}
+	}()
+
 	// 设置状态显示器的总路径数
-	totalPaths := len(targets) * len(paths)
 	s.statusDisplay.SetTotalPaths(totalPaths)
 
+	var results []ScanResult
+	if s.config.General.TwoPhase {
+		if results, err = s.runTwoPhase(tasks, recursionLevel); err != nil {
+			return results, fmt.Errorf("two-phase scan failed: %w", err)
+		}
+	} else {
+		results = s.runTasks(tasks, recursionLevel, nil)
+	}
+
+	// 为发现的文件检查常见的备份/临时文件变体（参数模糊测试时路径固定，不检查备份和递归）
+	if s.config.General.CheckBackups && !s.config.General.ParamFuzz {
+		backupResults, err := s.runBackupChecks(results, totalPaths, recursionLevel)
+		results = append(results, backupResults...)
+		if err != nil {
+			return results, fmt.Errorf("backup checks failed: %w", err)
+		}
+	}
+
+	// 如果启用递归扫描，对目录进行递归（扫描已停止时不再递归）
+	if s.config.View.RecursiveScan && !s.config.General.ParamFuzz && recursionLevel < 3 && s.ctx.Err() == nil { // 限制递归深度为3
+		recursiveResults, err := s.performRecursiveScan(results, recursionLevel+1)
+		results = append(results, recursiveResults...)
+		if err != nil {
+			return results, fmt.Errorf("recursive scan failed: %w", err)
+		}
+	}
+
+	return results, nil
+}
+
+// runTasks 使用工作池执行扫描任务，结果经分发器交给各个消费者，provisional返回true的结果只收集不发布
+func (s *Scanner) runTasks(tasks taskSource, recursionLevel int, provisional func(result ScanResult) bool) []ScanResult {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("runTasks panic recovered: %v\nStack trace: %s", r, debug.Stack())
+		}
+	}()
+
 	// 创建工作池
 	workerCount := s.config.General.Threads
 	if workerCount <= 0 {
 		workerCount = 25 // 默认线程数
 	}
+	// 交互模式下按最大并发数启动工作协程，实际并发由控制器限制
+	if s.controller != nil && s.controller.MaxLimit() > workerCount {
+		workerCount = s.controller.MaxLimit()
+	}
 
 	// 创建任务通道
 	taskChan := make(chan ScanTask, workerCount*2)
@@ -210,21 +484,25 @@ func (s *Scanner) executeScan(targets []string, paths []string, recursionLevel i
 			}
 		}()
 
-		for _, target := range targets {
-			for _, path := range paths {
-				select {
-				case taskChan <- ScanTask{Target: target, Path: path}:
-				case <-s.ctx.Done():
-					return
-				}
+		err := tasks(func(task ScanTask) bool {
+			select {
+			case taskChan <- task:
+				return true
+			case <-s.ctx.Done():
+				return false
 			}
+		})
+		if err != nil {
+			log.Printf("Failed to generate scan tasks: %v", err)
 		}
 	}()
 
-	// 收集结果
+	// 收集结果并分发给结果切片、状态显示、报告存储和外部订阅者
 	var results []ScanResult
+	fanout := newResultFanout(workerCount*2, s.resultConsumers(&results, provisional)...)
+	collectorDone := make(chan struct{})
 	go func() {
-		defer close(resultChan)
+		defer close(collectorDone)
 		defer func() {
 			if r := recover(); r != nil {
 				log.Printf("Result collector panic recovered: %v", r)
@@ -233,56 +511,67 @@ func (s *Scanner) executeScan(targets []string, paths []string, recursionLevel i
 
 		for result := range resultChan {
 			result.RecursionLevel = recursionLevel
-			results = append(results, result)
-			s.statusDisplay.UpdateProgress(result)
+			// 两阶段扫描中待确认的探测结果不参与去重
+			if s.dedup != nil && (provisional == nil || !provisional(result)) && s.isFound(result) {
+				result.Duplicate = s.dedup.Seen(s.smartPathJoin(result.URL, result.Path), result)
+			}
+			// 只有被报告的结果才标记为目录，被过滤的响应不出现在目录树中
+			result.IsDirectory = s.isFound(result) && s.isDirectory(result)
+			// --exit-on-error：请求出错时停止扫描
+			s.abortOnError(result)
+			if s.notFound != nil && result.Error == nil && result.StatusCode == http.StatusNotFound {
+				s.notFound.Record(result.URL, result.Path)
+			}
+			// 记录会话进度，恢复时需要重新交给扫描器的结果（发现的路径和触发递归的目录）一并保存
+			if s.session != nil && (provisional == nil || !provisional(result)) {
+				s.session.Record(result, s.isFound(result) || s.shouldRecurse(result))
+			}
+			fanout.Publish(result)
 		}
 	}()
 
-	// 等待所有工作协程完成
+	// 等待所有工作协程完成，再等待结果收集完毕
 	wg.Wait()
+	close(resultChan)
+	<-collectorDone
+	fanout.Close()
 
-	// 如果启用递归扫描，对目录进行递归
-	if s.config.View.RecursiveScan && recursionLevel < 3 { // 限制递归深度为3
-		recursiveResults := s.performRecursiveScan(results, recursionLevel+1)
-		results = append(results, recursiveResults...)
-	}
-
-	return results, nil
+	return results
 }
 
 // performRecursiveScan 执行递归扫描
-func (s *Scanner) performRecursiveScan(results []ScanResult, recursionLevel int) []ScanResult {
+func (s *Scanner) performRecursiveScan(results []ScanResult, recursionLevel int) (recursiveResults []ScanResult, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("performRecursiveScan panic recovered: %v", r)
+			log.Printf("performRecursiveScan panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
 		}
 	}()
 
-	var recursiveResults []ScanResult
 	var directories []string
 
-	// 识别目录（200或403状态码）
+	// 识别需要递归的目录
 	for _, result := range results {
-		if (result.StatusCode == 200 || result.StatusCode == 403) && s.isDirectory(result) {
-			result.IsDirectory = true
-			directories = append(directories, result.URL)
+		if s.shouldRecurse(result) {
+			directories = append(directories, strings.TrimSuffix(s.smartPathJoin(result.URL, result.Path), "/")+"/")
 		}
 	}
 
 	if len(directories) == 0 {
-		return recursiveResults
+		return recursiveResults, nil
 	}
 
 	fmt.Printf("发现 %d 个目录，开始递归扫描...\n", len(directories))
 
-	// 为每个目录生成子路径
+	// 为每个目录生成子路径（使用相同的字典）
+	subPaths, pathCount, err := s.scanPaths()
+	if err != nil {
+		log.Printf("Failed to generate paths for recursive scan: %v", err)
+		return recursiveResults, nil
+	}
+
 	for _, directory := range directories {
-		subPaths, err := s.dictionary.GeneratePaths() // 使用相同的字典
-		if err != nil {
-			log.Printf("Failed to generate paths for directory %s: %v", directory, err)
-			continue
-		}
-		subResults, err := s.executeScan([]string{directory}, subPaths, recursionLevel)
+		subResults, err := s.executeScanPaths([]string{directory}, subPaths, pathCount, recursionLevel)
 		if err != nil {
 			log.Printf("Failed to scan directory %s: %v", directory, err)
 			continue // 忽略递归扫描错误
@@ -290,7 +579,48 @@ func (s *Scanner) performRecursiveScan(results []ScanResult, recursionLevel int)
 		recursiveResults = append(recursiveResults, subResults...)
 	}
 
-	return recursiveResults
+	return recursiveResults, nil
+}
+
+// defaultRecursionStatus 未配置--recursion-status时触发递归的状态码
+const defaultRecursionStatus = "200-399,401,403"
+
+// shouldRecurse 判断结果是否触发递归：状态码属于recursion-status、不属于recursion-exclude-status且是目录
+// （--force-recursive时不要求是目录）
+func (s *Scanner) shouldRecurse(result ScanResult) bool {
+	if result.Error != nil || result.Duplicate || !s.matchesRecursionStatus(result.StatusCode) {
+		return false
+	}
+	// 排除的状态码照常报告，只是不再递归（如403目录通常无法继续访问）
+	if matchesStatusSpecs(s.config.General.NoRecursionStatus, result.StatusCode) {
+		return false
+	}
+	return s.config.General.ForceRecursive || s.isDirectory(result)
+}
+
+// matchesRecursionStatus 检查状态码是否属于recursion-status
+func (s *Scanner) matchesRecursionStatus(statusCode int) bool {
+	specs := s.config.General.RecursionStatus
+	if len(specs) == 0 {
+		specs = []string{defaultRecursionStatus}
+	}
+	return matchesStatusSpecs(specs, statusCode)
+}
+
+// matchesStatusSpecs 检查状态码是否属于状态码列表（每项可以是逗号分隔的状态码或范围），无效的项被忽略
+func matchesStatusSpecs(specs []string, statusCode int) bool {
+	for _, spec := range specs {
+		statusCodes, err := config.ParseStatusCodes(spec)
+		if err != nil {
+			continue
+		}
+		for _, status := range statusCodes {
+			if statusCode == status {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // isDirectory 判断是否为目录
@@ -301,11 +631,26 @@ func (s *Scanner) isDirectory(result ScanResult) bool {
 		}
 	}()
 
-	// 检查URL是否以斜杠结尾
-	if strings.HasSuffix(result.URL, "/") {
+	// 检查路径是否以斜杠结尾
+	if strings.HasSuffix(result.Path, "/") {
 		return true
 	}
 
+	// 受保护区域的根目录通常返回401且没有响应内容可供判断，--recursion-status中明确列出401时没有扩展名的路径视为目录
+	// （默认状态码包含401，但需要认证的应用中几乎每个词都返回401，不能都当作目录递归）
+	if result.StatusCode == http.StatusUnauthorized && len(s.config.General.RecursionStatus) > 0 &&
+		matchesStatusSpecs(s.config.General.RecursionStatus, http.StatusUnauthorized) &&
+		path.Ext(strings.TrimSuffix(result.Path, "/")) == "" {
+		return true
+	}
+
+	// 检查是否重定向到带斜杠的同一路径，如 /admin -> /admin/
+	if redirect, _, _ := strings.Cut(result.Redirect, "?"); redirect != "" && result.Path != "" {
+		if strings.HasSuffix(redirect, "/"+strings.Trim(result.Path, "/")+"/") {
+			return true
+		}
+	}
+
 	// 检查响应头中的Content-Type
 	if result.Headers != nil {
 		contentType := result.Headers.Get("Content-Type")
@@ -322,7 +667,7 @@ func (s *Scanner) isDirectory(result ScanResult) bool {
 	return false
 }
 
-// normalizeTargets 标准化目标URL，确保末尾有斜杠
+// normalizeTargets 标准化目标URL，确保末尾有斜杠，配置了--subdirs时展开为每个目标的各个子目录
 func (s *Scanner) normalizeTargets(targets []string) []string {
 	defer func() {
 		if r := recover(); r != nil {
@@ -342,59 +687,162 @@ func (s *Scanner) normalizeTargets(targets []string) []string {
 		// 添加斜杠
 		normalized[i] = target + "/"
 	}
-	return normalized
+	return s.expandSubdirs(normalized)
+}
+
+// expandSubdirs 将目标与--subdirs中的子目录组合（支持逗号分隔和多次指定），空条目表示目标根目录
+func (s *Scanner) expandSubdirs(targets []string) []string {
+	var subdirs []string
+	for _, spec := range s.config.General.Subdirs {
+		for _, subdir := range strings.Split(spec, ",") {
+			subdirs = append(subdirs, strings.Trim(strings.TrimSpace(subdir), "/"))
+		}
+	}
+	if len(subdirs) == 0 {
+		return targets
+	}
+
+	seen := make(map[string]bool)
+	var expanded []string
+	for _, target := range targets {
+		for _, subdir := range subdirs {
+			// 目标已以斜杠结尾，子目录同样以斜杠结尾，与smartPathJoin拼接路径时不会产生双斜杠
+			full := target
+			if subdir != "" {
+				full += subdir + "/"
+			}
+			if !seen[full] {
+				seen[full] = true
+				expanded = append(expanded, full)
+			}
+		}
+	}
+	return expanded
 }
 
 // ScanTask 扫描任务
 type ScanTask struct {
 	Target string
 	Path   string
+	Probe  bool // 两阶段扫描的第一阶段，只发送HEAD请求
 }
 
 // worker 工作协程
 func (s *Scanner) worker(wg *sync.WaitGroup, taskChan <-chan ScanTask, resultChan chan<- ScanResult) {
+	for task := range taskChan {
+		if !s.runTask(task, resultChan) {
+			return
+		}
+	}
+}
+
+// runTask 执行单个扫描任务并发送结果，扫描停止时返回false。处理任务时发生panic则发送携带该错误的结果，
+// 工作协程继续处理剩余任务
+func (s *Scanner) runTask(task ScanTask, resultChan chan<- ScanResult) (ok bool) {
+	acquired := false
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Worker panic recovered: %v", r)
+			log.Printf("Worker panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			if acquired {
+				s.controller.Release()
+			}
+			ok = s.sendResult(resultChan, panicResult(task.Target, task.Path, r))
 		}
 	}()
 
-	for task := range taskChan {
-		select {
-		case <-s.ctx.Done():
-			return
-		default:
+	select {
+	case <-s.ctx.Done():
+		return false
+	default:
+	}
+
+	// 会话中已完成的路径不再发送请求，直接使用保存的结果；
+	// 两阶段扫描中有保存结果的路径仍需探测，以便在第二阶段使用保存的结果
+	if s.session != nil {
+		if saved, found := s.session.Lookup(task.Target, task.Path); found && (saved == nil || !task.Probe) {
+			if saved != nil {
+				return s.sendResult(resultChan, *saved)
+			}
+			return true
 		}
+	}
 
-		// 使用安全的扫描方式
-		result := s.scanPath(task.Target, task.Path)
+	// 连续出错被跳过的主机不再发送请求
+	if s.hostSkipped(task.Target) {
+		return true
+	}
 
-		// 应用智能延迟
-		if s.config.Connection.Delay > 0 {
-			// 从URL中提取主机名
-			if parsedURL, err := url.Parse(result.URL); err == nil {
-				smartDelay := s.requester.HostManager.GetSmartDelay(parsedURL.Host)
-				time.Sleep(smartDelay)
-			}
+	// 无头浏览器的请求不经过请求器，超过--max-rate时在这里等待，扫描停止时立即返回
+	if s.limiter != nil && s.config.View.Headless && s.headlessBrowser != nil {
+		if err := s.limiter.Wait(s.ctx); err != nil {
+			return false
 		}
+	}
 
-		select {
-		case resultChan <- result:
-		case <-s.ctx.Done():
-			return
+	// 等待控制器放行（暂停或超过并发上限时阻塞）
+	if s.controller != nil {
+		if !s.controller.Acquire(s.ctx) {
+			return false
 		}
+		acquired = true
+	}
+
+	// 使用安全的扫描方式
+	var result ScanResult
+	if task.Probe {
+		result = s.probePath(task.Target, task.Path)
+	} else {
+		result = s.scanPath(task.Target, task.Path)
+	}
+
+	if acquired {
+		acquired = false
+		s.controller.Release()
+	}
+	s.recordHostResult(result)
+
+	// 应用智能延迟
+	if s.config.Connection.Delay > 0 {
+		// 从URL中提取主机键（host:port）
+		if parsedURL, err := url.Parse(result.URL); err == nil {
+			smartDelay := s.requester.HostManager.GetSmartDelay(connection.HostKey(parsedURL))
+			time.Sleep(smartDelay)
+		}
+	}
+
+	return s.sendResult(resultChan, result)
+}
+
+// sendResult 发送结果，扫描停止时返回false
+func (s *Scanner) sendResult(resultChan chan<- ScanResult, result ScanResult) bool {
+	select {
+	case resultChan <- result:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// panicResult 扫描单个路径时发生panic，返回携带该错误的结果而不是空结果
+func panicResult(target, path string, recovered any) ScanResult {
+	return ScanResult{
+		URL:       target,
+		Path:      path,
+		Error:     utils.PanicError(recovered),
+		Timestamp: time.Now(),
 	}
 }
 
 // scanPath 扫描单个路径
-func (s *Scanner) scanPath(target, path string) ScanResult {
+func (s *Scanner) scanPath(target, path string) (result ScanResult) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("scanPath panic recovered: %v", r)
+			result = panicResult(target, path, r)
 		}
 	}()
 
-	result := ScanResult{
+	result = ScanResult{
 		URL:       target,
 		Path:      path,
 		Timestamp: time.Now(),
@@ -409,15 +857,28 @@ func (s *Scanner) scanPath(target, path string) ScanResult {
 
 	// 根据模式选择扫描方法
 	if s.config.View.Headless && s.headlessBrowser != nil {
-		// 使用headless浏览器扫描
-		headlessResult := s.headlessBrowser.ScanURL(fullURL)
+		// 使用headless浏览器扫描，为发现的页面在同一次加载中截图
+		var shouldCapture func(*connection.HeadlessResult) bool
+		if s.config.Output.ScreenshotDir != "" {
+			shouldCapture = func(headlessResult *connection.HeadlessResult) bool {
+				found := result
+				applyHeadlessResult(&found, headlessResult)
+				return s.shouldIncludeResult(found)
+			}
+		}
+		headlessResult := s.headlessBrowser.ScanURLWithScreenshot(fullURL, shouldCapture)
 		if headlessResult.Error != nil {
 			result.Error = headlessResult.Error
 		} else {
-			result.StatusCode = headlessResult.StatusCode
-			result.Size = headlessResult.ContentLength
-			result.Title = headlessResult.Title
-			result.Redirect = strings.Join(headlessResult.Redirects, " -> ")
+			applyHeadlessResult(&result, headlessResult)
+
+			if headlessResult.ScreenshotErr != nil {
+				log.Printf("Warning: Failed to capture screenshot for %s: %v", fullURL, headlessResult.ScreenshotErr)
+			} else if headlessResult.Screenshot != nil {
+				if err := s.saveScreenshot(fullURL, headlessResult.Screenshot); err != nil {
+					log.Printf("Warning: Failed to save screenshot for %s: %v", fullURL, err)
+				}
+			}
 		}
 	} else {
 		// 使用普通HTTP请求
@@ -432,23 +893,58 @@ func (s *Scanner) scanPath(target, path string) ScanResult {
 		result.Size = resp.ContentLength
 		result.Title = s.extractTitle(resp.Body)
 		result.Redirect = resp.Redirect
+		result.RawRedirect = resp.RawRedirect
 		result.Headers = resp.Headers
 		result.Body = resp.Body
+		result.ResponseTime = resp.ResponseTime
+	}
+
+	// 标记疑似被WAF拦截的响应
+	if s.shouldDetectWAF() {
+		result.Blocked = isWAFBlocked(result)
+	}
+
+	// 通过重放代理重新发送符合条件的结果
+	if result.Error == nil && s.requester != nil && s.requester.CanReplay() && s.shouldIncludeResult(result) {
+		if err := s.requester.Replay(fullURL); err != nil {
+			log.Printf("Warning: Failed to replay %s: %v", fullURL, err)
+		}
+	}
+
+	// 对发现的路径探测允许的HTTP方法
+	if s.shouldProbeMethods(result) {
+		result.AllowedMethods = s.probeAllowedMethods(fullURL)
 	}
 
 	return result
 }
 
+// applyHeadlessResult 将无头浏览器的扫描结果写入结果
+func applyHeadlessResult(result *ScanResult, headlessResult *connection.HeadlessResult) {
+	result.StatusCode = headlessResult.StatusCode
+	result.Size = headlessResult.ContentLength
+	result.Title = headlessResult.Title
+	result.Redirect = strings.Join(headlessResult.Redirects, " -> ")
+	result.Cookies = headlessResult.Cookies
+	result.ResponseTime = headlessResult.ResponseTime
+}
+
 // buildURL 构建完整URL
-func (s *Scanner) buildURL(target, path string) (string, error) {
+func (s *Scanner) buildURL(target, path string) (_ string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("buildURL panic recovered: %v", r)
+			err = utils.PanicError(r)
 		}
 	}()
 
-	// 智能添加路径分隔符
-	fullURL := s.smartPathJoin(target, path)
+	// 智能添加路径分隔符，参数模糊测试时路径是附加到端点URL上的查询串
+	var fullURL string
+	if s.config.General.ParamFuzz {
+		fullURL = target + path
+	} else {
+		fullURL = s.smartPathJoin(target, path)
+	}
 
 	// 验证URL格式
 	parsedURL, err := url.Parse(fullURL)
@@ -461,6 +957,11 @@ func (s *Scanner) buildURL(target, path string) (string, error) {
 		return "", fmt.Errorf("invalid URL: missing scheme or host")
 	}
 
+	// 附加缓存破坏参数（只用于实际请求，结果中的URL和路径不变）
+	if s.config.Request.CacheBust {
+		fullURL = addCacheBuster(fullURL, s.config.Request.CacheBustParam)
+	}
+
 	return fullURL, nil
 }
 
@@ -539,12 +1040,31 @@ func (s *Scanner) addResult(result ScanResult) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// 检查是否应该包含此结果
-	if s.shouldIncludeResult(result) {
+	// 检查是否应该包含此结果（出错的条目始终保留，以便之后用--retry-errors重试）
+	if result.Error != nil || s.shouldIncludeResult(result) {
 		s.results = append(s.results, result)
 	}
 }
 
+// Findings 返回已收集结果中发现的路径数量
+func (s *Scanner) Findings() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, result := range s.results {
+		if s.isFound(result) {
+			count++
+		}
+	}
+	return count
+}
+
+// isFound 判断结果是否为发现的路径：请求成功、不是404且通过所有过滤
+func (s *Scanner) isFound(result ScanResult) bool {
+	return result.Error == nil && result.StatusCode != http.StatusNotFound && s.shouldIncludeResult(result)
+}
+
 // shouldIncludeResult 检查是否应该包含结果
 func (s *Scanner) shouldIncludeResult(result ScanResult) bool {
 	defer func() {
@@ -553,6 +1073,60 @@ func (s *Scanner) shouldIncludeResult(result ScanResult) bool {
 		}
 	}()
 
+	if !s.matchesStatusFilters(result) {
+		return false
+	}
+
+	// 检查近似长度排除
+	for _, spec := range s.config.General.ExcludeLengthSimilar {
+		sizeRange, err := config.ParseLengthSimilar(spec)
+		if err != nil {
+			continue
+		}
+		if sizeRange.Contains(result.Size) {
+			return false
+		}
+	}
+
+	// 排除与基线响应相同的参数模糊测试结果
+	if s.matchesParamBaseline(result) {
+		return false
+	}
+
+	// 排除重复的结果
+	if result.Duplicate {
+		return false
+	}
+
+	// 排除疑似被WAF拦截的响应
+	if s.config.General.ExcludeBlocked && result.Blocked {
+		return false
+	}
+
+	// 检查已知的404特征
+	for _, spec := range s.config.General.Known404 {
+		signature, err := config.ParseKnown404(spec)
+		if err != nil {
+			continue
+		}
+		if signature.Matches(result.Size, result.Body) {
+			return false
+		}
+	}
+
+	// 检查响应时间过滤
+	if minTime := s.config.General.MinResponseTime; minTime > 0 && result.ResponseTime < time.Duration(minTime)*time.Millisecond {
+		return false
+	}
+	if maxTime := s.config.General.MaxResponseTime; maxTime > 0 && result.ResponseTime > time.Duration(maxTime)*time.Millisecond {
+		return false
+	}
+
+	return true
+}
+
+// matchesStatusFilters 检查结果状态码是否满足包含/排除状态码过滤
+func (s *Scanner) matchesStatusFilters(result ScanResult) bool {
 	// 检查状态码过滤
 	if len(s.config.General.IncludeStatus) > 0 {
 		found := false
@@ -592,6 +1166,30 @@ func (s *Scanner) shouldIncludeResult(result ScanResult) bool {
 	return true
 }
 
+// SaveFoundURLs 将发现路径的完整URL按发现顺序写入文件，每行一个
+func (s *Scanner) SaveFoundURLs(filename string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("SaveFoundURLs panic recovered: %v", r)
+			err = utils.PanicError(r)
+		}
+	}()
+
+	seen := make(map[string]bool)
+	var urls []string
+	for _, result := range s.GetResults() {
+		if !s.isFound(result) {
+			continue
+		}
+		fullURL := s.smartPathJoin(result.URL, result.Path)
+		if !seen[fullURL] {
+			seen[fullURL] = true
+			urls = append(urls, fullURL)
+		}
+	}
+	return report.SaveURLList(urls, filename)
+}
+
 // GetResults 获取结果
 func (s *Scanner) GetResults() []ScanResult {
 	defer func() {
@@ -628,10 +1226,11 @@ func (s *Scanner) Stop() {
 }
 
 // SaveResults 保存结果
-func (s *Scanner) SaveResults(filename string) error {
+func (s *Scanner) SaveResults(filename string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("SaveResults panic recovered: %v", r)
+			err = utils.PanicError(r)
 		}
 	}()
 
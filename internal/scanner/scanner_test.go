@@ -1,11 +1,56 @@
 package scanner
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/connection"
+	"dirsearch-go/internal/dictionary"
+	"dirsearch-go/internal/utils"
+	"dirsearch-go/internal/view"
 )
 
+// newTestScanner 创建不依赖字典和域名检测的测试扫描器
+func newTestScanner(t *testing.T, cfg *config.Config) *Scanner {
+	t.Helper()
+
+	if cfg.General.Threads <= 0 {
+		cfg.General.Threads = 2
+	}
+	if cfg.Connection.Timeout <= 0 {
+		cfg.Connection.Timeout = 5
+	}
+	if cfg.Request.HTTPMethod == "" {
+		cfg.Request.HTTPMethod = "GET"
+	}
+
+	requester, err := connection.NewRequester(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create requester: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	requester.SetContext(ctx)
+
+	return &Scanner{
+		config:        cfg,
+		requester:     requester,
+		statusDisplay: view.NewStatusDisplay(cfg),
+		controller:    NewScanController(cfg.General.Threads, cfg.General.Threads*2),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
 func TestSmartPathJoin(t *testing.T) {
 	// 创建测试配置
 	cfg := &config.Config{}
@@ -126,3 +171,396 @@ func TestBuildURL(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldRecurse(t *testing.T) {
+	tests := []struct {
+		name            string
+		recursionStatus []string
+		force           bool
+		result          ScanResult
+		expected        bool
+	}{
+		{name: "200目录", result: ScanResult{StatusCode: 200, Path: "admin/"}, expected: true},
+		{name: "200文件", result: ScanResult{StatusCode: 200, Path: "index.php"}, expected: false},
+		{name: "401目录", result: ScanResult{StatusCode: 401, Path: "private/"}, expected: true},
+		{name: "404目录", result: ScanResult{StatusCode: 404, Path: "missing/"}, expected: false},
+		{name: "重定向到带斜杠路径", result: ScanResult{StatusCode: 301, Path: "admin", Redirect: "https://example.com/admin/"}, expected: true},
+		{name: "重定向到其他路径", result: ScanResult{StatusCode: 302, Path: "admin", Redirect: "https://example.com/login"}, expected: false},
+		{name: "目录列表", result: ScanResult{StatusCode: 200, Path: "files", Headers: map[string][]string{"Content-Type": {"text/html"}}, Body: "<title>Index of /files</title>"}, expected: true},
+		{name: "自定义状态码排除401", recursionStatus: []string{"200"}, result: ScanResult{StatusCode: 401, Path: "private/"}, expected: false},
+		{name: "自定义状态码包含401", recursionStatus: []string{"200", "401"}, result: ScanResult{StatusCode: 401, Path: "private/"}, expected: true},
+		{name: "强制递归文件", force: true, result: ScanResult{StatusCode: 200, Path: "index.php"}, expected: true},
+		{name: "强制递归仍检查状态码", force: true, result: ScanResult{StatusCode: 404, Path: "index.php"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.General.RecursionStatus = tt.recursionStatus
+			cfg.General.ForceRecursive = tt.force
+			s := newTestScanner(t, cfg)
+
+			if got := s.shouldRecurse(tt.result); got != tt.expected {
+				t.Errorf("shouldRecurse(%+v) = %v, want %v", tt.result, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecurseIntoUnauthorizedDirectory(t *testing.T) {
+	tests := []struct {
+		name            string
+		recursionStatus []string
+		expectRecursion bool
+	}{
+		{name: "配置401", recursionStatus: []string{"200", "401"}, expectRecursion: true},
+		{name: "未配置401", recursionStatus: []string{"200"}, expectRecursion: false},
+		{name: "默认状态码", recursionStatus: nil, expectRecursion: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			requested := make(map[string]bool)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requested[r.URL.Path] = true
+				mu.Unlock()
+				switch {
+				case r.URL.Path == "/private" || r.URL.Path == "/private/private":
+					w.WriteHeader(http.StatusUnauthorized)
+				case r.URL.Path == "/private/secret":
+					w.WriteHeader(http.StatusOK)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			wordlist := filepath.Join(t.TempDir(), "words.txt")
+			if err := os.WriteFile(wordlist, []byte("private\nsecret\n"), 0644); err != nil {
+				t.Fatalf("Failed to write wordlist: %v", err)
+			}
+
+			cfg := &config.Config{}
+			cfg.View.RecursiveScan = true
+			cfg.General.RecursionStatus = tt.recursionStatus
+			cfg.Dictionary.Wordlists = []string{wordlist}
+			s := newTestScanner(t, cfg)
+			dict, err := dictionary.NewDictionary(cfg)
+			if err != nil {
+				t.Fatalf("NewDictionary failed: %v", err)
+			}
+			s.dictionary = dict
+
+			results, err := s.executeScan([]string{server.URL + "/"}, []string{"private", "secret"}, 0)
+			if err != nil {
+				t.Fatalf("executeScan failed: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if requested["/private/secret"] != tt.expectRecursion {
+				t.Errorf("Recursion into 401 directory = %v, want %v (requests: %v)", requested["/private/secret"], tt.expectRecursion, requested)
+			}
+			found := false
+			for _, result := range results {
+				if result.URL == server.URL+"/private/" && result.Path == "secret" && result.StatusCode == http.StatusOK {
+					found = true
+				}
+			}
+			if found != tt.expectRecursion {
+				t.Errorf("Found /private/secret = %v, want %v: %+v", found, tt.expectRecursion, results)
+			}
+		})
+	}
+}
+
+func TestRecursionExcludeStatus(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/forbidden/":
+			w.WriteHeader(http.StatusForbidden)
+		case "/open/", "/open/inner":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("forbidden/\nopen/\ninner\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.View.RecursiveScan = true
+	cfg.General.NoRecursionStatus = []string{"403"}
+	cfg.Dictionary.Wordlists = []string{wordlist}
+	s := newTestScanner(t, cfg)
+	dict, err := dictionary.NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+	s.dictionary = dict
+
+	results, err := s.executeScan([]string{server.URL + "/"}, []string{"forbidden/", "open/", "inner"}, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requested["/forbidden/inner"] {
+		t.Errorf("Recursed into excluded 403 directory: %v", requested)
+	}
+	if !requested["/open/inner"] {
+		t.Errorf("Expected recursion into 200 directory: %v", requested)
+	}
+
+	// 排除递归的目录仍然出现在结果中
+	reported := false
+	for _, result := range results {
+		if result.Path == "forbidden/" && result.StatusCode == http.StatusForbidden {
+			reported = true
+		}
+	}
+	if !reported {
+		t.Errorf("Expected 403 directory to be reported: %+v", results)
+	}
+}
+
+func TestScanPathPanicBecomesResultError(t *testing.T) {
+	s := newTestScanner(t, &config.Config{View: config.ViewConfig{Headless: true}})
+	// 未启动的无头浏览器没有上下文，ScanURL会直接panic，由scanPath自己恢复（不经过请求器的recover）
+	s.headlessBrowser = &connection.HeadlessBrowser{}
+
+	result := s.scanPath("http://127.0.0.1/", "admin")
+	if !errors.Is(result.Error, utils.ErrPanic) {
+		t.Fatalf("Result error = %v, want a recovered panic", result.Error)
+	}
+	if !strings.HasPrefix(result.Error.Error(), utils.ErrPanic.Error()) {
+		t.Errorf("Result error = %v, want the panic recovered by scanPath", result.Error)
+	}
+	if result.URL != "http://127.0.0.1/" || result.Path != "admin" {
+		t.Errorf("Result target = %q %q, want the scanned target and path", result.URL, result.Path)
+	}
+}
+
+func TestBuildURLPanicReturnsError(t *testing.T) {
+	// 没有配置时读取配置会panic
+	s := &Scanner{}
+
+	fullURL, err := s.buildURL("http://127.0.0.1/", "admin")
+	if !errors.Is(err, utils.ErrPanic) {
+		t.Fatalf("buildURL error = %v, want a recovered panic", err)
+	}
+	if fullURL != "" {
+		t.Errorf("buildURL URL = %q, want empty on error", fullURL)
+	}
+}
+
+func TestWorkerPanicBecomesResultError(t *testing.T) {
+	s := newTestScanner(t, &config.Config{General: config.GeneralConfig{Threads: 1}})
+	// 检查主机是否被跳过时访问请求器的HostManager，为nil时在工作协程中panic
+	s.config.Connection.MaxConsecutiveErrors = 5
+	s.requester.HostManager = nil
+
+	results, err := s.executeScan([]string{"http://127.0.0.1/"}, []string{"a", "b"}, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+	// 发生panic后工作协程继续处理剩余任务，每个任务都得到携带错误的结果
+	if len(results) != 2 {
+		t.Fatalf("Got %d results, want 2: %+v", len(results), results)
+	}
+	for _, result := range results {
+		if !errors.Is(result.Error, utils.ErrPanic) {
+			t.Errorf("Result %q error = %v, want a recovered panic", result.Path, result.Error)
+		}
+	}
+}
+
+func TestSubdirs(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []string
+		subdirs  []string
+		expected []string
+	}{
+		{
+			name:     "未配置子目录",
+			targets:  []string{"https://example.com"},
+			expected: []string{"https://example.com/"},
+		},
+		{
+			name:     "逗号分隔",
+			targets:  []string{"https://example.com/"},
+			subdirs:  []string{"admin/,/api"},
+			expected: []string{"https://example.com/admin/", "https://example.com/api/"},
+		},
+		{
+			name:     "空条目表示根目录",
+			targets:  []string{"https://a.example.com", "https://b.example.com/app/"},
+			subdirs:  []string{",admin/", "admin"},
+			expected: []string{"https://a.example.com/", "https://a.example.com/admin/", "https://b.example.com/app/", "https://b.example.com/app/admin/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scanner{config: &config.Config{}}
+			s.config.General.Subdirs = tt.subdirs
+			got := s.normalizeTargets(tt.targets)
+			if strings.Join(got, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("normalizeTargets(%v) = %v, want %v", tt.targets, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("扫描子目录下的路径", func(t *testing.T) {
+		var mu sync.Mutex
+		requested := make(map[string]bool)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requested[r.URL.Path] = true
+			mu.Unlock()
+			http.NotFound(w, r)
+		}))
+		defer server.Close()
+
+		cfg := &config.Config{}
+		cfg.General.Subdirs = []string{"admin/,api/"}
+		s := newTestScanner(t, cfg)
+		if _, err := s.executeScan(s.normalizeTargets([]string{server.URL}), []string{"login"}, 0); err != nil {
+			t.Fatalf("executeScan failed: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, path := range []string{"/admin/login", "/api/login"} {
+			if !requested[path] {
+				t.Errorf("Expected %s to be requested, got %v", path, requested)
+			}
+		}
+		if requested["/login"] {
+			t.Errorf("Root should not be scanned without an empty subdir entry: %v", requested)
+		}
+	})
+}
+
+func TestMaxConsecutiveErrorsSkipsHost(t *testing.T) {
+	// 关闭连接使每个请求都出错
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 1
+	cfg.Connection.MaxConsecutiveErrors = 3
+	s := newTestScanner(t, cfg)
+	s.controller = nil
+
+	paths := []string{"a", "b", "c", "d", "e", "f"}
+	results, err := s.executeScan([]string{down.URL + "/", up.URL + "/"}, paths, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		if result.Error != nil {
+			counts["error"]++
+		} else if result.URL == up.URL+"/" {
+			counts["up"]++
+		}
+	}
+	if counts["error"] != cfg.Connection.MaxConsecutiveErrors {
+		t.Errorf("Got %d error results, want the down host skipped after %d", counts["error"], cfg.Connection.MaxConsecutiveErrors)
+	}
+	if counts["up"] != len(paths) {
+		t.Errorf("Got %d results for the healthy host, want %d", counts["up"], len(paths))
+	}
+}
+
+func TestSaveFoundURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin", "/api/users":
+			w.WriteHeader(http.StatusOK)
+		case "/private/":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 1
+	s := newTestScanner(t, cfg)
+	s.controller = nil
+
+	paths := []string{"admin", "missing", "private/", "api/users", "old.bak"}
+	if _, err := s.executeScan([]string{server.URL + "/"}, paths, 0); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "urls.txt")
+	if err := s.SaveFoundURLs(filename); err != nil {
+		t.Fatalf("SaveFoundURLs failed: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	want := server.URL + "/admin\n" + server.URL + "/private/\n" + server.URL + "/api/users\n"
+	if string(data) != want {
+		t.Errorf("URL list:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestIsDirectoryOnlyForFoundResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/":
+			w.WriteHeader(http.StatusOK)
+		case "/blocked/":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.ExcludeStatus = []string{"403"}
+	s := newTestScanner(t, cfg)
+
+	results, err := s.executeScan([]string{server.URL + "/"}, []string{"admin/", "blocked/"}, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	for _, result := range results {
+		want := result.Path == "admin/"
+		if result.IsDirectory != want {
+			t.Errorf("%s (%d) IsDirectory = %v, want %v", result.Path, result.StatusCode, result.IsDirectory, want)
+		}
+	}
+}
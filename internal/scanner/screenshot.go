@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// unsafeFilenameChars 文件名中不安全的字符
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// screenshotFilename 根据URL的主机和路径生成截图文件名，附加完整URL的短哈希，
+// 只有查询参数不同的URL（如/a?x=1和/a?x=2）不会写入同一个文件
+func screenshotFilename(fullURL string) string {
+	name := fullURL
+	if parsedURL, err := url.Parse(fullURL); err == nil {
+		name = parsedURL.Host + "_" + strings.Trim(parsedURL.Path, "/")
+	}
+
+	name = strings.Trim(unsafeFilenameChars.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		name = "root"
+	}
+	sum := sha256.Sum256([]byte(fullURL))
+	return name + "_" + hex.EncodeToString(sum[:4]) + ".png"
+}
+
+// saveScreenshot 将无头浏览器扫描时截取的截图保存到截图目录
+func (s *Scanner) saveScreenshot(fullURL string, data []byte) error {
+	if err := os.MkdirAll(s.config.Output.ScreenshotDir, 0755); err != nil {
+		return fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+
+	filename := filepath.Join(s.config.Output.ScreenshotDir, screenshotFilename(fullURL))
+	return os.WriteFile(filename, data, 0644)
+}
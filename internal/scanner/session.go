@@ -0,0 +1,189 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"dirsearch-go/internal/session"
+)
+
+// sessionSaveInterval 扫描过程中定期保存会话的间隔
+const sessionSaveInterval = 30 * time.Second
+
+// scanSession --session的扫描会话：记录已完成的目标/路径和需要保留的结果，定期写入会话文件
+type scanSession struct {
+	path      string
+	targets   []string
+	completed map[string]map[string]bool
+	results   map[string]map[string]ScanResult
+	dirty     bool
+	mu        sync.Mutex
+}
+
+// openSession 打开会话文件：文件存在时恢复之前的目标和进度，否则使用本次的目标开始新会话
+func openSession(path string, targets []string) (*scanSession, error) {
+	sess := &scanSession{
+		path:      path,
+		targets:   targets,
+		completed: make(map[string]map[string]bool),
+		results:   make(map[string]map[string]ScanResult),
+	}
+
+	state, err := session.Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return sess, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(state.Targets) > 0 {
+		sess.targets = state.Targets
+	}
+	count := 0
+	for target, paths := range state.Completed {
+		for _, path := range paths {
+			sess.markCompleted(target, path)
+			count++
+		}
+	}
+	for _, result := range state.Results {
+		sess.keepResult(result)
+	}
+	fmt.Printf("正在恢复会话 %s: %d 个目标，%d 个已完成的请求\n", path, len(sess.targets), count)
+	return sess, nil
+}
+
+// markCompleted 记录已完成的目标/路径，调用方需持有锁或独占会话
+func (sess *scanSession) markCompleted(target, path string) {
+	paths := sess.completed[target]
+	if paths == nil {
+		paths = make(map[string]bool)
+		sess.completed[target] = paths
+	}
+	paths[path] = true
+}
+
+// keepResult 保存需要在恢复时重新交给扫描器的结果，调用方需持有锁或独占会话
+func (sess *scanSession) keepResult(result ScanResult) {
+	results := sess.results[result.URL]
+	if results == nil {
+		results = make(map[string]ScanResult)
+		sess.results[result.URL] = results
+	}
+	results[result.Path] = result
+}
+
+// Lookup 检查目标/路径是否已在之前完成，已完成时返回保存的结果（没有保存结果时为nil）
+func (sess *scanSession) Lookup(target, path string) (*ScanResult, bool) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if !sess.completed[target][path] {
+		return nil, false
+	}
+	if result, ok := sess.results[target][path]; ok {
+		return &result, true
+	}
+	return nil, true
+}
+
+// Record 记录完成的请求，出错的请求不算完成，恢复时会重新发送；keep为true时同时保存结果
+func (sess *scanSession) Record(result ScanResult, keep bool) {
+	if result.Error != nil {
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	sess.markCompleted(result.URL, result.Path)
+	if keep {
+		sess.keepResult(result)
+	}
+	sess.dirty = true
+}
+
+// Save 将当前进度写入会话文件
+func (sess *scanSession) Save() error {
+	sess.mu.Lock()
+	state := &session.State{
+		Targets:   sess.targets,
+		Completed: make(map[string][]string, len(sess.completed)),
+		SavedAt:   time.Now(),
+	}
+	for target, paths := range sess.completed {
+		for path := range paths {
+			state.Completed[target] = append(state.Completed[target], path)
+		}
+	}
+	for _, results := range sess.results {
+		for _, result := range results {
+			state.Results = append(state.Results, result)
+		}
+	}
+	sess.dirty = false
+	sess.mu.Unlock()
+
+	return session.Save(sess.path, state)
+}
+
+// autosave 每隔interval在有新进度时保存会话，返回停止函数
+func (sess *scanSession) autosave(interval time.Duration) func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Session autosave panic recovered: %v", r)
+			}
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sess.mu.Lock()
+				dirty := sess.dirty
+				sess.mu.Unlock()
+				if !dirty {
+					continue
+				}
+				if err := sess.Save(); err != nil {
+					log.Printf("Warning: Failed to save session: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// Close 结束会话：扫描完整结束时删除会话文件，被中断或出错时保存进度以便下次恢复
+func (sess *scanSession) Close(finished bool) {
+	if finished {
+		if err := os.Remove(sess.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Warning: Failed to remove session file: %v", err)
+		}
+		return
+	}
+
+	if err := sess.Save(); err != nil {
+		log.Printf("Warning: Failed to save session: %v", err)
+		return
+	}
+	fmt.Printf("扫描进度已保存到会话文件: %s\n", sess.path)
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/session"
+)
+
+func TestSessionResume(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/admin" || r.URL.Path == "/login" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	target := server.URL + "/"
+	path := filepath.Join(t.TempDir(), "scan.session")
+	err := session.Save(path, &session.State{
+		Targets:   []string{target},
+		Completed: map[string][]string{target: {"admin", "old"}},
+		Results:   []ScanResult{{URL: target, Path: "admin", StatusCode: http.StatusOK, Title: "saved"}},
+	})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	s := newTestScanner(t, &config.Config{})
+	sess, err := openSession(path, nil)
+	if err != nil {
+		t.Fatalf("openSession failed: %v", err)
+	}
+	if len(sess.targets) != 1 || sess.targets[0] != target {
+		t.Fatalf("Restored targets = %v, want [%s]", sess.targets, target)
+	}
+	s.session = sess
+
+	results, err := s.executeScan(sess.targets, []string{"admin", "old", "login", "missing"}, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	sort.Strings(requested)
+	got := strings.Join(requested, ",")
+	mu.Unlock()
+	if got != "/login,/missing" {
+		t.Errorf("Requested %s, want only the paths not completed in the session", got)
+	}
+
+	var saved bool
+	for _, result := range results {
+		if result.Path == "admin" && result.Title == "saved" {
+			saved = true
+		}
+	}
+	if !saved {
+		t.Errorf("Results %+v do not contain the result restored from the session", results)
+	}
+
+	// 中断时保存进度，新完成的路径和发现的结果写入会话文件
+	sess.Close(false)
+	state, err := session.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	completed := state.Completed[target]
+	sort.Strings(completed)
+	if strings.Join(completed, ",") != "admin,login,missing,old" {
+		t.Errorf("Completed = %v, want all four paths", completed)
+	}
+	var paths []string
+	for _, result := range state.Results {
+		paths = append(paths, result.Path)
+	}
+	sort.Strings(paths)
+	if strings.Join(paths, ",") != "admin,login" {
+		t.Errorf("Saved results %v, want admin and login", paths)
+	}
+}
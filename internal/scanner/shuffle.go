@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// shuffleWindow 低内存模式下打乱顺序使用的缓冲路径数，避免把整个字典读入内存
+const shuffleWindow = 4096
+
+// shuffleRand 获取--shuffle使用的随机数生成器，未指定种子时使用当前时间并打印种子以便复现
+func (s *Scanner) shuffleRand() *rand.Rand {
+	if s.rng == nil {
+		seed := s.config.General.ShuffleSeed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+			fmt.Printf("随机扫描顺序种子: %d (使用 --shuffle-seed 复现)\n", seed)
+		}
+		s.rng = rand.New(rand.NewSource(seed))
+	}
+	return s.rng
+}
+
+// shufflePaths 将路径来源包装为随机顺序的来源；window<=0时打乱全部路径，否则按window大小分批打乱
+func shufflePaths(paths pathSource, rng *rand.Rand, window int) pathSource {
+	return func(fn func(path string) bool) error {
+		var buf []string
+		stopped := false
+
+		flush := func() bool {
+			rng.Shuffle(len(buf), func(i, j int) {
+				buf[i], buf[j] = buf[j], buf[i]
+			})
+			for _, path := range buf {
+				if !fn(path) {
+					stopped = true
+					return false
+				}
+			}
+			buf = buf[:0]
+			return true
+		}
+
+		err := paths(func(path string) bool {
+			buf = append(buf, path)
+			if window > 0 && len(buf) >= window {
+				return flush()
+			}
+			return true
+		})
+		if err != nil || stopped {
+			return err
+		}
+
+		flush()
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+// collectTaskPaths 按目标收集任务来源发送的路径
+func collectTaskPaths(t *testing.T, tasks taskSource) map[string][]string {
+	t.Helper()
+	byTarget := make(map[string][]string)
+	if err := tasks(func(task ScanTask) bool {
+		byTarget[task.Target] = append(byTarget[task.Target], task.Path)
+		return true
+	}); err != nil {
+		t.Fatalf("task source failed: %v", err)
+	}
+	return byTarget
+}
+
+func TestShuffledTaskOrder(t *testing.T) {
+	var paths []string
+	for i := 0; i < 50; i++ {
+		paths = append(paths, fmt.Sprintf("path%02d", i))
+	}
+	targets := []string{"http://a.example.com/", "http://b.example.com/"}
+
+	newShuffleScanner := func() *Scanner {
+		cfg := &config.Config{}
+		cfg.General.Shuffle = true
+		cfg.General.ShuffleSeed = 42
+		return newTestScanner(t, cfg)
+	}
+
+	first := collectTaskPaths(t, newShuffleScanner().scanTasks(targets, slicePaths(paths), 0))
+	second := collectTaskPaths(t, newShuffleScanner().scanTasks(targets, slicePaths(paths), 0))
+
+	input := strings.Join(paths, ",")
+	for _, target := range targets {
+		got := first[target]
+		if strings.Join(got, ",") == input {
+			t.Errorf("%s: shuffled order equals input order", target)
+		}
+
+		sorted := append([]string(nil), got...)
+		sort.Strings(sorted)
+		if strings.Join(sorted, ",") != input {
+			t.Errorf("%s: shuffled paths do not cover all input paths: %v", target, got)
+		}
+
+		if strings.Join(second[target], ",") != strings.Join(got, ",") {
+			t.Errorf("%s: same seed produced different orders", target)
+		}
+	}
+	if strings.Join(first[targets[0]], ",") == strings.Join(first[targets[1]], ",") {
+		t.Error("Expected each target to get its own order")
+	}
+
+	// 未启用时保持字典顺序
+	ordered := collectTaskPaths(t, newTestScanner(t, &config.Config{}).scanTasks(targets[:1], slicePaths(paths), 0))
+	if strings.Join(ordered[targets[0]], ",") != input {
+		t.Errorf("Expected wordlist order without --shuffle, got %v", ordered[targets[0]])
+	}
+}
+
+func TestShufflePathsWindow(t *testing.T) {
+	var paths []string
+	for i := 0; i < 20; i++ {
+		paths = append(paths, fmt.Sprintf("p%02d", i))
+	}
+
+	var got []string
+	err := shufflePaths(slicePaths(paths), rand.New(rand.NewSource(1)), 8)(func(path string) bool {
+		got = append(got, path)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("shufflePaths failed: %v", err)
+	}
+
+	sorted := append([]string(nil), got...)
+	sort.Strings(sorted)
+	if strings.Join(sorted, ",") != strings.Join(paths, ",") {
+		t.Errorf("Windowed shuffle lost paths: %v", got)
+	}
+
+	// 分批打乱时每批只包含该窗口内的路径
+	firstWindow := append([]string(nil), got[:8]...)
+	sort.Strings(firstWindow)
+	if strings.Join(firstWindow, ",") != strings.Join(paths[:8], ",") {
+		t.Errorf("First window = %v, want a permutation of %v", got[:8], paths[:8])
+	}
+
+	// 回调返回false时停止
+	count := 0
+	shufflePaths(slicePaths(paths), rand.New(rand.NewSource(1)), 0)(func(string) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Errorf("Expected shuffle to stop after callback returned false, got %d callbacks", count)
+	}
+}
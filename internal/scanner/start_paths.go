@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"strings"
+)
+
+// startPaths 解析--start-paths，支持逗号分隔和多次指定，去掉开头的斜杠并去重，保留末尾斜杠以便识别为目录
+func (s *Scanner) startPaths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, spec := range s.config.General.StartPaths {
+		for _, path := range strings.Split(spec, ",") {
+			path = strings.TrimLeft(strings.TrimSpace(path), "/")
+			if path == "" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// priorityPaths 首轮扫描中先对所有目标发送的路径：--start-paths在前，其次是--priority-file生成的路径
+func (s *Scanner) priorityPaths() []string {
+	paths := s.startPaths()
+	if s.dictionary == nil {
+		return paths
+	}
+
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		seen[path] = true
+	}
+	for _, path := range s.dictionary.PriorityPaths() {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// excludePaths 从路径来源中跳过指定的路径
+func excludePaths(paths pathSource, skip map[string]bool) pathSource {
+	return func(fn func(path string) bool) error {
+		return paths(func(path string) bool {
+			if skip[path] {
+				return true
+			}
+			return fn(path)
+		})
+	}
+}
+
+// startPathTasks 先为所有目标发送起始路径，再发送字典路径（跳过与起始路径重复的），返回任务来源和任务总数
+func (s *Scanner) startPathTasks(targets []string, start []string, paths pathSource) (taskSource, int) {
+	skip := make(map[string]bool, len(start))
+	for _, path := range start {
+		skip[path] = true
+	}
+	remaining := excludePaths(paths, skip)
+
+	// 统计去重后的字典路径数量，用于显示进度
+	count := 0
+	remaining(func(path string) bool {
+		count++
+		return true
+	})
+
+	wordlistTasks := s.scanTasks(targets, remaining, 0)
+	tasks := func(send func(task ScanTask) bool) error {
+		for _, path := range start {
+			for _, target := range targets {
+				if !send(ScanTask{Target: target, Path: path}) {
+					return nil
+				}
+			}
+		}
+		return wordlistTasks(send)
+	}
+	return tasks, len(targets) * (len(start) + count)
+}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/dictionary"
+)
+
+func TestStartPaths(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/", "/api/users", "/login":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordlist, []byte("login\nusers\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.General.Threads = 1
+	cfg.General.StartPaths = []string{"/api/,/admin/", "login"}
+	cfg.View.RecursiveScan = true
+	cfg.Dictionary.Wordlists = []string{wordlist}
+	s := newTestScanner(t, cfg)
+	dict, err := dictionary.NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+	s.dictionary = dict
+	// 单个工作协程按发送顺序处理任务
+	s.controller = nil
+
+	if _, err := s.executeScan([]string{server.URL + "/"}, []string{"login", "users"}, 0); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// 起始路径最先发送，字典中重复的login不再请求
+	wantFirst := []string{"/api/", "/admin/", "/login", "/users"}
+	if len(requested) < len(wantFirst) {
+		t.Fatalf("Got requests %v, want at least %v", requested, wantFirst)
+	}
+	for i, path := range wantFirst {
+		if requested[i] != path {
+			t.Fatalf("Request order %v, want prefix %v", requested, wantFirst)
+		}
+	}
+	count := make(map[string]int)
+	for _, path := range requested {
+		count[path]++
+	}
+	if count["/login"] != 1 {
+		t.Errorf("/login requested %d times, want 1: %v", count["/login"], requested)
+	}
+
+	// 起始目录参与递归
+	if count["/api/users"] != 1 {
+		t.Errorf("Recursion into start path /api/ did not request /api/users: %v", requested)
+	}
+}
+
+func TestPriorityPathsEmittedFirst(t *testing.T) {
+	dir := t.TempDir()
+	wordlist := filepath.Join(dir, "words.txt")
+	priority := filepath.Join(dir, "priority.txt")
+	if err := os.WriteFile(wordlist, []byte("index\nadmin\nabout\n"), 0644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+	if err := os.WriteFile(priority, []byte("admin\nbackup\n"), 0644); err != nil {
+		t.Fatalf("Failed to write priority file: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.General.StartPaths = []string{"/api/"}
+	cfg.Dictionary.Wordlists = []string{wordlist}
+	cfg.Dictionary.PriorityFile = priority
+	s := newTestScanner(t, cfg)
+	dict, err := dictionary.NewDictionary(cfg)
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+	s.dictionary = dict
+
+	paths, pathCount, err := s.scanPaths()
+	if err != nil {
+		t.Fatalf("scanPaths failed: %v", err)
+	}
+	targets := []string{"http://a.example.com/", "http://b.example.com/"}
+	tasks, count := s.startPathTasks(targets, s.priorityPaths(), paths)
+
+	var got []string
+	tasks(func(task ScanTask) bool {
+		host := strings.TrimSuffix(strings.TrimPrefix(task.Target, "http://"), ".example.com/")
+		got = append(got, host+":"+task.Path)
+		return true
+	})
+
+	// 起始路径和高价值路径先对所有目标发送，之后每个目标按字典顺序发送其余路径
+	want := "a:api/,b:api/,a:admin,b:admin,a:backup,b:backup,a:index,a:about,b:index,b:about"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Task order %s, want %s", strings.Join(got, ","), want)
+	}
+	if count != len(got) || pathCount != 4 {
+		t.Errorf("Task count = %d (paths %d), want %d tasks for 4 paths", count, pathCount, len(got))
+	}
+}
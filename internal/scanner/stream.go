@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"log"
+	"time"
+
+	"dirsearch-go/internal/report"
+)
+
+// StreamResults 扫描过程中将保留的结果以JSON Lines格式实时写入filename，
+// 每隔--flush-interval刷新到磁盘。需要在Scan之前调用，扫描结束时关闭
+func (s *Scanner) StreamResults(filename string) error {
+	interval := time.Duration(s.config.Output.FlushInterval * float64(time.Second))
+	stream, err := report.NewStreamWriter(filename, interval)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stream != nil {
+		s.stream.Close()
+	}
+	s.stream = stream
+	return nil
+}
+
+// writeStream 将与报告相同的结果（出错的条目和通过过滤的结果）写入流式报告
+func (s *Scanner) writeStream(result ScanResult) {
+	if result.Error == nil && !s.shouldIncludeResult(result) {
+		return
+	}
+	if err := s.stream.WriteResult(result); err != nil {
+		log.Printf("Warning: Failed to write stream report: %v", err)
+	}
+}
+
+// closeStream 关闭流式报告，写入缓冲区中剩余的结果
+func (s *Scanner) closeStream() {
+	if s.stream == nil {
+		return
+	}
+	if err := s.stream.Close(); err != nil {
+		log.Printf("Warning: Failed to close stream report: %v", err)
+	}
+}
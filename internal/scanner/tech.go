@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"dirsearch-go/internal/dictionary"
+)
+
+// detectTech 请求每个主机的首页，根据响应头识别技术栈并向字典追加对应的扩展名
+func (s *Scanner) detectTech(targets []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("detectTech panic recovered: %v", r)
+		}
+	}()
+
+	if !s.config.Dictionary.AutoTech || s.dictionary == nil || s.requester == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var techs []string
+	for _, root := range warmupURLs(targets) {
+		resp, err := s.requester.Request(root)
+		if err != nil {
+			log.Printf("Warning: Technology detection request to %s failed: %v", root, err)
+			continue
+		}
+		for _, tech := range dictionary.DetectTech(resp.Headers) {
+			if !seen[tech] {
+				seen[tech] = true
+				techs = append(techs, tech)
+			}
+		}
+	}
+
+	if len(techs) == 0 {
+		fmt.Println("未从响应头识别到目标技术栈，使用配置的扩展名")
+		return
+	}
+
+	extensions, err := dictionary.TechExtensions(techs)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
+	added := s.dictionary.AddExtensions(extensions)
+	fmt.Printf("识别到技术栈: %s，追加扩展名: %s\n", strings.Join(techs, ", "), strings.Join(added, ", "))
+}
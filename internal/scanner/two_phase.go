@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"dirsearch-go/internal/utils"
+)
+
+// runTwoPhase 两阶段扫描：第一阶段对所有路径发送HEAD请求，第二阶段只对候选结果用配置的方法重新请求。
+// 发生panic时返回已得到的结果和错误
+func (s *Scanner) runTwoPhase(tasks taskSource, recursionLevel int) (results []ScanResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("runTwoPhase panic recovered: %v\nStack trace: %s", r, debug.Stack())
+			err = utils.PanicError(r)
+		}
+	}()
+
+	// 第一阶段：HEAD探测，候选结果留到第二阶段确认后再计入进度
+	probeTasks := func(send func(task ScanTask) bool) error {
+		return tasks(func(task ScanTask) bool {
+			task.Probe = true
+			return send(task)
+		})
+	}
+	probeResults := s.runTasks(probeTasks, recursionLevel, s.isTwoPhaseCandidate)
+
+	var candidates []ScanTask
+	for _, result := range probeResults {
+		if s.isTwoPhaseCandidate(result) {
+			candidates = append(candidates, ScanTask{Target: result.URL, Path: result.Path})
+		} else {
+			results = append(results, result)
+		}
+	}
+
+	if len(candidates) == 0 || s.ctx.Err() != nil {
+		return results, nil
+	}
+
+	// 第二阶段：只对候选结果发送完整请求，获取标题、响应体并应用其余过滤
+	fmt.Printf("\n两阶段扫描: HEAD探测发现 %d 个候选结果，开始确认...\n", len(candidates))
+	confirmTasks := func(send func(task ScanTask) bool) error {
+		for _, task := range candidates {
+			if !send(task) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	results = append(results, s.runTasks(confirmTasks, recursionLevel, nil)...)
+	return results, nil
+}
+
+// isTwoPhaseCandidate 判断HEAD探测结果是否需要在第二阶段确认
+func (s *Scanner) isTwoPhaseCandidate(result ScanResult) bool {
+	if result.Error != nil || result.StatusCode == http.StatusNotFound {
+		return false
+	}
+
+	// 服务器不支持HEAD时无法判断，交给第二阶段
+	if result.StatusCode == http.StatusMethodNotAllowed || result.StatusCode == http.StatusNotImplemented {
+		return true
+	}
+
+	// 第一阶段没有响应体，只应用状态码过滤
+	return s.matchesStatusFilters(result)
+}
+
+// probePath 使用HEAD请求探测单个路径，不读取响应体，也不触发截图和重放
+func (s *Scanner) probePath(target, path string) (result ScanResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("probePath panic recovered: %v", r)
+			result = panicResult(target, path, r)
+		}
+	}()
+
+	result = ScanResult{
+		URL:       target,
+		Path:      path,
+		Timestamp: time.Now(),
+	}
+
+	fullURL, err := s.buildURL(target, path)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to build URL: %w", err)
+		return result
+	}
+
+	resp, err := s.requester.RequestMethod(fullURL, http.MethodHead)
+	if err != nil {
+		result.Error = fmt.Errorf("request failed: %w", err)
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.Size = resp.ContentLength
+	result.Redirect = resp.Redirect
+	result.RawRedirect = resp.RawRedirect
+	result.Headers = resp.Headers
+	result.ResponseTime = resp.ResponseTime
+	return result
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestTwoPhaseScan(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method] = append(requests[r.Method], strings.TrimPrefix(r.URL.Path, "/"))
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/admin":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><title>Admin</title></html>")
+		case "/secret":
+			w.WriteHeader(http.StatusForbidden)
+		case "/nohead":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			fmt.Fprint(w, "ok")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.General.TwoPhase = true
+	cfg.General.ExcludeStatus = []string{"403"}
+	s := newTestScanner(t, cfg)
+
+	paths := []string{"admin", "secret", "nohead", "missing"}
+	results, err := s.executeScan([]string{server.URL + "/"}, paths, 0)
+	if err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(requests[http.MethodHead])
+	sort.Strings(requests[http.MethodGet])
+
+	if got, want := strings.Join(requests[http.MethodHead], ","), "admin,missing,nohead,secret"; got != want {
+		t.Errorf("Phase one HEAD requests = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(requests[http.MethodGet], ","), "admin,nohead"; got != want {
+		t.Errorf("Phase two GET requests = %q, want %q", got, want)
+	}
+
+	if len(results) != len(paths) {
+		t.Fatalf("Got %d results, want %d: %+v", len(results), len(paths), results)
+	}
+	for _, result := range results {
+		if result.Path == "admin" && result.Title != "Admin" {
+			t.Errorf("Expected title from phase two GET, got %q", result.Title)
+		}
+		if result.Path == "nohead" && result.StatusCode != http.StatusOK {
+			t.Errorf("Expected nohead to be confirmed with GET status 200, got %d", result.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"net/http"
+	"strings"
+)
+
+// wafBlockStatuses WAF拦截请求时常用的状态码
+var wafBlockStatuses = map[int]bool{
+	http.StatusNotAcceptable:   true, // 406，ModSecurity默认
+	419:                        true, // 部分WAF/CSRF防护
+	http.StatusTooManyRequests: true, // 429，限速
+}
+
+// wafHeaders WAF或CDN防护添加的响应头
+var wafHeaders = []string{
+	"Cf-Ray",
+	"X-Sucuri-Id",
+	"X-Sucuri-Block",
+	"X-Iinfo",
+	"X-Cdn",
+	"Akamai-Grn",
+}
+
+// wafBodyMarkers 常见WAF拦截页面中的特征文本（小写）
+var wafBodyMarkers = []string{
+	"attention required! | cloudflare",
+	"cloudflare ray id",
+	"access denied - sucuri website firewall",
+	"request unsuccessful. incapsula incident id",
+	"the requested url was rejected. please consult with your administrator",
+	"this request has been blocked by",
+	"mod_security",
+	"web application firewall",
+}
+
+// shouldDetectWAF 判断是否需要检测WAF拦截，--exclude-blocked隐含--detect-waf
+func (s *Scanner) shouldDetectWAF() bool {
+	return s.config.General.DetectWAF || s.config.General.ExcludeBlocked
+}
+
+// isWAFBlocked 判断响应是否疑似为WAF拦截页面：WAF常用状态码，或403/503响应带有拦截页面特征文本或WAF响应头
+// （正常页面也可能提到这些文本或带有这些头，不单独作为依据）
+func isWAFBlocked(result ScanResult) bool {
+	if result.Error != nil {
+		return false
+	}
+	if wafBlockStatuses[result.StatusCode] {
+		return true
+	}
+	if result.StatusCode != http.StatusForbidden && result.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+
+	body := strings.ToLower(result.Body)
+	for _, marker := range wafBodyMarkers {
+		if strings.Contains(body, marker) {
+			return true
+		}
+	}
+
+	for _, header := range wafHeaders {
+		if result.Headers.Get(header) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestDetectWAF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modsec":
+			w.WriteHeader(http.StatusNotAcceptable)
+			io.WriteString(w, "Not Acceptable")
+		case "/ratelimit":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/cf-forbidden":
+			w.Header().Set("CF-RAY", "8a1b2c3d4e5f-AMS")
+			w.WriteHeader(http.StatusForbidden)
+		case "/blockpage":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "<html><title>Attention Required! | Cloudflare</title></html>")
+		case "/article":
+			// 正常页面提到拦截页面的特征文本
+			io.WriteString(w, "<html><p>How to find the Cloudflare Ray ID of a blocked request</p></html>")
+		case "/admin":
+			// 经过CDN的正常页面也带有WAF响应头
+			w.Header().Set("CF-RAY", "8a1b2c3d4e5f-AMS")
+			io.WriteString(w, "<html><title>Admin</title></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	paths := []string{"modsec", "ratelimit", "cf-forbidden", "blockpage", "article", "admin"}
+	wantBlocked := map[string]bool{
+		"modsec":       true,
+		"ratelimit":    true,
+		"cf-forbidden": true,
+		"blockpage":    true,
+		"article":      false,
+		"admin":        false,
+	}
+
+	tests := []struct {
+		name           string
+		excludeBlocked bool
+	}{
+		{name: "只标记", excludeBlocked: false},
+		{name: "标记并排除", excludeBlocked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.General.DetectWAF = true
+			cfg.General.ExcludeBlocked = tt.excludeBlocked
+			s := newTestScanner(t, cfg)
+
+			results, err := s.executeScan([]string{server.URL + "/"}, paths, 0)
+			if err != nil {
+				t.Fatalf("executeScan failed: %v", err)
+			}
+			if len(results) != len(paths) {
+				t.Fatalf("Got %d results, want %d", len(results), len(paths))
+			}
+
+			for _, result := range results {
+				if result.Blocked != wantBlocked[result.Path] {
+					t.Errorf("%s: Blocked = %v, want %v", result.Path, result.Blocked, wantBlocked[result.Path])
+				}
+				if tt.excludeBlocked && result.Blocked && s.isFound(result) {
+					t.Errorf("%s: blocked result reported as found", result.Path)
+				}
+				if result.Path == "admin" && !s.isFound(result) {
+					t.Errorf("admin: normal page behind a CDN not reported as found")
+				}
+			}
+		})
+	}
+}
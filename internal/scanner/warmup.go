@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// warmupURLs 提取每个主机的根路径（按协议+主机去重，保持目标顺序）
+func warmupURLs(targets []string) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, target := range targets {
+		parsed, err := url.Parse(target)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		root := parsed.Scheme + "://" + parsed.Host + "/"
+		if seen[root] {
+			continue
+		}
+		seen[root] = true
+		urls = append(urls, root)
+	}
+	return urls
+}
+
+// warmup 扫描开始前向每个主机发送若干个丢弃结果的请求，预先建立连接池并缓存DNS
+func (s *Scanner) warmup(targets []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("warmup panic recovered: %v", r)
+		}
+	}()
+
+	count := s.config.Connection.Warmup
+	if count <= 0 || s.requester == nil {
+		return
+	}
+
+	urls := warmupURLs(targets)
+	if len(urls) == 0 {
+		return
+	}
+	fmt.Printf("正在预热连接: %d 个主机，每个主机 %d 个请求...\n", len(urls), count)
+
+	workers := s.config.General.Threads
+	if workers <= 0 {
+		workers = 25
+	}
+	sem := make(chan struct{}, workers)
+
+	// 同一主机的预热请求并发发送，以便建立多个可复用的连接
+	var wg sync.WaitGroup
+	for _, root := range urls {
+		for i := 0; i < count; i++ {
+			if s.ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(root string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if _, err := s.requester.RequestMethod(root, http.MethodGet); err != nil {
+					log.Printf("Warning: Warmup request to %s failed: %v", root, err)
+				}
+			}(root)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+)
+
+func TestWarmupBeforeScanTasks(t *testing.T) {
+	var mu sync.Mutex
+	newServer := func(log *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			*log = append(*log, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	var firstLog, secondLog []string
+	first := newServer(&firstLog)
+	defer first.Close()
+	second := newServer(&secondLog)
+	defer second.Close()
+
+	cfg := &config.Config{}
+	cfg.Connection.Warmup = 3
+	s := newTestScanner(t, cfg)
+
+	targets := []string{first.URL + "/", first.URL + "/app/", second.URL + "/"}
+	if _, err := s.executeScan(targets, []string{"admin", "login"}, 0); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	tests := []struct {
+		name     string
+		log      []string
+		expected int // 扫描请求数
+	}{
+		{name: "第一个主机", log: firstLog, expected: 4},
+		{name: "第二个主机", log: secondLog, expected: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.log) != cfg.Connection.Warmup+tt.expected {
+				t.Fatalf("Got requests %v, want %d warmup + %d scan requests", tt.log, cfg.Connection.Warmup, tt.expected)
+			}
+			for i, path := range tt.log {
+				isWarmup := path == "/"
+				if i < cfg.Connection.Warmup && !isWarmup {
+					t.Errorf("Request %d = %q, expected warmup requests before scan tasks: %v", i, path, tt.log)
+				}
+				if i >= cfg.Connection.Warmup && isWarmup {
+					t.Errorf("Unexpected warmup request after scan tasks began: %v", tt.log)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/report"
+)
+
+func TestWebhookDeliversFoundResults(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin", "/login":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer target.Close()
+
+	var mu sync.Mutex
+	var delivered []string
+	attempts := 0
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		// 第一次请求失败，验证重试
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Webhook Authorization header = %q, want %q", got, "Bearer secret")
+		}
+		var payload report.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+			return
+		}
+		delivered = append(delivered, payload.Path)
+	}))
+	defer hook.Close()
+
+	cfg := &config.Config{}
+	cfg.Output.Webhook = hook.URL
+	cfg.Output.WebhookHeaders = []string{"Authorization: Bearer secret"}
+	s := newTestScanner(t, cfg)
+	webhook, err := report.NewWebhookSender(cfg)
+	if err != nil {
+		t.Fatalf("NewWebhookSender failed: %v", err)
+	}
+	s.webhook = webhook
+
+	if _, err := s.executeScan([]string{target.URL + "/"}, []string{"admin", "login", "missing"}, 0); err != nil {
+		t.Fatalf("executeScan failed: %v", err)
+	}
+	s.flushWebhook()
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(delivered)
+	if got := strings.Join(delivered, ","); got != "admin,login" {
+		t.Errorf("Delivered results = %q, want %q", got, "admin,login")
+	}
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dirsearch-go/internal/report"
+)
+
+// State 扫描会话状态。递归队列不单独保存：恢复时已完成路径的结果会重新交给扫描器，
+// 由这些结果重新生成需要递归的目录
+type State struct {
+	Targets   []string            `json:"targets"`
+	Completed map[string][]string `json:"completed"` // 目标 -> 已完成的路径
+	Results   []report.ScanResult `json:"results"`   // 已完成路径中发现或触发递归的结果
+	SavedAt   time.Time           `json:"saved_at"`
+}
+
+// Save 将会话状态写入文件：先写入同目录下的临时文件再重命名，写入中途崩溃不会损坏已有的会话文件
+func Save(path string, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create session file: %w", err)
+	}
+	tmpName := file.Name()
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to sync session file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close session file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace session file: %w", err)
+	}
+	return nil
+}
+
+// Load 读取会话文件
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse session file %s: %w", path, err)
+	}
+	if state.Completed == nil {
+		state.Completed = make(map[string][]string)
+	}
+	return &state, nil
+}
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"dirsearch-go/internal/report"
+)
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scan.session")
+
+	state := &State{
+		Targets:   []string{"http://example.com/"},
+		Completed: map[string][]string{"http://example.com/": {"admin", "login"}},
+		Results: []report.ScanResult{
+			{URL: "http://example.com/", Path: "admin", StatusCode: 200, Title: "Admin"},
+		},
+	}
+	if err := Save(path, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	// 覆盖已有的会话文件
+	state.Completed["http://example.com/"] = append(state.Completed["http://example.com/"], "backup")
+	if err := Save(path, state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Targets, state.Targets) || !reflect.DeepEqual(loaded.Completed, state.Completed) {
+		t.Errorf("Load() = %+v, want %+v", loaded, state)
+	}
+	if len(loaded.Results) != 1 || loaded.Results[0].Title != "Admin" {
+		t.Errorf("Load() results = %+v", loaded.Results)
+	}
+
+	// 写入后不应留下临时文件
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Got files %v, want only the session file", entries)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Load(filepath.Join(dir, "missing.session")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load(missing) error = %v, want fs.ErrNotExist", err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.session")
+	if err := os.WriteFile(corrupt, []byte(`{"targets": [`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := Load(corrupt); err == nil {
+		t.Error("Load(corrupt) succeeded, want error")
+	}
+}
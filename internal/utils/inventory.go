@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// InventoryEntry 目标清单条目
+type InventoryEntry struct {
+	Host   string
+	Port   int
+	Scheme string
+	Tags   []string
+}
+
+// URL 根据协议和端口生成目标URL，未指定协议时443端口使用https，其余使用http
+func (e InventoryEntry) URL() string {
+	scheme := strings.ToLower(e.Scheme)
+	if scheme == "" {
+		scheme = "http"
+		if e.Port == 443 {
+			scheme = "https"
+		}
+	}
+
+	host := strings.Trim(e.Host, "[]")
+	if e.Port > 0 && !(scheme == "http" && e.Port == 80) && !(scheme == "https" && e.Port == 443) {
+		host = net.JoinHostPort(host, strconv.Itoa(e.Port))
+	} else if strings.Contains(host, ":") {
+		// IPv6地址需要方括号
+		host = "[" + host + "]"
+	}
+
+	return scheme + "://" + host
+}
+
+// HasAnyTag 条目是否包含任一标签（不区分大小写），tags为空时返回true
+func (e InventoryEntry) HasAnyTag(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, want := range tags {
+		for _, tag := range e.Tags {
+			if strings.EqualFold(strings.TrimSpace(want), tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadInventoryTargets 从CSV或JSON目标清单读取目标URL，可按标签过滤
+func ReadInventoryTargets(filename string, tags []string) ([]string, error) {
+	entries, err := ReadInventory(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	for _, entry := range entries {
+		if entry.HasAnyTag(tags) {
+			targets = append(targets, entry.URL())
+		}
+	}
+	return targets, nil
+}
+
+// ReadInventory 根据文件扩展名解析CSV或JSON目标清单
+func ReadInventory(filename string) ([]InventoryEntry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory file: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return parseInventoryCSV(file)
+	case ".json":
+		return parseInventoryJSON(file)
+	default:
+		return nil, fmt.Errorf("unsupported inventory format %q (expected .csv or .json)", filepath.Ext(filename))
+	}
+}
+
+// parseInventoryCSV 解析带表头的CSV清单，列：host, port, scheme, tags（标签以;或|分隔）
+func parseInventoryCSV(r io.Reader) ([]InventoryEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["host"]; !ok {
+		return nil, fmt.Errorf("inventory CSV must have a host column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []InventoryEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inventory line %d: %w", line, err)
+		}
+
+		host := field(record, "host")
+		if host == "" || strings.HasPrefix(host, "#") {
+			continue
+		}
+
+		entry := InventoryEntry{
+			Host:   host,
+			Scheme: field(record, "scheme"),
+			Tags:   splitTags(field(record, "tags")),
+		}
+		if port := field(record, "port"); port != "" {
+			entry.Port, err = strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q on inventory line %d", port, line)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// inventoryJSONEntry JSON清单条目，端口可以是数字或字符串，标签可以是数组或字符串
+type inventoryJSONEntry struct {
+	Host   string          `json:"host"`
+	Port   json.RawMessage `json:"port"`
+	Scheme string          `json:"scheme"`
+	Tags   json.RawMessage `json:"tags"`
+}
+
+// parseInventoryJSON 解析JSON数组格式的清单
+func parseInventoryJSON(r io.Reader) ([]InventoryEntry, error) {
+	var raw []inventoryJSONEntry
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory JSON: %w", err)
+	}
+
+	var entries []InventoryEntry
+	for i, item := range raw {
+		if strings.TrimSpace(item.Host) == "" {
+			continue
+		}
+
+		entry := InventoryEntry{
+			Host:   strings.TrimSpace(item.Host),
+			Scheme: strings.TrimSpace(item.Scheme),
+		}
+
+		if len(item.Port) > 0 && string(item.Port) != "null" {
+			port := strings.Trim(string(item.Port), `"`)
+			var err error
+			if entry.Port, err = strconv.Atoi(port); err != nil {
+				return nil, fmt.Errorf("invalid port %s for inventory entry %d", item.Port, i)
+			}
+		}
+
+		if len(item.Tags) > 0 && string(item.Tags) != "null" {
+			var tags []string
+			if err := json.Unmarshal(item.Tags, &tags); err != nil {
+				var tagString string
+				if err := json.Unmarshal(item.Tags, &tagString); err != nil {
+					return nil, fmt.Errorf("invalid tags for inventory entry %d: %w", i, err)
+				}
+				tags = splitTags(tagString)
+			}
+			entry.Tags = tags
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// splitTags 按;、|或,分隔标签
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == '|' || r == ','
+	}) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
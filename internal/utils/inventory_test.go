@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeInventory 写入测试清单文件
+func writeInventory(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write inventory: %v", err)
+	}
+	return path
+}
+
+func TestReadInventoryTargetsCSV(t *testing.T) {
+	path := writeInventory(t, "inventory.csv", `host,port,scheme,tags
+example.com,443,,prod;web
+api.example.com,8443,https,prod|api
+legacy.example.com,80,http,legacy
+dev.example.com,8080,,dev
+`)
+
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{
+			name: "全部目标",
+			expected: []string{
+				"https://example.com",
+				"https://api.example.com:8443",
+				"http://legacy.example.com",
+				"http://dev.example.com:8080",
+			},
+		},
+		{
+			name:     "按标签过滤",
+			tags:     []string{"PROD"},
+			expected: []string{"https://example.com", "https://api.example.com:8443"},
+		},
+		{
+			name:     "多个标签",
+			tags:     []string{"api", "dev"},
+			expected: []string{"https://api.example.com:8443", "http://dev.example.com:8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := ReadInventoryTargets(path, tt.tags)
+			if err != nil {
+				t.Fatalf("ReadInventoryTargets failed: %v", err)
+			}
+			if !reflect.DeepEqual(targets, tt.expected) {
+				t.Errorf("ReadInventoryTargets() = %v, want %v", targets, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadInventoryTargetsJSON(t *testing.T) {
+	path := writeInventory(t, "inventory.json", `[
+  {"host": "example.com", "port": 443, "tags": ["prod", "web"]},
+  {"host": "admin.example.com", "port": "8000", "scheme": "http", "tags": "internal;admin"},
+  {"host": "[::1]", "scheme": "https"},
+  {"host": "::1", "port": 8443},
+  {"host": "", "port": 80}
+]`)
+
+	targets, err := ReadInventoryTargets(path, nil)
+	if err != nil {
+		t.Fatalf("ReadInventoryTargets failed: %v", err)
+	}
+	expected := []string{
+		"https://example.com",
+		"http://admin.example.com:8000",
+		"https://[::1]",
+		"http://[::1]:8443",
+	}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("ReadInventoryTargets() = %v, want %v", targets, expected)
+	}
+
+	filtered, err := ReadInventoryTargets(path, []string{"admin"})
+	if err != nil {
+		t.Fatalf("ReadInventoryTargets failed: %v", err)
+	}
+	if !reflect.DeepEqual(filtered, []string{"http://admin.example.com:8000"}) {
+		t.Errorf("Filtered targets = %v, want [http://admin.example.com:8000]", filtered)
+	}
+}
+
+func TestReadInventoryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "不支持的格式", file: "inventory.txt", content: "example.com\n"},
+		{name: "缺少host列", file: "inventory.csv", content: "name,port\nexample.com,80\n"},
+		{name: "无效端口", file: "inventory.csv", content: "host,port\nexample.com,http\n"},
+		{name: "无效JSON", file: "inventory.json", content: `{"host": "example.com"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadInventory(writeInventory(t, tt.file, tt.content)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// nmapRun nmap XML报告（-oX）中用到的部分
+type nmapRun struct {
+	Hosts []nmapHost `xml:"host"`
+}
+
+// nmapHost nmap报告中的主机
+type nmapHost struct {
+	Status struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr     string `xml:"addr,attr"`
+		AddrType string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []nmapPort `xml:"ports>port"`
+}
+
+// nmapPort nmap报告中的端口
+type nmapPort struct {
+	Protocol string `xml:"protocol,attr"`
+	PortID   int    `xml:"portid,attr"`
+	State    struct {
+		State string `xml:"state,attr"`
+	} `xml:"state"`
+	Service struct {
+		Name   string `xml:"name,attr"`
+		Tunnel string `xml:"tunnel,attr"`
+	} `xml:"service"`
+}
+
+// nmapHTTPServices 视为Web服务的nmap服务名
+var nmapHTTPServices = map[string]bool{
+	"http":       true,
+	"https":      true,
+	"http-alt":   true,
+	"https-alt":  true,
+	"http-proxy": true,
+}
+
+// ParseNmapReport 从nmap XML报告中提取所有开放的HTTP/HTTPS服务，生成scheme://host:port目标URL。
+// 跳过状态不是up的主机和状态不是open的端口，有用户指定的主机名时优先使用主机名
+func ParseNmapReport(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nmap report: %w", err)
+	}
+
+	var run nmapRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse nmap report %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	var targets []string
+	for _, host := range run.Hosts {
+		if host.Status.State != "" && host.Status.State != "up" {
+			continue
+		}
+		name := host.targetName()
+		if name == "" {
+			continue
+		}
+
+		for _, port := range host.Ports {
+			scheme, ok := port.httpScheme()
+			if !ok {
+				continue
+			}
+			entry := InventoryEntry{Host: name, Port: port.PortID, Scheme: scheme}
+			target := entry.URL()
+			if !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// targetName 返回扫描使用的主机名：用户指定的主机名优先，其次是IPv4/IPv6地址
+func (h nmapHost) targetName() string {
+	for _, hostname := range h.Hostnames {
+		if hostname.Type == "user" && hostname.Name != "" {
+			return hostname.Name
+		}
+	}
+	for _, address := range h.Addresses {
+		if address.AddrType == "ipv4" || address.AddrType == "ipv6" {
+			return address.Addr
+		}
+	}
+	return ""
+}
+
+// httpScheme 判断端口是否为开放的HTTP/HTTPS服务并返回协议，ssl/http等经SSL隧道的服务使用https
+func (p nmapPort) httpScheme() (string, bool) {
+	if p.Protocol != "tcp" || p.State.State != "open" || p.PortID <= 0 {
+		return "", false
+	}
+
+	name := strings.ToLower(p.Service.Name)
+	tunnel := strings.ToLower(p.Service.Tunnel)
+	if after, ok := strings.CutPrefix(name, "ssl/"); ok {
+		name, tunnel = after, "ssl"
+	}
+	if !nmapHTTPServices[name] {
+		return "", false
+	}
+
+	if tunnel == "ssl" || strings.HasPrefix(name, "https") {
+		return "https", true
+	}
+	return "http", true
+}
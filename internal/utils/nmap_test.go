@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseNmapReport(t *testing.T) {
+	path := writeInventory(t, "scan.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<nmaprun scanner="nmap" args="nmap -sV -oX scan.xml 10.0.0.0/24">
+<host><status state="up" reason="syn-ack"/>
+<address addr="10.0.0.1" addrtype="ipv4"/>
+<address addr="00:11:22:33:44:55" addrtype="mac"/>
+<hostnames><hostname name="gw.lan" type="PTR"/></hostnames>
+<ports>
+<port protocol="tcp" portid="22"><state state="open"/><service name="ssh"/></port>
+<port protocol="tcp" portid="80"><state state="open"/><service name="http"/></port>
+<port protocol="tcp" portid="443"><state state="open"/><service name="http" tunnel="ssl"/></port>
+<port protocol="tcp" portid="8080"><state state="open"/><service name="http-alt"/></port>
+<port protocol="tcp" portid="8443"><state state="open"/><service name="ssl/http"/></port>
+<port protocol="tcp" portid="8888"><state state="filtered"/><service name="http"/></port>
+<port protocol="udp" portid="80"><state state="open"/><service name="http"/></port>
+</ports></host>
+<host><status state="up"/>
+<address addr="10.0.0.2" addrtype="ipv4"/>
+<hostnames><hostname name="app.example.com" type="user"/></hostnames>
+<ports><port protocol="tcp" portid="9443"><state state="open"/><service name="https"/></port></ports>
+</host>
+<host><status state="down"/>
+<address addr="10.0.0.3" addrtype="ipv4"/>
+<ports><port protocol="tcp" portid="80"><state state="open"/><service name="http"/></port></ports>
+</host>
+<host><status state="up"/>
+<address addr="fe80::1" addrtype="ipv6"/>
+<ports><port protocol="tcp" portid="8000"><state state="open"/><service name="http"/></port></ports>
+</host>
+</nmaprun>
+`)
+
+	targets, err := ParseNmapReport(path)
+	if err != nil {
+		t.Fatalf("ParseNmapReport failed: %v", err)
+	}
+
+	expected := []string{
+		"http://10.0.0.1",
+		"https://10.0.0.1",
+		"http://10.0.0.1:8080",
+		"https://10.0.0.1:8443",
+		"https://app.example.com:9443",
+		"http://[fe80::1]:8000",
+	}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("ParseNmapReport() = %v, want %v", targets, expected)
+	}
+}
+
+func TestParseNmapReportErrors(t *testing.T) {
+	if _, err := ParseNmapReport(filepath.Join(t.TempDir(), "missing.xml")); err == nil {
+		t.Error("ParseNmapReport(missing) succeeded, want error")
+	}
+	if _, err := ParseNmapReport(writeInventory(t, "broken.xml", "<nmaprun><host>")); err == nil {
+		t.Error("ParseNmapReport(broken) succeeded, want error")
+	}
+}
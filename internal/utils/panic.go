@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPanic 从panic中恢复时返回的错误
+var ErrPanic = errors.New("panic recovered")
+
+// PanicError 将recover()得到的值转换为错误，值本身是error时保留错误链
+func PanicError(recovered any) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("%w: %w", ErrPanic, err)
+	}
+	return fmt.Errorf("%w: %v", ErrPanic, recovered)
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestPanicError(t *testing.T) {
+	tests := []struct {
+		name      string
+		recovered any
+		wantErr   error
+	}{
+		{name: "字符串", recovered: "boom"},
+		{name: "错误值", recovered: io.ErrUnexpectedEOF, wantErr: io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PanicError(tt.recovered)
+			if !errors.Is(err, ErrPanic) {
+				t.Errorf("PanicError(%v) = %v, want ErrPanic", tt.recovered, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("PanicError(%v) = %v, want wrapped %v", tt.recovered, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// NewScanID 生成随机的扫描ID（UUID v4），用于关联同一次扫描的日志、报告和webhook事件
+func NewScanID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// 系统随机源不可用时退回到时间戳，仍能区分不同的扫描
+		binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixNano()))
+	}
+	b[6] = b[6]&0x0f | 0x40 // 版本4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122变体
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
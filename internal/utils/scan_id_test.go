@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewScanID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := NewScanID()
+		if !pattern.MatchString(id) {
+			t.Fatalf("NewScanID() = %q, want a UUID v4", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewScanID() returned duplicate %q", id)
+		}
+		seen[id] = true
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"net"
 	"net/url"
 	"strings"
 )
@@ -96,3 +97,37 @@ func EnsureTrailingSlash(path string) string {
 func RemoveTrailingSlash(path string) string {
 	return strings.TrimSuffix(strings.TrimSuffix(path, "/"), "\\")
 }
+
+// ExpandWWW 将域名目标展开为apex和www.两个变体，已带www.的目标、IP地址和单标签主机名保持不变，结果去重并保持顺序
+func ExpandWWW(targets []string) []string {
+	seen := make(map[string]bool)
+	var expanded []string
+	add := func(target string) {
+		if !seen[target] {
+			seen[target] = true
+			expanded = append(expanded, target)
+		}
+	}
+
+	for _, target := range targets {
+		add(target)
+
+		parsed, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+		hostname := parsed.Hostname()
+		if hostname == "" || net.ParseIP(hostname) != nil || !strings.Contains(hostname, ".") {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(hostname), "www.") {
+			continue
+		}
+
+		variant := *parsed
+		variant.Host = "www." + parsed.Host
+		add(variant.String())
+	}
+
+	return expanded
+}
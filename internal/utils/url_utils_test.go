@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandWWW(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []string
+		expected []string
+	}{
+		{
+			name:     "apex展开为两个变体",
+			targets:  []string{"http://example.com"},
+			expected: []string{"http://example.com", "http://www.example.com"},
+		},
+		{
+			name:     "已带www.保持单个",
+			targets:  []string{"https://www.example.com"},
+			expected: []string{"https://www.example.com"},
+		},
+		{
+			name:     "保留端口和路径",
+			targets:  []string{"https://example.com:8443/app"},
+			expected: []string{"https://example.com:8443/app", "https://www.example.com:8443/app"},
+		},
+		{
+			name:     "IP和单标签主机名不展开",
+			targets:  []string{"http://192.168.1.1", "http://localhost:8080"},
+			expected: []string{"http://192.168.1.1", "http://localhost:8080"},
+		},
+		{
+			name:     "同时给出apex和www时去重",
+			targets:  []string{"http://example.com", "http://www.example.com"},
+			expected: []string{"http://example.com", "http://www.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandWWW(tt.targets)
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("ExpandWWW(%v) = %v, want %v", tt.targets, got, tt.expected)
+			}
+		})
+	}
+}
@@ -3,18 +3,57 @@ package view
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 )
 
 // StatusColors 状态码颜色配置
 type StatusColors struct {
-	Success     *color.Color // 2xx
-	Redirect    *color.Color // 3xx
-	ClientError *color.Color // 4xx
-	ServerError *color.Color // 5xx
-	Info        *color.Color // 1xx
-	Default     *color.Color // 其他
+	Success     *color.Color         // 2xx
+	Redirect    *color.Color         // 3xx
+	ClientError *color.Color         // 4xx
+	ServerError *color.Color         // 5xx
+	Info        *color.Color         // 1xx
+	Default     *color.Color         // 其他
+	Codes       map[int]*color.Color // --color-map按状态码覆盖的颜色，优先于上面的分类颜色
+}
+
+// colorNames --color-map可用的颜色名称
+var colorNames = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// ParseColorMap 解析状态码颜色映射，如 "403=yellow,401=cyan"
+func ParseColorMap(spec string) (map[int]*color.Color, error) {
+	colors := make(map[int]*color.Color)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid color mapping %q (expected code=color)", entry)
+		}
+		statusCode, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil || statusCode < 100 || statusCode > 599 {
+			return nil, fmt.Errorf("invalid status code in color mapping %q", entry)
+		}
+		attr, ok := colorNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q in color mapping %q (available: black, red, green, yellow, blue, magenta, cyan, white)", strings.TrimSpace(name), entry)
+		}
+		colors[statusCode] = color.New(attr, color.Bold)
+	}
+	return colors, nil
 }
 
 // ColorManager 颜色管理器
@@ -23,8 +62,8 @@ type ColorManager struct {
 	colors  *StatusColors
 }
 
-// NewColorManager 创建新的颜色管理器
-func NewColorManager(enabled bool) *ColorManager {
+// NewColorManager 创建新的颜色管理器，colorMap按状态码覆盖默认颜色，未指定的状态码使用默认颜色
+func NewColorManager(enabled bool, colorMap map[int]*color.Color) *ColorManager {
 	// 在Windows上强制启用颜色
 	if enabled {
 		color.NoColor = false
@@ -41,6 +80,7 @@ func NewColorManager(enabled bool) *ColorManager {
 			ServerError: color.New(color.FgMagenta, color.Bold),
 			Info:        color.New(color.FgCyan, color.Bold),
 			Default:     color.New(color.FgWhite),
+			Codes:       colorMap,
 		},
 	}
 }
@@ -51,21 +91,26 @@ func (cm *ColorManager) ColorizeStatus(statusCode int) string {
 		return strconv.Itoa(statusCode)
 	}
 
-	// 使用简单的文本颜色标识
+	// 使用简单的文本颜色标识，--color-map指定的状态码再加上对应的颜色
+	var text string
 	switch {
 	case statusCode >= 200 && statusCode < 300:
-		return fmt.Sprintf("✓%d✓", statusCode) // 成功 - 绿色标识
+		text = fmt.Sprintf("✓%d✓", statusCode) // 成功 - 绿色标识
 	case statusCode >= 300 && statusCode < 400:
-		return fmt.Sprintf("→%d→", statusCode) // 重定向 - 黄色标识
+		text = fmt.Sprintf("→%d→", statusCode) // 重定向 - 黄色标识
 	case statusCode >= 400 && statusCode < 500:
-		return fmt.Sprintf("✗%d✗", statusCode) // 客户端错误 - 红色标识
+		text = fmt.Sprintf("✗%d✗", statusCode) // 客户端错误 - 红色标识
 	case statusCode >= 500 && statusCode < 600:
-		return fmt.Sprintf("⚠%d⚠", statusCode) // 服务器错误 - 紫色标识
+		text = fmt.Sprintf("⚠%d⚠", statusCode) // 服务器错误 - 紫色标识
 	case statusCode >= 100 && statusCode < 200:
-		return fmt.Sprintf("ℹ%dℹ", statusCode) // 信息 - 蓝色标识
+		text = fmt.Sprintf("ℹ%dℹ", statusCode) // 信息 - 蓝色标识
 	default:
-		return strconv.Itoa(statusCode)
+		text = strconv.Itoa(statusCode)
+	}
+	if c, ok := cm.colors.Codes[statusCode]; ok {
+		return c.Sprint(text)
 	}
+	return text
 }
 
 // ColorizeURL 为URL添加颜色
@@ -138,6 +183,10 @@ func (cm *ColorManager) GetStatusColor(statusCode int) *color.Color {
 		return color.New()
 	}
 
+	if c, ok := cm.colors.Codes[statusCode]; ok {
+		return c
+	}
+
 	switch {
 	case statusCode >= 200 && statusCode < 300:
 		return cm.colors.Success
@@ -0,0 +1,50 @@
+package view
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColorMap(t *testing.T) {
+	colorMap, err := ParseColorMap("403=yellow, 401=Cyan")
+	if err != nil {
+		t.Fatalf("ParseColorMap failed: %v", err)
+	}
+	cm := NewColorManager(true, colorMap)
+	defaults := NewColorManager(true, nil)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantCode   string // 期望的ANSI前景色代码
+	}{
+		{name: "403改为黄色", statusCode: 403, wantCode: "33"},
+		{name: "401改为青色", statusCode: 401, wantCode: "36"},
+		{name: "未指定的404保持红色", statusCode: 404, wantCode: "31"},
+		{name: "未指定的200保持绿色", statusCode: 200, wantCode: "32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cm.GetStatusColor(tt.statusCode).Sprint("x")
+			if !strings.HasPrefix(got, "\x1b["+tt.wantCode+";") {
+				t.Errorf("GetStatusColor(%d) rendered %q, want color code %s", tt.statusCode, got, tt.wantCode)
+			}
+		})
+	}
+
+	if got, want := cm.GetStatusColor(403).Sprint("x"), defaults.GetStatusColor(403).Sprint("x"); got == want {
+		t.Errorf("Expected --color-map to change the color of 403, both rendered %q", got)
+	}
+	if got := cm.ColorizeStatus(403); !strings.Contains(got, "\x1b[33;") {
+		t.Errorf("ColorizeStatus(403) = %q, expected the mapped color", got)
+	}
+}
+
+func TestParseColorMapInvalid(t *testing.T) {
+	for _, spec := range []string{"403", "abc=red", "403=purple", "999=red"} {
+		if _, err := ParseColorMap(spec); err == nil {
+			t.Errorf("ParseColorMap(%q) expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package view
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"dirsearch-go/internal/report"
+)
+
+// directoryTree 根据IsDirectory结果构建目录树：按主机分组、去重排序，并按路径深度缩进
+func directoryTree(results []report.ScanResult) string {
+	hosts := make(map[string]map[string][]string)
+	for _, result := range results {
+		if !result.IsDirectory || result.Error != nil {
+			continue
+		}
+		fullURL := strings.TrimSuffix(result.URL, "/") + "/" + strings.Trim(result.Path, "/")
+		parsed, err := url.Parse(fullURL)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		root := parsed.Scheme + "://" + parsed.Host + "/"
+		dir := strings.Trim(parsed.Path, "/")
+		if dir == "" {
+			continue
+		}
+		if hosts[root] == nil {
+			hosts[root] = make(map[string][]string)
+		}
+		hosts[root][dir] = strings.Split(dir, "/")
+	}
+
+	roots := make([]string, 0, len(hosts))
+	for root := range hosts {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+
+	var b strings.Builder
+	for _, root := range roots {
+		dirs := make([][]string, 0, len(hosts[root]))
+		for _, segments := range hosts[root] {
+			dirs = append(dirs, segments)
+		}
+		// 按路径段比较排序，保证子目录紧跟在父目录之后
+		sort.Slice(dirs, func(i, j int) bool {
+			x, y := dirs[i], dirs[j]
+			for k := 0; k < len(x) && k < len(y); k++ {
+				if x[k] != y[k] {
+					return x[k] < y[k]
+				}
+			}
+			return len(x) < len(y)
+		})
+
+		b.WriteString(root + "\n")
+		printed := make(map[string]bool)
+		for _, segments := range dirs {
+			// 未单独发现的父目录也补充显示，保持树结构完整
+			for depth := range segments {
+				prefix := strings.Join(segments[:depth+1], "/")
+				if printed[prefix] {
+					continue
+				}
+				printed[prefix] = true
+				b.WriteString(strings.Repeat("  ", depth+1) + segments[depth] + "/\n")
+			}
+		}
+	}
+	return b.String()
+}
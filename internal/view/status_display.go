@@ -12,27 +12,58 @@ import (
 
 // StatusDisplay 状态显示器
 type StatusDisplay struct {
-	config     *config.Config
-	mu         sync.RWMutex
-	startTime  time.Time
-	totalPaths int
-	scanned    int
-	found      int
-	errors     int
-	status     map[int]int // 状态码统计
-	lastUpdate time.Time
+	config        *config.Config
+	includeStatus map[int]bool // 视为"发现"的状态码，为空时使用默认的200-399
+	excludeStatus map[int]bool // 不视为"发现"的状态码
+	mu            sync.RWMutex
+	startTime     time.Time
+	totalPaths    int
+	scanned       int
+	found         int
+	errors        int
+	status        map[int]int // 状态码统计
+	lastUpdate    time.Time
+	timeLimit     time.Duration // 因--max-time提前结束时的时间限制，为0表示扫描正常结束
 }
 
 // NewStatusDisplay 创建新的状态显示器
 func NewStatusDisplay(cfg *config.Config) *StatusDisplay {
 	return &StatusDisplay{
-		config:     cfg,
-		status:     make(map[int]int),
-		startTime:  time.Now(),
-		lastUpdate: time.Now(),
+		config:        cfg,
+		includeStatus: parseStatusSet(cfg.General.IncludeStatus),
+		excludeStatus: parseStatusSet(cfg.General.ExcludeStatus),
+		status:        make(map[int]int),
+		startTime:     time.Now(),
+		lastUpdate:    time.Now(),
 	}
 }
 
+// parseStatusSet 将状态码配置解析为集合
+func parseStatusSet(statusList []string) map[int]bool {
+	set := make(map[int]bool)
+	for _, statusStr := range statusList {
+		codes, err := config.ParseStatusCodes(statusStr)
+		if err != nil {
+			continue
+		}
+		for _, code := range codes {
+			set[code] = true
+		}
+	}
+	return set
+}
+
+// isFound 判断状态码是否计为"发现"，与include-status/exclude-status配置保持一致
+func (sd *StatusDisplay) isFound(statusCode int) bool {
+	if sd.excludeStatus[statusCode] {
+		return false
+	}
+	if len(sd.includeStatus) > 0 {
+		return sd.includeStatus[statusCode]
+	}
+	return statusCode >= 200 && statusCode < 400
+}
+
 // SetTotalPaths 设置总路径数
 func (sd *StatusDisplay) SetTotalPaths(total int) {
 	sd.mu.Lock()
@@ -51,7 +82,7 @@ func (sd *StatusDisplay) UpdateProgress(result report.ScanResult) {
 		sd.errors++
 	} else {
 		sd.status[result.StatusCode]++
-		if result.StatusCode >= 200 && result.StatusCode < 400 {
+		if sd.isFound(result.StatusCode) {
 			sd.found++
 		}
 	}
@@ -63,13 +94,38 @@ func (sd *StatusDisplay) UpdateProgress(result report.ScanResult) {
 	}
 }
 
+// Scanned 返回已计入进度的结果数量
+func (sd *StatusDisplay) Scanned() int {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	return sd.scanned
+}
+
+// SetTimeLimited 标记扫描因--max-time提前结束，最终结果中会注明
+func (sd *StatusDisplay) SetTimeLimited(limit time.Duration) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	sd.timeLimit = limit
+}
+
+// TimeLimited 返回扫描是否因--max-time提前结束
+func (sd *StatusDisplay) TimeLimited() bool {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+	return sd.timeLimit > 0
+}
+
 // DisplayFinalResults 显示最终结果
 func (sd *StatusDisplay) DisplayFinalResults(results []report.ScanResult) {
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
 
 	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("扫描完成!")
+	if sd.timeLimit > 0 {
+		fmt.Printf("扫描已达到最长时间 %s，提前结束 (结果不完整)\n", sd.timeLimit)
+	} else {
+		fmt.Println("扫描完成!")
+	}
 	fmt.Println(strings.Repeat("=", 50))
 
 	elapsed := time.Since(sd.startTime)
@@ -85,9 +141,27 @@ func (sd *StatusDisplay) DisplayFinalResults(results []report.ScanResult) {
 		}
 	}
 
+	// 显示发现的目录结构
+	if tree := directoryTree(results); tree != "" {
+		fmt.Println("\n发现的目录:")
+		fmt.Print(tree)
+	}
+
 	fmt.Println(strings.Repeat("=", 50))
 }
 
+// CISummary 返回供CI解析的单行扫描摘要，如 "RESULT found=12 scanned=10000 errors=3 duration=42.1s"
+func (sd *StatusDisplay) CISummary() string {
+	sd.mu.RLock()
+	defer sd.mu.RUnlock()
+	return sd.ciSummary(time.Since(sd.startTime))
+}
+
+// ciSummary 按指定耗时生成CI摘要，调用方需持有锁
+func (sd *StatusDisplay) ciSummary(elapsed time.Duration) string {
+	return fmt.Sprintf("RESULT found=%d scanned=%d errors=%d duration=%.1fs", sd.found, sd.scanned, sd.errors, elapsed.Seconds())
+}
+
 // displayProgress 显示进度
 func (sd *StatusDisplay) displayProgress() {
 	if sd.totalPaths == 0 {
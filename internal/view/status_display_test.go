@@ -0,0 +1,110 @@
+package view
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"dirsearch-go/internal/config"
+	"dirsearch-go/internal/report"
+)
+
+func TestUpdateProgressFoundFollowsIncludeStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		includeStatus []string
+		excludeStatus []string
+		statusCodes   []int
+		expected      int
+	}{
+		{
+			name:          "include-status为403时只统计403",
+			includeStatus: []string{"403"},
+			statusCodes:   []int{403, 200},
+			expected:      1,
+		},
+		{
+			name:        "默认统计200-399",
+			statusCodes: []int{200, 301, 403, 404},
+			expected:    2,
+		},
+		{
+			name:          "exclude-status优先",
+			excludeStatus: []string{"301"},
+			statusCodes:   []int{200, 301},
+			expected:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				General: config.GeneralConfig{
+					IncludeStatus: tt.includeStatus,
+					ExcludeStatus: tt.excludeStatus,
+				},
+			}
+			sd := NewStatusDisplay(cfg)
+			for _, code := range tt.statusCodes {
+				sd.UpdateProgress(report.ScanResult{StatusCode: code})
+			}
+			if sd.found != tt.expected {
+				t.Errorf("found = %d, want %d", sd.found, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCISummary(t *testing.T) {
+	sd := NewStatusDisplay(&config.Config{})
+	for _, result := range []report.ScanResult{
+		{StatusCode: 200},
+		{StatusCode: 301},
+		{StatusCode: 404},
+		{StatusCode: 404},
+		{Error: errors.New("timeout")},
+	} {
+		sd.UpdateProgress(result)
+	}
+
+	if got, want := sd.ciSummary(42*time.Second+120*time.Millisecond), "RESULT found=2 scanned=5 errors=1 duration=42.1s"; got != want {
+		t.Errorf("ciSummary() = %q, want %q", got, want)
+	}
+
+	pattern := regexp.MustCompile(`^RESULT found=\d+ scanned=\d+ errors=\d+ duration=\d+\.\ds$`)
+	if got := sd.CISummary(); !pattern.MatchString(got) {
+		t.Errorf("CISummary() = %q, want a line matching %s", got, pattern)
+	}
+}
+
+func TestDirectoryTree(t *testing.T) {
+	results := []report.ScanResult{
+		{URL: "https://example.com/", Path: "admin/", IsDirectory: true},
+		{URL: "https://example.com/admin/", Path: "users/", IsDirectory: true},
+		{URL: "https://example.com/admin/", Path: "users/", IsDirectory: true},
+		{URL: "https://example.com/", Path: "admin-old", IsDirectory: true},
+		{URL: "https://example.com/admin/users/", Path: "profile", IsDirectory: true},
+		{URL: "https://example.com/", Path: "api/v1/", IsDirectory: true},
+		{URL: "https://example.com/", Path: "index.php"},
+		{URL: "https://cdn.example.com/", Path: "static/", IsDirectory: true},
+	}
+
+	expected := `https://cdn.example.com/
+  static/
+https://example.com/
+  admin/
+    users/
+      profile/
+  admin-old/
+  api/
+    v1/
+`
+	if got := directoryTree(results); got != expected {
+		t.Errorf("directoryTree() =\n%s\nwant:\n%s", got, expected)
+	}
+
+	if got := directoryTree([]report.ScanResult{{URL: "https://example.com/", Path: "index.php"}}); got != "" {
+		t.Errorf("Expected empty tree without directories, got %q", got)
+	}
+}
@@ -5,19 +5,13 @@ import (
 	"os"
 
 	"dirsearch-go/internal/cmd"
-	"dirsearch-go/internal/config"
 )
 
 func main() {
-	// 初始化配置
-	if err := config.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize config: %v\n", err)
-		os.Exit(1)
-	}
-
-	// 运行命令行程序
+	// 运行命令行程序（配置在解析命令行参数后初始化，以支持--no-config）
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cmd.ExitError)
 	}
+	os.Exit(cmd.ExitCode())
 }